@@ -0,0 +1,56 @@
+package collections
+
+import "time"
+
+// BatchChannel groups items from in into batches of at most maxSize items. A batch is
+// emitted when it is full or maxWait after its first item arrived (maxWait <= 0 = size only).
+// The remaining items are flushed and the returned channel is closed when in is closed.
+func BatchChannel[T any](in <-chan T, maxSize int, maxWait time.Duration) <-chan []T {
+	if maxSize <= 0 {
+		maxSize = 1
+	}
+	out := make(chan []T)
+
+	go func() {
+		defer close(out)
+
+		batch := make([]T, 0, maxSize)
+		var timer *time.Timer
+		var timeout <-chan time.Time
+
+		flush := func() {
+			if timer != nil {
+				timer.Stop()
+				timer, timeout = nil, nil
+			}
+			if len(batch) == 0 {
+				return
+			}
+			out <- batch
+			batch = make([]T, 0, maxSize)
+		}
+
+		for {
+			select {
+			case item, ok := <-in:
+				if !ok {
+					flush()
+					return
+				}
+				batch = append(batch, item)
+				if len(batch) == 1 && maxWait > 0 {
+					timer = time.NewTimer(maxWait)
+					timeout = timer.C
+				}
+				if len(batch) >= maxSize {
+					flush()
+				}
+			case <-timeout:
+				timer, timeout = nil, nil
+				flush()
+			}
+		}
+	}()
+
+	return out
+}
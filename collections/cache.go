@@ -0,0 +1,327 @@
+package collections
+
+import (
+	"sync"
+	"time"
+)
+
+// EvictionReason tells an eviction callback why an entry left the cache
+type EvictionReason int
+
+const (
+	// EvictionCapacity means the entry was the least recently used one of a full cache
+	EvictionCapacity EvictionReason = iota
+	// EvictionExpired means the entry's TTL passed
+	EvictionExpired
+	// EvictionDeleted means the entry was removed via Delete or Purge
+	EvictionDeleted
+)
+
+// String returns the reason name
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictionCapacity:
+		return "capacity"
+	case EvictionExpired:
+		return "expired"
+	case EvictionDeleted:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+// CacheStats holds cache hit/miss counters
+type CacheStats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // Capacity and expiry evictions
+}
+
+// HitRate returns the ratio of hits to lookups (0 when there were no lookups)
+func (s CacheStats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// cacheEntry is a node of the LRU recency list
+type cacheEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	expiresAt  time.Time // zero = never expires
+	prev, next *cacheEntry[K, V]
+}
+
+// evicted is an entry removed under the lock whose callback runs after unlocking
+type evicted[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+// LRU is a concurrency-safe least-recently-used cache with optional entry expiry
+type LRU[K comparable, V any] struct {
+	mu         sync.Mutex
+	capacity   int
+	ttl        time.Duration
+	entries    map[K]*cacheEntry[K, V]
+	head, tail *cacheEntry[K, V] // head = most recently used
+	onEvict    func(key K, value V, reason EvictionReason)
+	stats      CacheStats
+}
+
+// NewLRU creates an LRU cache holding at most capacity entries (capacity <= 0 = unbounded)
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		entries:  make(map[K]*cacheEntry[K, V]),
+	}
+}
+
+// NewTTLCache creates an LRU cache whose entries expire ttl after being set
+// (capacity <= 0 = unbounded). Expired entries are dropped lazily on access
+// or in bulk via DeleteExpired.
+func NewTTLCache[K comparable, V any](capacity int, ttl time.Duration) *LRU[K, V] {
+	c := NewLRU[K, V](capacity)
+	c.ttl = ttl
+	return c
+}
+
+// OnEvict sets a callback invoked (outside the cache lock) whenever an entry is removed
+func (c *LRU[K, V]) OnEvict(fn func(key K, value V, reason EvictionReason)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onEvict = fn
+}
+
+// Get returns the value for a key and marks it as recently used
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	var removed []evicted[K, V]
+	if ok && c.expired(entry) {
+		removed = append(removed, c.remove(entry, EvictionExpired))
+		ok = false
+	}
+
+	var value V
+	if ok {
+		c.stats.Hits++
+		c.moveToFront(entry)
+		value = entry.value
+	} else {
+		c.stats.Misses++
+	}
+	c.mu.Unlock()
+
+	c.notify(removed)
+	return value, ok
+}
+
+// Peek returns the value for a key without updating recency or stats
+func (c *LRU[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || c.expired(entry) {
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// Set adds or updates an entry using the cache's default TTL
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL adds or updates an entry that expires after ttl (0 = never)
+func (c *LRU[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	var removed []evicted[K, V]
+	if entry, ok := c.entries[key]; ok {
+		entry.value = value
+		entry.expiresAt = expiresAt
+		c.moveToFront(entry)
+	} else {
+		entry := &cacheEntry[K, V]{key: key, value: value, expiresAt: expiresAt}
+		c.entries[key] = entry
+		c.pushFront(entry)
+		for c.capacity > 0 && len(c.entries) > c.capacity {
+			removed = append(removed, c.remove(c.tail, EvictionCapacity))
+		}
+	}
+	c.mu.Unlock()
+
+	c.notify(removed)
+}
+
+// GetOrLoad returns the cached value or stores and returns the result of load.
+// Concurrent misses for the same key may each call load.
+func (c *LRU[K, V]) GetOrLoad(key K, load func(K) (V, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+	value, err := load(key)
+	if err != nil {
+		return value, err
+	}
+	c.Set(key, value)
+	return value, nil
+}
+
+// Delete removes an entry, reporting whether it was present
+func (c *LRU[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	var removed []evicted[K, V]
+	if ok {
+		removed = append(removed, c.remove(entry, EvictionDeleted))
+	}
+	c.mu.Unlock()
+
+	c.notify(removed)
+	return ok
+}
+
+// DeleteExpired removes all expired entries and returns how many were removed
+func (c *LRU[K, V]) DeleteExpired() int {
+	c.mu.Lock()
+	var removed []evicted[K, V]
+	for entry := c.head; entry != nil; {
+		next := entry.next
+		if c.expired(entry) {
+			removed = append(removed, c.remove(entry, EvictionExpired))
+		}
+		entry = next
+	}
+	c.mu.Unlock()
+
+	c.notify(removed)
+	return len(removed)
+}
+
+// Purge removes all entries
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	removed := make([]evicted[K, V], 0, len(c.entries))
+	for entry := c.head; entry != nil; entry = entry.next {
+		removed = append(removed, evicted[K, V]{key: entry.key, value: entry.value, reason: EvictionDeleted})
+	}
+	c.entries = make(map[K]*cacheEntry[K, V])
+	c.head, c.tail = nil, nil
+	c.mu.Unlock()
+
+	c.notify(removed)
+}
+
+// Len returns the number of entries (including expired entries not yet removed)
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Keys returns the keys of unexpired entries, most recently used first
+func (c *LRU[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]K, 0, len(c.entries))
+	for entry := c.head; entry != nil; entry = entry.next {
+		if !c.expired(entry) {
+			keys = append(keys, entry.key)
+		}
+	}
+	return keys
+}
+
+// Stats returns a snapshot of the hit/miss counters
+func (c *LRU[K, V]) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// ResetStats resets the hit/miss counters
+func (c *LRU[K, V]) ResetStats() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = CacheStats{}
+}
+
+// expired checks if an entry's TTL passed; callers must hold the lock
+func (c *LRU[K, V]) expired(entry *cacheEntry[K, V]) bool {
+	return !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt)
+}
+
+// pushFront inserts an entry as most recently used; callers must hold the lock
+func (c *LRU[K, V]) pushFront(entry *cacheEntry[K, V]) {
+	entry.prev = nil
+	entry.next = c.head
+	if c.head != nil {
+		c.head.prev = entry
+	}
+	c.head = entry
+	if c.tail == nil {
+		c.tail = entry
+	}
+}
+
+// unlink removes an entry from the recency list; callers must hold the lock
+func (c *LRU[K, V]) unlink(entry *cacheEntry[K, V]) {
+	if entry.prev != nil {
+		entry.prev.next = entry.next
+	} else {
+		c.head = entry.next
+	}
+	if entry.next != nil {
+		entry.next.prev = entry.prev
+	} else {
+		c.tail = entry.prev
+	}
+	entry.prev, entry.next = nil, nil
+}
+
+// moveToFront marks an entry as most recently used; callers must hold the lock
+func (c *LRU[K, V]) moveToFront(entry *cacheEntry[K, V]) {
+	if c.head == entry {
+		return
+	}
+	c.unlink(entry)
+	c.pushFront(entry)
+}
+
+// remove deletes an entry and records the eviction; callers must hold the lock
+func (c *LRU[K, V]) remove(entry *cacheEntry[K, V], reason EvictionReason) evicted[K, V] {
+	c.unlink(entry)
+	delete(c.entries, entry.key)
+	if reason != EvictionDeleted {
+		c.stats.Evictions++
+	}
+	return evicted[K, V]{key: entry.key, value: entry.value, reason: reason}
+}
+
+// notify runs the eviction callback for removed entries; must be called without the lock
+func (c *LRU[K, V]) notify(removed []evicted[K, V]) {
+	if len(removed) == 0 {
+		return
+	}
+	c.mu.Lock()
+	onEvict := c.onEvict
+	c.mu.Unlock()
+
+	if onEvict == nil {
+		return
+	}
+	for _, e := range removed {
+		onEvict(e.key, e.value, e.reason)
+	}
+}
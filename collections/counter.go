@@ -0,0 +1,120 @@
+package collections
+
+import "sort"
+
+// Counter is a multiset tallying occurrences of elements. Not safe for concurrent use.
+type Counter[T comparable] struct {
+	counts map[T]int
+	order  map[T]int // first-seen sequence, used to break ties deterministically
+	seq    int
+}
+
+// NewCounter creates a counter tallying the given elements
+func NewCounter[T comparable](elements ...T) *Counter[T] {
+	c := &Counter[T]{counts: make(map[T]int), order: make(map[T]int)}
+	c.Add(elements...)
+	return c
+}
+
+// CounterBy creates a counter tallying the keys of slice elements (like CountBy)
+func CounterBy[T any, K comparable](slice []T, keyFunc func(T) K) *Counter[K] {
+	c := NewCounter[K]()
+	for _, item := range slice {
+		c.Add(keyFunc(item))
+	}
+	return c
+}
+
+// Add increments the count of each element by one
+func (c *Counter[T]) Add(elements ...T) {
+	for _, element := range elements {
+		c.AddN(element, 1)
+	}
+}
+
+// AddN changes the count of an element by n; elements dropping to zero or below are removed
+func (c *Counter[T]) AddN(element T, n int) {
+	if c.counts == nil {
+		c.counts = make(map[T]int)
+		c.order = make(map[T]int)
+	}
+	if _, ok := c.order[element]; !ok {
+		c.order[element] = c.seq
+		c.seq++
+	}
+
+	count := c.counts[element] + n
+	if count <= 0 {
+		delete(c.counts, element)
+		delete(c.order, element)
+		return
+	}
+	c.counts[element] = count
+}
+
+// Count returns the count of an element (0 if absent)
+func (c *Counter[T]) Count(element T) int {
+	return c.counts[element]
+}
+
+// Total returns the sum of all counts
+func (c *Counter[T]) Total() int {
+	total := 0
+	for _, count := range c.counts {
+		total += count
+	}
+	return total
+}
+
+// Len returns the number of distinct elements
+func (c *Counter[T]) Len() int {
+	return len(c.counts)
+}
+
+// MostCommon returns the n elements with the highest counts, highest first
+// (n <= 0 = all elements). Ties keep the order in which elements were first added.
+func (c *Counter[T]) MostCommon(n int) []KeyValue[T, int] {
+	result := make([]KeyValue[T, int], 0, len(c.counts))
+	for element, count := range c.counts {
+		result = append(result, KeyValue[T, int]{Key: element, Value: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value != result[j].Value {
+			return result[i].Value > result[j].Value
+		}
+		return c.order[result[i].Key] < c.order[result[j].Key]
+	})
+
+	if n > 0 && n < len(result) {
+		result = result[:n]
+	}
+	return result
+}
+
+// Merge adds the counts of other to this counter
+func (c *Counter[T]) Merge(other *Counter[T]) {
+	for _, kv := range other.MostCommon(0) {
+		c.AddN(kv.Key, kv.Value)
+	}
+}
+
+// Subtract removes the counts of other from this counter; elements dropping to zero or below are removed
+func (c *Counter[T]) Subtract(other *Counter[T]) {
+	for element, count := range other.counts {
+		if _, ok := c.counts[element]; ok {
+			c.AddN(element, -count)
+		}
+	}
+}
+
+// Remove deletes an element regardless of its count
+func (c *Counter[T]) Remove(element T) {
+	delete(c.counts, element)
+	delete(c.order, element)
+}
+
+// ToMap returns a copy of the counts
+func (c *Counter[T]) ToMap() map[T]int {
+	return CloneMap(c.counts)
+}
@@ -0,0 +1,74 @@
+package collections
+
+import "reflect"
+
+// Change holds the old and new version of an element with the same key
+type Change[T any] struct {
+	Old T
+	New T
+}
+
+// DiffResult holds the edit operations turning one slice into another
+type DiffResult[T any] struct {
+	Added     []T         // Keys only present in the new slice
+	Removed   []T         // Keys only present in the old slice
+	Updated   []Change[T] // Keys present in both with unequal elements
+	Unchanged []T         // Keys present in both with equal elements
+}
+
+// HasChanges checks if anything was added, removed or updated
+func (d DiffResult[T]) HasChanges() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.Updated) > 0
+}
+
+// DiffDetailed compares two slices by key, e.g. for sync and reconciliation jobs.
+// Elements with the same key are compared with equal (nil = reflect.DeepEqual).
+// Results keep input order (new order for Added/Updated/Unchanged, old order for Removed);
+// with duplicate keys the last element wins.
+func DiffDetailed[T any, K comparable](oldSlice, newSlice []T, keyFunc func(T) K, equal func(a, b T) bool) DiffResult[T] {
+	if equal == nil {
+		equal = func(a, b T) bool { return reflect.DeepEqual(a, b) }
+	}
+
+	oldByKey := KeyBy(oldSlice, keyFunc)
+	newByKey := KeyBy(newSlice, keyFunc)
+
+	result := DiffResult[T]{
+		Added:     make([]T, 0),
+		Removed:   make([]T, 0),
+		Updated:   make([]Change[T], 0),
+		Unchanged: make([]T, 0),
+	}
+
+	seen := NewSet[K]()
+	for _, item := range newSlice {
+		key := keyFunc(item)
+		if seen.Contains(key) {
+			continue
+		}
+		seen.Add(key)
+
+		current := newByKey[key]
+		previous, existed := oldByKey[key]
+		switch {
+		case !existed:
+			result.Added = append(result.Added, current)
+		case equal(previous, current):
+			result.Unchanged = append(result.Unchanged, current)
+		default:
+			result.Updated = append(result.Updated, Change[T]{Old: previous, New: current})
+		}
+	}
+
+	removed := NewSet[K]()
+	for _, item := range oldSlice {
+		key := keyFunc(item)
+		if _, exists := newByKey[key]; exists || removed.Contains(key) {
+			continue
+		}
+		removed.Add(key)
+		result.Removed = append(result.Removed, oldByKey[key])
+	}
+
+	return result
+}
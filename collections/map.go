@@ -185,6 +185,26 @@ func GroupBy[T any, K comparable](slice []T, keyFunc func(T) K) map[K][]T {
 	return result
 }
 
+// KeyBy indexes slice elements by key; later elements override earlier ones with the same key
+func KeyBy[T any, K comparable](slice []T, keyFunc func(T) K) map[K]T {
+	result := make(map[K]T, len(slice))
+	for _, item := range slice {
+		result[keyFunc(item)] = item
+	}
+	return result
+}
+
+// Associate builds a map from key-value pairs returned by fn for each element;
+// later elements override earlier ones with the same key
+func Associate[T any, K comparable, V any](slice []T, fn func(T) (K, V)) map[K]V {
+	result := make(map[K]V, len(slice))
+	for _, item := range slice {
+		key, value := fn(item)
+		result[key] = value
+	}
+	return result
+}
+
 // CountBy counts slice elements by the result of the key function
 func CountBy[T any, K comparable](slice []T, keyFunc func(T) K) map[K]int {
 	result := make(map[K]int)
@@ -0,0 +1,258 @@
+package collections
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"reflect"
+)
+
+// orderedEntry is a node of the OrderedMap's insertion-order list
+type orderedEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	prev, next *orderedEntry[K, V]
+}
+
+// OrderedMap is a map that preserves insertion order for iteration and JSON encoding.
+// Updating an existing key keeps its position. The zero value is an empty map ready to use.
+type OrderedMap[K comparable, V any] struct {
+	entries    map[K]*orderedEntry[K, V]
+	head, tail *orderedEntry[K, V]
+}
+
+// NewOrderedMap creates an empty ordered map
+func NewOrderedMap[K comparable, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{entries: make(map[K]*orderedEntry[K, V])}
+}
+
+// Set sets the value for a key, appending the key if it is new
+func (m *OrderedMap[K, V]) Set(key K, value V) {
+	if m.entries == nil {
+		m.entries = make(map[K]*orderedEntry[K, V])
+	}
+	if entry, ok := m.entries[key]; ok {
+		entry.value = value
+		return
+	}
+
+	entry := &orderedEntry[K, V]{key: key, value: value, prev: m.tail}
+	if m.tail != nil {
+		m.tail.next = entry
+	} else {
+		m.head = entry
+	}
+	m.tail = entry
+	m.entries[key] = entry
+}
+
+// Get returns the value for a key
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	if entry, ok := m.entries[key]; ok {
+		return entry.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// GetOrDefault returns the value for a key or defaultValue if it is missing
+func (m *OrderedMap[K, V]) GetOrDefault(key K, defaultValue V) V {
+	if value, ok := m.Get(key); ok {
+		return value
+	}
+	return defaultValue
+}
+
+// Has checks if the map contains a key
+func (m *OrderedMap[K, V]) Has(key K) bool {
+	_, ok := m.entries[key]
+	return ok
+}
+
+// Delete removes a key, reporting whether it was present
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	entry, ok := m.entries[key]
+	if !ok {
+		return false
+	}
+
+	if entry.prev != nil {
+		entry.prev.next = entry.next
+	} else {
+		m.head = entry.next
+	}
+	if entry.next != nil {
+		entry.next.prev = entry.prev
+	} else {
+		m.tail = entry.prev
+	}
+	delete(m.entries, key)
+	return true
+}
+
+// Len returns the number of entries
+func (m *OrderedMap[K, V]) Len() int {
+	return len(m.entries)
+}
+
+// Keys returns the keys in insertion order
+func (m *OrderedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, len(m.entries))
+	for entry := m.head; entry != nil; entry = entry.next {
+		keys = append(keys, entry.key)
+	}
+	return keys
+}
+
+// Values returns the values in insertion order
+func (m *OrderedMap[K, V]) Values() []V {
+	values := make([]V, 0, len(m.entries))
+	for entry := m.head; entry != nil; entry = entry.next {
+		values = append(values, entry.value)
+	}
+	return values
+}
+
+// Entries returns the key-value pairs in insertion order
+func (m *OrderedMap[K, V]) Entries() []KeyValue[K, V] {
+	entries := make([]KeyValue[K, V], 0, len(m.entries))
+	for entry := m.head; entry != nil; entry = entry.next {
+		entries = append(entries, KeyValue[K, V]{Key: entry.key, Value: entry.value})
+	}
+	return entries
+}
+
+// ForEach calls fn for every entry in insertion order
+func (m *OrderedMap[K, V]) ForEach(fn func(K, V)) {
+	for entry := m.head; entry != nil; entry = entry.next {
+		fn(entry.key, entry.value)
+	}
+}
+
+// All returns an iterator over the entries in insertion order
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for entry := m.head; entry != nil; entry = entry.next {
+			if !yield(entry.key, entry.value) {
+				return
+			}
+		}
+	}
+}
+
+// ToMap returns the entries as a regular (unordered) map
+func (m *OrderedMap[K, V]) ToMap() map[K]V {
+	result := make(map[K]V, len(m.entries))
+	for key, entry := range m.entries {
+		result[key] = entry.value
+	}
+	return result
+}
+
+// MarshalJSON encodes the map as a JSON object with keys in insertion order
+func (m OrderedMap[K, V]) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for entry := m.head; entry != nil; entry = entry.next {
+		if entry != m.head {
+			buf.WriteByte(',')
+		}
+
+		keyString, err := orderedMapKeyString(entry.key)
+		if err != nil {
+			return nil, err
+		}
+		key, err := json.Marshal(keyString)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(entry.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value for key %s: %w", key, err)
+		}
+
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes a JSON object, keeping the key order of the document
+func (m *OrderedMap[K, V]) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("ordered map must be a JSON object")
+	}
+
+	m.entries = make(map[K]*orderedEntry[K, V])
+	m.head, m.tail = nil, nil
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		keyString, ok := token.(string)
+		if !ok {
+			return fmt.Errorf("ordered map key must be a string")
+		}
+
+		key, err := parseOrderedMapKey[K](keyString)
+		if err != nil {
+			return err
+		}
+
+		var value V
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("failed to unmarshal value for key %q: %w", keyString, err)
+		}
+		m.Set(key, value)
+	}
+
+	_, err = decoder.Token()
+	return err
+}
+
+// orderedMapKeyString converts a key to its JSON object key, following encoding/json rules
+func orderedMapKeyString[K comparable](key K) (string, error) {
+	if tm, ok := any(key).(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	v := reflect.ValueOf(key)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fmt.Sprint(key), nil
+	default:
+		return "", fmt.Errorf("unsupported ordered map key type %T", key)
+	}
+}
+
+// parseOrderedMapKey converts a JSON object key back to K
+func parseOrderedMapKey[K comparable](s string) (K, error) {
+	var key K
+	if tu, ok := any(&key).(encoding.TextUnmarshaler); ok {
+		err := tu.UnmarshalText([]byte(s))
+		return key, err
+	}
+	v := reflect.ValueOf(&key).Elem()
+	if v.Kind() == reflect.String {
+		v.SetString(s)
+		return key, nil
+	}
+	if err := json.Unmarshal([]byte(s), &key); err != nil {
+		return key, fmt.Errorf("invalid ordered map key %q: %w", s, err)
+	}
+	return key, nil
+}
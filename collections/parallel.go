@@ -0,0 +1,60 @@
+package collections
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/kerimovok/go-pkg-utils/errors"
+)
+
+// ForEachParallel calls fn for every element using at most maxWorkers goroutines
+// (maxWorkers <= 0 = runtime.NumCPU()). Every element is processed even if some fail;
+// errors are aggregated in element order via errors.CombineErrors. When ctx is done,
+// no new elements are started and ctx.Err() is included in the result.
+func ForEachParallel[T any](ctx context.Context, slice []T, maxWorkers int, fn func(ctx context.Context, item T) error) error {
+	_, err := MapParallel(ctx, slice, maxWorkers, func(ctx context.Context, item T) (struct{}, error) {
+		return struct{}{}, fn(ctx, item)
+	})
+	return err
+}
+
+// MapParallel transforms every element using at most maxWorkers goroutines
+// (maxWorkers <= 0 = runtime.NumCPU()). Results keep the order of the input; results
+// of failed or skipped elements are zero values. Errors are aggregated like ForEachParallel.
+func MapParallel[T, U any](ctx context.Context, slice []T, maxWorkers int, transform func(ctx context.Context, item T) (U, error)) ([]U, error) {
+	if maxWorkers <= 0 {
+		maxWorkers = runtime.NumCPU()
+	}
+	maxWorkers = min(maxWorkers, len(slice))
+
+	results := make([]U, len(slice))
+	errs := make([]error, len(slice))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < maxWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = transform(ctx, slice[i])
+			}
+		}()
+	}
+
+	var ctxErr error
+dispatch:
+	for i := range slice {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results, errors.CombineErrors(append(errs, ctxErr)...)
+}
@@ -0,0 +1,142 @@
+package collections
+
+import "container/heap"
+
+// heapItems adapts a slice and a less function to container/heap
+type heapItems[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *heapItems[T]) Len() int           { return len(h.items) }
+func (h *heapItems[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *heapItems[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *heapItems[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+func (h *heapItems[T]) Pop() any {
+	n := len(h.items)
+	item := h.items[n-1]
+	var zero T
+	h.items[n-1] = zero
+	h.items = h.items[:n-1]
+	return item
+}
+
+// PriorityQueue is a binary heap ordered by a custom comparator.
+// Pop returns the element for which less reports it comes first
+// (use a < b for a min-queue, a > b for a max-queue). Not safe for concurrent use.
+type PriorityQueue[T any] struct {
+	h *heapItems[T]
+}
+
+// NewPriorityQueue creates a priority queue ordered by less, optionally seeded with elements
+func NewPriorityQueue[T any](less func(a, b T) bool, elements ...T) *PriorityQueue[T] {
+	h := &heapItems[T]{items: Clone(elements), less: less}
+	if h.items == nil {
+		h.items = make([]T, 0)
+	}
+	heap.Init(h)
+	return &PriorityQueue[T]{h: h}
+}
+
+// Push adds elements to the queue
+func (pq *PriorityQueue[T]) Push(elements ...T) {
+	for _, element := range elements {
+		heap.Push(pq.h, element)
+	}
+}
+
+// Pop removes and returns the first element (false if the queue is empty)
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if pq.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(pq.h).(T), true
+}
+
+// Peek returns the first element without removing it (false if the queue is empty)
+func (pq *PriorityQueue[T]) Peek() (T, bool) {
+	if pq.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.h.items[0], true
+}
+
+// Len returns the number of elements
+func (pq *PriorityQueue[T]) Len() int {
+	return pq.h.Len()
+}
+
+// IsEmpty checks if the queue has no elements
+func (pq *PriorityQueue[T]) IsEmpty() bool {
+	return pq.h.Len() == 0
+}
+
+// Clear removes all elements
+func (pq *PriorityQueue[T]) Clear() {
+	pq.h.items = make([]T, 0)
+}
+
+// Items returns the elements in priority order without modifying the queue
+func (pq *PriorityQueue[T]) Items() []T {
+	return Sorted(pq.h.items, pq.h.less)
+}
+
+// BoundedPriorityQueue is a PriorityQueue holding at most capacity elements.
+// When full, pushing drops the lowest-priority element, so it retains the top
+// capacity elements seen (e.g. top-k computations). Eviction is O(capacity).
+type BoundedPriorityQueue[T any] struct {
+	PriorityQueue[T]
+	capacity int
+}
+
+// NewBoundedPriorityQueue creates a priority queue that keeps at most capacity elements
+func NewBoundedPriorityQueue[T any](capacity int, less func(a, b T) bool) *BoundedPriorityQueue[T] {
+	if capacity <= 0 {
+		capacity = 1
+	}
+	return &BoundedPriorityQueue[T]{
+		PriorityQueue: *NewPriorityQueue(less),
+		capacity:      capacity,
+	}
+}
+
+// Push adds elements, dropping the lowest-priority elements beyond capacity
+func (bq *BoundedPriorityQueue[T]) Push(elements ...T) {
+	for _, element := range elements {
+		bq.PushEvict(element)
+	}
+}
+
+// PushEvict adds an element and returns the element dropped to stay within capacity
+// (which may be the pushed element itself), if any
+func (bq *BoundedPriorityQueue[T]) PushEvict(element T) (T, bool) {
+	var zero T
+	if bq.h.Len() < bq.capacity {
+		heap.Push(bq.h, element)
+		return zero, false
+	}
+
+	// The lowest-priority element of a binary heap is one of its leaves
+	worst := bq.h.Len() / 2
+	for i := worst + 1; i < bq.h.Len(); i++ {
+		if bq.h.less(bq.h.items[worst], bq.h.items[i]) {
+			worst = i
+		}
+	}
+
+	if !bq.h.less(element, bq.h.items[worst]) {
+		return element, true
+	}
+
+	evicted := bq.h.items[worst]
+	bq.h.items[worst] = element
+	heap.Fix(bq.h, worst)
+	return evicted, true
+}
+
+// Capacity returns the maximum number of elements
+func (bq *BoundedPriorityQueue[T]) Capacity() int {
+	return bq.capacity
+}
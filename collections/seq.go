@@ -0,0 +1,161 @@
+package collections
+
+import "iter"
+
+// Seq is a lazy sequence built on Go iterators. Operations are chained without
+// allocating intermediate slices; nothing runs until the sequence is consumed.
+type Seq[T any] iter.Seq[T]
+
+// SeqOf returns a sequence over the elements of a slice
+func SeqOf[T any](slice []T) Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range slice {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// SeqFrom wraps an iter.Seq (e.g. maps.Keys, slices.Values) as a Seq
+func SeqFrom[T any](seq iter.Seq[T]) Seq[T] {
+	return Seq[T](seq)
+}
+
+// SeqFromChannel returns a sequence over the values received from a channel until it is closed
+func SeqFromChannel[T any](ch <-chan T) Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range ch {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// MapSeq lazily transforms each element of a sequence
+func MapSeq[T, U any](seq Seq[T], transform func(T) U) Seq[U] {
+	return func(yield func(U) bool) {
+		for item := range seq {
+			if !yield(transform(item)) {
+				return
+			}
+		}
+	}
+}
+
+// Iter returns the sequence as a standard iter.Seq
+func (s Seq[T]) Iter() iter.Seq[T] {
+	return iter.Seq[T](s)
+}
+
+// Filter lazily keeps the elements matching predicate
+func (s Seq[T]) Filter(predicate func(T) bool) Seq[T] {
+	return func(yield func(T) bool) {
+		for item := range s {
+			if predicate(item) && !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Take lazily yields at most the first n elements
+func (s Seq[T]) Take(n int) Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		taken := 0
+		for item := range s {
+			if !yield(item) {
+				return
+			}
+			taken++
+			if taken >= n {
+				return
+			}
+		}
+	}
+}
+
+// Skip lazily drops the first n elements
+func (s Seq[T]) Skip(n int) Seq[T] {
+	return func(yield func(T) bool) {
+		skipped := 0
+		for item := range s {
+			if skipped < n {
+				skipped++
+				continue
+			}
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// ChunkSeq lazily groups elements into slices of the given size (the last chunk may be smaller)
+func ChunkSeq[T any](s Seq[T], size int) Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size <= 0 {
+			return
+		}
+		chunk := make([]T, 0, size)
+		for item := range s {
+			chunk = append(chunk, item)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Collect consumes the sequence into a slice
+func (s Seq[T]) Collect() []T {
+	result := make([]T, 0)
+	for item := range s {
+		result = append(result, item)
+	}
+	return result
+}
+
+// ForEach consumes the sequence, calling fn for every element
+func (s Seq[T]) ForEach(fn func(T)) {
+	for item := range s {
+		fn(item)
+	}
+}
+
+// Count consumes the sequence and returns the number of elements
+func (s Seq[T]) Count() int {
+	count := 0
+	for range s {
+		count++
+	}
+	return count
+}
+
+// First returns the first element of the sequence
+func (s Seq[T]) First() (T, bool) {
+	for item := range s {
+		return item, true
+	}
+	var zero T
+	return zero, false
+}
+
+// ReduceSeq consumes the sequence, folding its elements into a single value
+func ReduceSeq[T, U any](seq Seq[T], initial U, reducer func(U, T) U) U {
+	result := initial
+	for item := range seq {
+		result = reducer(result, item)
+	}
+	return result
+}
@@ -0,0 +1,159 @@
+package collections
+
+import "encoding/json"
+
+// Set is an unordered collection of unique elements.
+// The zero value is an empty set ready to use.
+type Set[T comparable] struct {
+	items map[T]struct{}
+}
+
+// NewSet creates a set containing the given elements
+func NewSet[T comparable](elements ...T) *Set[T] {
+	s := &Set[T]{items: make(map[T]struct{}, len(elements))}
+	s.Add(elements...)
+	return s
+}
+
+// Add adds elements to the set
+func (s *Set[T]) Add(elements ...T) {
+	if s.items == nil {
+		s.items = make(map[T]struct{}, len(elements))
+	}
+	for _, element := range elements {
+		s.items[element] = struct{}{}
+	}
+}
+
+// Remove removes elements from the set
+func (s *Set[T]) Remove(elements ...T) {
+	for _, element := range elements {
+		delete(s.items, element)
+	}
+}
+
+// Contains checks if the set contains an element
+func (s *Set[T]) Contains(element T) bool {
+	_, ok := s.items[element]
+	return ok
+}
+
+// ContainsAll checks if the set contains all elements
+func (s *Set[T]) ContainsAll(elements ...T) bool {
+	for _, element := range elements {
+		if !s.Contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of elements in the set
+func (s *Set[T]) Len() int {
+	return len(s.items)
+}
+
+// IsEmpty checks if the set has no elements
+func (s *Set[T]) IsEmpty() bool {
+	return len(s.items) == 0
+}
+
+// Clear removes all elements from the set
+func (s *Set[T]) Clear() {
+	s.items = make(map[T]struct{})
+}
+
+// Clone returns a copy of the set
+func (s *Set[T]) Clone() *Set[T] {
+	result := &Set[T]{items: make(map[T]struct{}, len(s.items))}
+	for element := range s.items {
+		result.items[element] = struct{}{}
+	}
+	return result
+}
+
+// Union returns a new set with the elements of both sets
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	result := s.Clone()
+	for element := range other.items {
+		result.items[element] = struct{}{}
+	}
+	return result
+}
+
+// Intersect returns a new set with the elements present in both sets
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	small, large := s, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
+
+	result := NewSet[T]()
+	for element := range small.items {
+		if large.Contains(element) {
+			result.items[element] = struct{}{}
+		}
+	}
+	return result
+}
+
+// Difference returns a new set with the elements of s that are not in other
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	result := NewSet[T]()
+	for element := range s.items {
+		if !other.Contains(element) {
+			result.items[element] = struct{}{}
+		}
+	}
+	return result
+}
+
+// IsSubset checks if every element of s is in other
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+	for element := range s.items {
+		if !other.Contains(element) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal checks if both sets contain the same elements
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// ForEach calls fn for every element (in no particular order)
+func (s *Set[T]) ForEach(fn func(T)) {
+	for element := range s.items {
+		fn(element)
+	}
+}
+
+// ToSlice returns the elements as a slice (in no particular order)
+func (s *Set[T]) ToSlice() []T {
+	result := make([]T, 0, len(s.items))
+	for element := range s.items {
+		result = append(result, element)
+	}
+	return result
+}
+
+// MarshalJSON encodes the set as a JSON array
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.ToSlice())
+}
+
+// UnmarshalJSON decodes a JSON array into the set, dropping duplicates
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+	s.Clear()
+	s.Add(elements...)
+	return nil
+}
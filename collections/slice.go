@@ -45,7 +45,8 @@ func LastIndexOf[T comparable](slice []T, element T) int {
 	return -1
 }
 
-// Remove removes the first occurrence of element from slice
+// Remove removes the first occurrence of element from slice.
+// It modifies the backing array of slice; use RemoveCopy to keep the input intact.
 func Remove[T comparable](slice []T, element T) []T {
 	index := IndexOf(slice, element)
 	if index == -1 {
@@ -65,7 +66,8 @@ func RemoveAll[T comparable](slice []T, element T) []T {
 	return result
 }
 
-// RemoveAt removes element at the specified index
+// RemoveAt removes element at the specified index.
+// It modifies the backing array of slice; use RemoveAtCopy to keep the input intact.
 func RemoveAt[T any](slice []T, index int) ([]T, error) {
 	if index < 0 || index >= len(slice) {
 		return slice, fmt.Errorf("index %d out of bounds for slice of length %d", index, len(slice))
@@ -73,7 +75,8 @@ func RemoveAt[T any](slice []T, index int) ([]T, error) {
 	return append(slice[:index], slice[index+1:]...), nil
 }
 
-// Insert inserts element at the specified index
+// Insert inserts element at the specified index.
+// It may modify the backing array of slice; use InsertCopy to keep the input intact.
 func Insert[T any](slice []T, index int, element T) ([]T, error) {
 	if index < 0 || index > len(slice) {
 		return slice, fmt.Errorf("index %d out of bounds for slice of length %d", index, len(slice))
@@ -91,14 +94,63 @@ func Insert[T any](slice []T, index int, element T) ([]T, error) {
 	return slice, nil
 }
 
+// RemoveCopy returns a new slice without the first occurrence of element; the input is not modified
+func RemoveCopy[T comparable](slice []T, element T) []T {
+	index := IndexOf(slice, element)
+	if index == -1 {
+		return Clone(slice)
+	}
+	result, _ := RemoveAtCopy(slice, index)
+	return result
+}
+
+// RemoveAtCopy returns a new slice without the element at index; the input is not modified
+func RemoveAtCopy[T any](slice []T, index int) ([]T, error) {
+	if index < 0 || index >= len(slice) {
+		return Clone(slice), fmt.Errorf("index %d out of bounds for slice of length %d", index, len(slice))
+	}
+	result := make([]T, 0, len(slice)-1)
+	result = append(result, slice[:index]...)
+	return append(result, slice[index+1:]...), nil
+}
+
+// InsertCopy returns a new slice with element inserted at index; the input is not modified
+func InsertCopy[T any](slice []T, index int, element T) ([]T, error) {
+	if index < 0 || index > len(slice) {
+		return Clone(slice), fmt.Errorf("index %d out of bounds for slice of length %d", index, len(slice))
+	}
+	result := make([]T, 0, len(slice)+1)
+	result = append(result, slice[:index]...)
+	result = append(result, element)
+	return append(result, slice[index:]...), nil
+}
+
+// AppendCopy returns a new slice with elements appended; unlike append, it never
+// writes into spare capacity shared with the input
+func AppendCopy[T any](slice []T, elements ...T) []T {
+	result := make([]T, 0, len(slice)+len(elements))
+	result = append(result, slice...)
+	return append(result, elements...)
+}
+
+// SetCopy returns a new slice with the element at index replaced; the input is not modified
+func SetCopy[T any](slice []T, index int, element T) ([]T, error) {
+	if index < 0 || index >= len(slice) {
+		return Clone(slice), fmt.Errorf("index %d out of bounds for slice of length %d", index, len(slice))
+	}
+	result := Clone(slice)
+	result[index] = element
+	return result, nil
+}
+
 // Unique removes duplicate elements from slice
 func Unique[T comparable](slice []T) []T {
-	seen := make(map[T]bool)
+	seen := NewSet[T]()
 	result := make([]T, 0, len(slice))
 
 	for _, item := range slice {
-		if !seen[item] {
-			seen[item] = true
+		if !seen.Contains(item) {
+			seen.Add(item)
 			result = append(result, item)
 		}
 	}
@@ -221,6 +273,35 @@ func Chunk[T any](slice []T, size int) [][]T {
 	return chunks
 }
 
+// Windows returns sliding windows of the given size, advancing by step elements.
+// Only full windows are returned; like Chunk, windows share memory with slice.
+func Windows[T any](slice []T, size, step int) [][]T {
+	if size <= 0 || step <= 0 || size > len(slice) {
+		return [][]T{}
+	}
+
+	windows := make([][]T, 0, (len(slice)-size)/step+1)
+	for i := 0; i+size <= len(slice); i += step {
+		windows = append(windows, slice[i:i+size:i+size])
+	}
+
+	return windows
+}
+
+// Pairwise returns consecutive overlapping pairs: [a b c] -> [(a b) (b c)]
+func Pairwise[T any](slice []T) []Pair[T, T] {
+	if len(slice) < 2 {
+		return []Pair[T, T]{}
+	}
+
+	pairs := make([]Pair[T, T], len(slice)-1)
+	for i := 0; i < len(slice)-1; i++ {
+		pairs[i] = Pair[T, T]{First: slice[i], Second: slice[i+1]}
+	}
+
+	return pairs
+}
+
 // Flatten flattens a slice of slices into a single slice
 func Flatten[T any](slices [][]T) []T {
 	totalLen := 0
@@ -238,18 +319,14 @@ func Flatten[T any](slices [][]T) []T {
 
 // Intersection returns elements common to both slices
 func Intersection[T comparable](slice1, slice2 []T) []T {
-	set1 := make(map[T]bool)
-	for _, item := range slice1 {
-		set1[item] = true
-	}
-
+	set1 := NewSet(slice1...)
+	seen := NewSet[T]()
 	result := make([]T, 0)
-	seen := make(map[T]bool)
 
 	for _, item := range slice2 {
-		if set1[item] && !seen[item] {
+		if set1.Contains(item) && !seen.Contains(item) {
 			result = append(result, item)
-			seen[item] = true
+			seen.Add(item)
 		}
 	}
 
@@ -258,36 +335,18 @@ func Intersection[T comparable](slice1, slice2 []T) []T {
 
 // Union returns all unique elements from both slices
 func Union[T comparable](slice1, slice2 []T) []T {
-	seen := make(map[T]bool)
 	result := make([]T, 0, len(slice1)+len(slice2))
-
-	for _, item := range slice1 {
-		if !seen[item] {
-			result = append(result, item)
-			seen[item] = true
-		}
-	}
-
-	for _, item := range slice2 {
-		if !seen[item] {
-			result = append(result, item)
-			seen[item] = true
-		}
-	}
-
-	return result
+	result = append(result, slice1...)
+	return Unique(append(result, slice2...))
 }
 
 // Difference returns elements in slice1 that are not in slice2
 func Difference[T comparable](slice1, slice2 []T) []T {
-	set2 := make(map[T]bool)
-	for _, item := range slice2 {
-		set2[item] = true
-	}
+	set2 := NewSet(slice2...)
 
 	result := make([]T, 0)
 	for _, item := range slice1 {
-		if !set2[item] {
+		if !set2.Contains(item) {
 			result = append(result, item)
 		}
 	}
@@ -361,3 +420,53 @@ func InterfaceToSlice[T any](interfaces []interface{}) ([]T, error) {
 
 	return result, nil
 }
+
+// Partition splits a slice into elements matching predicate and the rest
+func Partition[T any](slice []T, predicate func(T) bool) (matched []T, rest []T) {
+	matched = make([]T, 0)
+	rest = make([]T, 0)
+	for _, item := range slice {
+		if predicate(item) {
+			matched = append(matched, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	return matched, rest
+}
+
+// Pair holds two values of possibly different types
+type Pair[A, B any] struct {
+	First  A `json:"first"`
+	Second B `json:"second"`
+}
+
+// Zip combines two slices into pairs; the result has the length of the shorter slice
+func Zip[A, B any](a []A, b []B) []Pair[A, B] {
+	n := min(len(a), len(b))
+	result := make([]Pair[A, B], n)
+	for i := 0; i < n; i++ {
+		result[i] = Pair[A, B]{First: a[i], Second: b[i]}
+	}
+	return result
+}
+
+// Unzip splits pairs into two slices
+func Unzip[A, B any](pairs []Pair[A, B]) ([]A, []B) {
+	first := make([]A, len(pairs))
+	second := make([]B, len(pairs))
+	for i, pair := range pairs {
+		first[i] = pair.First
+		second[i] = pair.Second
+	}
+	return first, second
+}
+
+// FlatMap transforms each element into a slice and concatenates the results
+func FlatMap[T, U any](slice []T, transform func(T) []U) []U {
+	result := make([]U, 0, len(slice))
+	for _, item := range slice {
+		result = append(result, transform(item)...)
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package collections
+
+import (
+	"cmp"
+	"slices"
+)
+
+// lessToCmp adapts a less function to the three-way comparison used by the slices package
+func lessToCmp[T any](less func(a, b T) bool) func(a, b T) int {
+	return func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// SortBy sorts the slice in place using less
+func SortBy[T any](slice []T, less func(a, b T) bool) {
+	slices.SortFunc(slice, lessToCmp(less))
+}
+
+// SortStable sorts the slice in place using less, keeping the order of equal elements
+func SortStable[T any](slice []T, less func(a, b T) bool) {
+	slices.SortStableFunc(slice, lessToCmp(less))
+}
+
+// SortByKey stably sorts the slice in place by the key returned by keyFn (ascending)
+func SortByKey[T any, K cmp.Ordered](slice []T, keyFn func(T) K) {
+	slices.SortStableFunc(slice, func(a, b T) int {
+		return cmp.Compare(keyFn(a), keyFn(b))
+	})
+}
+
+// SortByKeyDesc stably sorts the slice in place by the key returned by keyFn (descending)
+func SortByKeyDesc[T any, K cmp.Ordered](slice []T, keyFn func(T) K) {
+	slices.SortStableFunc(slice, func(a, b T) int {
+		return cmp.Compare(keyFn(b), keyFn(a))
+	})
+}
+
+// Sorted returns a sorted copy of the slice, leaving the original unchanged
+func Sorted[T any](slice []T, less func(a, b T) bool) []T {
+	result := Clone(slice)
+	SortStable(result, less)
+	return result
+}
+
+// MinBy returns the smallest element according to less (false for an empty slice)
+func MinBy[T any](slice []T, less func(a, b T) bool) (T, bool) {
+	var zero T
+	if len(slice) == 0 {
+		return zero, false
+	}
+	result := slice[0]
+	for _, item := range slice[1:] {
+		if less(item, result) {
+			result = item
+		}
+	}
+	return result, true
+}
+
+// MaxBy returns the largest element according to less (false for an empty slice)
+func MaxBy[T any](slice []T, less func(a, b T) bool) (T, bool) {
+	var zero T
+	if len(slice) == 0 {
+		return zero, false
+	}
+	result := slice[0]
+	for _, item := range slice[1:] {
+		if less(result, item) {
+			result = item
+		}
+	}
+	return result, true
+}
+
+// TopN returns the first n elements in the order defined by less (e.g. the n largest
+// with a "greater than" function) without modifying the original slice
+func TopN[T any](slice []T, n int, less func(a, b T) bool) []T {
+	if n <= 0 {
+		return []T{}
+	}
+	result := Sorted(slice, less)
+	if n < len(result) {
+		result = result[:n]
+	}
+	return result
+}
@@ -0,0 +1,257 @@
+package collections
+
+import "sync"
+
+// SyncMap is a concurrency-safe generic map guarded by a read-write mutex.
+// The zero value is an empty map ready to use.
+type SyncMap[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]V
+}
+
+// NewSyncMap creates a SyncMap, optionally seeded with the entries of initial
+func NewSyncMap[K comparable, V any](initial ...map[K]V) *SyncMap[K, V] {
+	m := &SyncMap[K, V]{items: make(map[K]V)}
+	for _, entries := range initial {
+		m.StoreAll(entries)
+	}
+	return m
+}
+
+// Load returns the value for a key
+func (m *SyncMap[K, V]) Load(key K) (V, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	value, ok := m.items[key]
+	return value, ok
+}
+
+// Store sets the value for a key
+func (m *SyncMap[K, V]) Store(key K, value V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	m.items[key] = value
+}
+
+// LoadOrStore returns the existing value for a key if present; otherwise it stores
+// and returns value. loaded reports whether the value was already present.
+func (m *SyncMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.items[key]; ok {
+		return existing, true
+	}
+	m.init()
+	m.items[key] = value
+	return value, false
+}
+
+// LoadAndDelete removes a key and returns its previous value
+func (m *SyncMap[K, V]) LoadAndDelete(key K) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	value, ok := m.items[key]
+	delete(m.items, key)
+	return value, ok
+}
+
+// Delete removes a key
+func (m *SyncMap[K, V]) Delete(key K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, key)
+}
+
+// Update atomically replaces the value for a key with the result of fn,
+// which receives the current value and whether it exists
+func (m *SyncMap[K, V]) Update(key K, fn func(value V, exists bool) V) V {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	value, ok := m.items[key]
+	value = fn(value, ok)
+	m.items[key] = value
+	return value
+}
+
+// StoreAll sets all entries of the given map in one batch
+func (m *SyncMap[K, V]) StoreAll(entries map[K]V) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.init()
+	for key, value := range entries {
+		m.items[key] = value
+	}
+}
+
+// LoadAll returns the values of all keys that are present
+func (m *SyncMap[K, V]) LoadAll(keys ...K) map[K]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make(map[K]V, len(keys))
+	for _, key := range keys {
+		if value, ok := m.items[key]; ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// DeleteAll removes all given keys in one batch
+func (m *SyncMap[K, V]) DeleteAll(keys ...K) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		delete(m.items, key)
+	}
+}
+
+// Len returns the number of entries
+func (m *SyncMap[K, V]) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.items)
+}
+
+// Keys returns a snapshot of the keys
+func (m *SyncMap[K, V]) Keys() []K {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return Keys(m.items)
+}
+
+// Values returns a snapshot of the values
+func (m *SyncMap[K, V]) Values() []V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return Values(m.items)
+}
+
+// Snapshot returns a copy of all entries
+func (m *SyncMap[K, V]) Snapshot() map[K]V {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return CloneMap(m.items)
+}
+
+// Range calls fn for every entry until fn returns false.
+// It iterates over a snapshot, so fn may safely modify the map.
+func (m *SyncMap[K, V]) Range(fn func(key K, value V) bool) {
+	for key, value := range m.Snapshot() {
+		if !fn(key, value) {
+			return
+		}
+	}
+}
+
+// Clear removes all entries
+func (m *SyncMap[K, V]) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = make(map[K]V)
+}
+
+// init lazily allocates the map for zero values; callers must hold the write lock
+func (m *SyncMap[K, V]) init() {
+	if m.items == nil {
+		m.items = make(map[K]V)
+	}
+}
+
+// SyncSlice is a concurrency-safe generic slice guarded by a read-write mutex.
+// The zero value is an empty slice ready to use.
+type SyncSlice[T any] struct {
+	mu    sync.RWMutex
+	items []T
+}
+
+// NewSyncSlice creates a SyncSlice containing the given elements
+func NewSyncSlice[T any](elements ...T) *SyncSlice[T] {
+	return &SyncSlice[T]{items: Clone(elements)}
+}
+
+// Append appends elements in one batch
+func (s *SyncSlice[T]) Append(elements ...T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = append(s.items, elements...)
+}
+
+// Get returns the element at index
+func (s *SyncSlice[T]) Get(index int) (T, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if index < 0 || index >= len(s.items) {
+		var zero T
+		return zero, false
+	}
+	return s.items[index], true
+}
+
+// Set replaces the element at index, reporting whether the index was valid
+func (s *SyncSlice[T]) Set(index int, element T) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index < 0 || index >= len(s.items) {
+		return false
+	}
+	s.items[index] = element
+	return true
+}
+
+// RemoveFunc removes all elements matching predicate and returns how many were removed
+func (s *SyncSlice[T]) RemoveFunc(predicate func(T) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.items[:0]
+	for _, item := range s.items {
+		if !predicate(item) {
+			kept = append(kept, item)
+		}
+	}
+	removed := len(s.items) - len(kept)
+	clear(s.items[len(kept):])
+	s.items = kept
+	return removed
+}
+
+// Len returns the number of elements
+func (s *SyncSlice[T]) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.items)
+}
+
+// Snapshot returns a copy of the elements
+func (s *SyncSlice[T]) Snapshot() []T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Clone(s.items)
+}
+
+// Drain returns all elements and empties the slice in one step (e.g. for batch flushing)
+func (s *SyncSlice[T]) Drain() []T {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items := s.items
+	s.items = nil
+	return items
+}
+
+// Range calls fn for every element until fn returns false.
+// It iterates over a snapshot, so fn may safely modify the slice.
+func (s *SyncSlice[T]) Range(fn func(index int, element T) bool) {
+	for i, item := range s.Snapshot() {
+		if !fn(i, item) {
+			return
+		}
+	}
+}
+
+// Clear removes all elements
+func (s *SyncSlice[T]) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = nil
+}
@@ -0,0 +1,89 @@
+package collections
+
+// TreeNode is a node of a tree built from flat items
+type TreeNode[T any] struct {
+	Item     T              `json:"item"`
+	Children []*TreeNode[T] `json:"children,omitempty"`
+}
+
+// BuildTree nests flat items (e.g. categories with a parent ID) into trees.
+// parentFunc returns the parent ID and false for root items; items whose parent
+// does not exist become roots. Items that are only reachable through a cycle are omitted.
+// Roots and children keep the order of the input.
+func BuildTree[T any, K comparable](items []T, idFunc func(T) K, parentFunc func(T) (K, bool)) []*TreeNode[T] {
+	nodes := make(map[K]*TreeNode[T], len(items))
+	ordered := make([]*TreeNode[T], len(items))
+	for i, item := range items {
+		node := &TreeNode[T]{Item: item}
+		nodes[idFunc(item)] = node
+		ordered[i] = node
+	}
+
+	roots := make([]*TreeNode[T], 0)
+	for i, item := range items {
+		node := ordered[i]
+		parentID, hasParent := parentFunc(item)
+		parent, exists := nodes[parentID]
+		if !hasParent || !exists || parent == node {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	return roots
+}
+
+// WalkDepthFirst visits nodes in depth-first pre-order with their depth (roots = 0)
+// until fn returns false
+func WalkDepthFirst[T any](roots []*TreeNode[T], fn func(node *TreeNode[T], depth int) bool) {
+	var walk func(nodes []*TreeNode[T], depth int) bool
+	walk = func(nodes []*TreeNode[T], depth int) bool {
+		for _, node := range nodes {
+			if !fn(node, depth) || !walk(node.Children, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(roots, 0)
+}
+
+// WalkBreadthFirst visits nodes level by level with their depth (roots = 0)
+// until fn returns false
+func WalkBreadthFirst[T any](roots []*TreeNode[T], fn func(node *TreeNode[T], depth int) bool) {
+	level := roots
+	for depth := 0; len(level) > 0; depth++ {
+		var next []*TreeNode[T]
+		for _, node := range level {
+			if !fn(node, depth) {
+				return
+			}
+			next = append(next, node.Children...)
+		}
+		level = next
+	}
+}
+
+// FlattenTree returns the items of the trees in depth-first pre-order
+func FlattenTree[T any](roots []*TreeNode[T]) []T {
+	result := make([]T, 0)
+	WalkDepthFirst(roots, func(node *TreeNode[T], _ int) bool {
+		result = append(result, node.Item)
+		return true
+	})
+	return result
+}
+
+// FindInTree returns the first node (depth-first) whose item matches predicate
+func FindInTree[T any](roots []*TreeNode[T], predicate func(T) bool) (*TreeNode[T], bool) {
+	var found *TreeNode[T]
+	WalkDepthFirst(roots, func(node *TreeNode[T], _ int) bool {
+		if predicate(node.Item) {
+			found = node
+			return false
+		}
+		return true
+	})
+	return found, found != nil
+}
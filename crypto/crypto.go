@@ -7,6 +7,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/sha256"
+	"crypto/sha512"
 	"crypto/x509"
 	"encoding/base64"
 	"encoding/hex"
@@ -336,6 +337,13 @@ func VerifyHMACSHA256(data, signature, key []byte) bool {
 	return hmac.Equal(signature, expectedSignature)
 }
 
+// HMACSHA512 computes HMAC-SHA512
+func HMACSHA512(data, key []byte) []byte {
+	h := hmac.New(sha512.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
 // SimpleJWT represents a simple JWT implementation
 type SimpleJWT struct {
 	SecretKey []byte
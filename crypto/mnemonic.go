@@ -0,0 +1,110 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed mnemonic_english.txt
+var mnemonicEnglish string
+
+// mnemonicWords is the BIP39 English wordlist (2048 words)
+var mnemonicWords = strings.Fields(mnemonicEnglish)
+
+// mnemonicIndex maps each word to its 11-bit index
+var mnemonicIndex = func() map[string]int {
+	index := make(map[string]int, len(mnemonicWords))
+	for i, word := range mnemonicWords {
+		index[word] = i
+	}
+	return index
+}()
+
+// SecretToMnemonic encodes a secret as BIP39 mnemonic words.
+// The secret must be 16-32 bytes in 4-byte steps (12-24 words); a SHA-256
+// checksum is appended so typos are detected when decoding.
+func SecretToMnemonic(secret []byte) ([]string, error) {
+	if len(secret) < 16 || len(secret) > 32 || len(secret)%4 != 0 {
+		return nil, fmt.Errorf("secret must be 16, 20, 24, 28 or 32 bytes, got %d", len(secret))
+	}
+
+	entropyBits := len(secret) * 8
+	checksumBits := entropyBits / 32
+	hash := sha256.Sum256(secret)
+
+	// Secret bits followed by the checksum bits (at most 8, so one hash byte suffices)
+	data := append(append([]byte{}, secret...), hash[0])
+
+	wordCount := (entropyBits + checksumBits) / 11
+	words := make([]string, wordCount)
+	for i := 0; i < wordCount; i++ {
+		index := 0
+		for b := 0; b < 11; b++ {
+			pos := i*11 + b
+			bit := (data[pos/8] >> (7 - uint(pos%8))) & 1
+			index = index<<1 | int(bit)
+		}
+		words[i] = mnemonicWords[index]
+	}
+
+	return words, nil
+}
+
+// MnemonicToSecret decodes BIP39 mnemonic words back into the original secret,
+// validating word membership and the embedded checksum.
+func MnemonicToSecret(words []string) ([]byte, error) {
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, fmt.Errorf("mnemonic must have 12, 15, 18, 21 or 24 words, got %d", len(words))
+	}
+
+	totalBits := len(words) * 11
+	checksumBits := totalBits / 33
+	entropyBits := totalBits - checksumBits
+
+	data := make([]byte, (totalBits+7)/8)
+	for i, word := range words {
+		index, ok := mnemonicIndex[strings.ToLower(strings.TrimSpace(word))]
+		if !ok {
+			return nil, fmt.Errorf("invalid mnemonic word at position %d: %q", i+1, word)
+		}
+		for b := 0; b < 11; b++ {
+			if index&(1<<(10-uint(b))) != 0 {
+				pos := i*11 + b
+				data[pos/8] |= 1 << (7 - uint(pos%8))
+			}
+		}
+	}
+
+	secret := data[:entropyBits/8]
+	hash := sha256.Sum256(secret)
+	expected := hash[0] >> (8 - uint(checksumBits))
+	actual := data[entropyBits/8] >> (8 - uint(checksumBits))
+	if expected != actual {
+		return nil, fmt.Errorf("invalid mnemonic checksum")
+	}
+
+	return secret, nil
+}
+
+// ValidateMnemonic checks whether the words form a valid mnemonic
+func ValidateMnemonic(words []string) bool {
+	_, err := MnemonicToSecret(words)
+	return err == nil
+}
+
+// GenerateMnemonic generates a random secret of the given size and returns it with its mnemonic
+func GenerateMnemonic(secretSize int) ([]byte, []string, error) {
+	secret, err := GenerateRandomBytes(secretSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	words, err := SecretToMnemonic(secret)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return secret, words, nil
+}
@@ -0,0 +1,118 @@
+package datetime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IsRelative checks if a value is a relative date expression ("now", "today", "now-7d", ...)
+func IsRelative(expr string) bool {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+	return strings.HasPrefix(expr, "now") || strings.HasPrefix(expr, "today")
+}
+
+// ParseRelative evaluates a relative date expression against now.
+// An expression starts with "now" or "today" (midnight of now's day), followed by any
+// number of offsets like "-7d" or "+1M" and an optional rounding suffix like "/d":
+//
+//	now-7d, now-1h, today+1d, now-1M/M (start of last month)
+//
+// Units: s (seconds), m (minutes), h (hours), d (days), w (weeks), M (months), y (years).
+func ParseRelative(expr string, now time.Time) (time.Time, error) {
+	rest := strings.TrimSpace(expr)
+	var t time.Time
+
+	switch lower := strings.ToLower(rest); {
+	case strings.HasPrefix(lower, "now"):
+		t = now
+		rest = rest[len("now"):]
+	case strings.HasPrefix(lower, "today"):
+		t = StartOfDay(now)
+		rest = rest[len("today"):]
+	default:
+		return time.Time{}, fmt.Errorf("relative date must start with 'now' or 'today': %s", expr)
+	}
+
+	for rest != "" {
+		if rest[0] == '/' {
+			if len(rest) != 2 {
+				return time.Time{}, fmt.Errorf("invalid rounding in relative date: %s", expr)
+			}
+			rounded, err := roundDown(t, rest[1])
+			if err != nil {
+				return time.Time{}, fmt.Errorf("%w: %s", err, expr)
+			}
+			return rounded, nil
+		}
+
+		if rest[0] != '+' && rest[0] != '-' {
+			return time.Time{}, fmt.Errorf("invalid offset in relative date: %s", expr)
+		}
+
+		i := 1
+		for i < len(rest) && rest[i] >= '0' && rest[i] <= '9' {
+			i++
+		}
+		if i == 1 || i == len(rest) {
+			return time.Time{}, fmt.Errorf("invalid offset in relative date: %s", expr)
+		}
+
+		amount, err := strconv.Atoi(rest[1:i])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid offset in relative date: %s", expr)
+		}
+		if rest[0] == '-' {
+			amount = -amount
+		}
+
+		t, err = addUnit(t, amount, rest[i])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %s", err, expr)
+		}
+		rest = rest[i+1:]
+	}
+
+	return t, nil
+}
+
+// addUnit adds amount of the given unit to t
+func addUnit(t time.Time, amount int, unit byte) (time.Time, error) {
+	switch unit {
+	case 's':
+		return t.Add(time.Duration(amount) * time.Second), nil
+	case 'm':
+		return t.Add(time.Duration(amount) * time.Minute), nil
+	case 'h':
+		return t.Add(time.Duration(amount) * time.Hour), nil
+	case 'd':
+		return t.AddDate(0, 0, amount), nil
+	case 'w':
+		return t.AddDate(0, 0, amount*7), nil
+	case 'M':
+		return t.AddDate(0, amount, 0), nil
+	case 'y':
+		return t.AddDate(amount, 0, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown unit '%c' in relative date", unit)
+	}
+}
+
+// roundDown truncates t to the start of the given unit
+func roundDown(t time.Time, unit byte) (time.Time, error) {
+	switch unit {
+	case 'h':
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()), nil
+	case 'd':
+		return StartOfDay(t), nil
+	case 'w':
+		return StartOfDay(StartOfWeek(t)), nil
+	case 'M':
+		return StartOfMonth(t), nil
+	case 'y':
+		return StartOfYear(t), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown rounding unit '%c' in relative date", unit)
+	}
+}
@@ -63,6 +63,16 @@ func Tomorrow() time.Time {
 	return Today().AddDate(0, 0, 1)
 }
 
+// StartOfDay returns midnight of the given time's day
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay returns the last nanosecond of the given time's day
+func EndOfDay(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
 // StartOfWeek returns the start of the week (Monday) for the given time
 func StartOfWeek(t time.Time) time.Time {
 	weekday := int(t.Weekday())
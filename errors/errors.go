@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -350,7 +351,38 @@ func NewErrorChain() *ErrorChain {
 // ErrorHandler provides utilities for error handling
 type ErrorHandler struct {
 	DefaultComponent string
-	Logger           func(error)
+	Logger           func(error) // Optional: defaults to the logger set with SetDefaultLogger
+}
+
+var (
+	defaultLoggerMu sync.RWMutex
+	defaultLogger   func(error)
+)
+
+// SetDefaultLogger sets the logger of error handlers without their own Logger
+// (e.g. logger.ErrorLogger for structured logs of *Error fields)
+func SetDefaultLogger(logger func(error)) {
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	defaultLogger = logger
+}
+
+// DefaultLogger returns the logger set with SetDefaultLogger (nil if none)
+func DefaultLogger() func(error) {
+	defaultLoggerMu.RLock()
+	defer defaultLoggerMu.RUnlock()
+	return defaultLogger
+}
+
+// log logs err with the handler's logger, or the default logger if it has none
+func (eh *ErrorHandler) log(err error) {
+	logger := eh.Logger
+	if logger == nil {
+		logger = DefaultLogger()
+	}
+	if logger != nil {
+		logger(err)
+	}
 }
 
 // NewErrorHandler creates a new error handler
@@ -368,9 +400,7 @@ func (eh *ErrorHandler) Handle(err error, sanitize bool) error {
 	}
 
 	// Log the error
-	if eh.Logger != nil {
-		eh.Logger(err)
-	}
+	eh.log(err)
 
 	// If sanitization is requested and it's an internal error, return a generic error
 	if sanitize {
@@ -392,9 +422,7 @@ func (eh *ErrorHandler) Recover() error {
 			err.WithComponent(eh.DefaultComponent)
 		}
 
-		if eh.Logger != nil {
-			eh.Logger(err)
-		}
+		eh.log(err)
 
 		return err
 	}
@@ -410,9 +438,7 @@ func (eh *ErrorHandler) SafeExecute(fn func() error) (err error) {
 				err.(*Error).WithComponent(eh.DefaultComponent)
 			}
 
-			if eh.Logger != nil {
-				eh.Logger(err)
-			}
+			eh.log(err)
 		}
 	}()
 
@@ -0,0 +1,82 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/datetime"
+)
+
+// datePrefix marks a filter on the calendar date of a time field, e.g. date(created_at)_eq
+const datePrefix = "date("
+
+// parseDateField unwraps "date(field)" and reports whether the date function was used
+func parseDateField(field string) (string, bool) {
+	if len(field) > len(datePrefix)+1 && strings.HasPrefix(strings.ToLower(field), datePrefix) && strings.HasSuffix(field, ")") {
+		return field[len(datePrefix) : len(field)-1], true
+	}
+	return field, false
+}
+
+// isTimeType checks if a field type holds time values
+func isTimeType(fieldType string) bool {
+	switch strings.ToLower(fieldType) {
+	case "time", "datetime", "date":
+		return true
+	}
+	return false
+}
+
+// parseTimeValue parses absolute times (RFC3339, 2006-01-02, 2006-01-02T15:04:05)
+// and relative expressions such as now-7d, evaluated against the current UTC time
+func parseTimeValue(value string) (time.Time, error) {
+	if datetime.IsRelative(value) {
+		return datetime.ParseRelative(value, datetime.NowUTC())
+	}
+	// Try ISO 8601 format first
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	// Try date only
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	// Try date with time
+	if t, err := time.Parse("2006-01-02T15:04:05", value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time format: %s (expected RFC3339, 2006-01-02, 2006-01-02T15:04:05 or a relative date like now-7d)", value)
+}
+
+// expandDateFilter rewrites a comparison on date(field) into range filters on the raw
+// column (e.g. date(created_at)_eq=2024-01-01 becomes created_at >= 2024-01-01 AND
+// created_at < 2024-01-02), which keeps indexes usable and works for every backend
+func expandDateFilter(dbField, fieldType string, operator Operator, value string) ([]Filter, error) {
+	t, err := parseTimeValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	start := datetime.StartOfDay(t)
+	end := start.AddDate(0, 0, 1)
+
+	rangeFilter := func(op Operator, bound time.Time) Filter {
+		return Filter{Field: dbField, Operator: op, Value: bound, FieldType: fieldType}
+	}
+
+	switch operator {
+	case OperatorEQ:
+		return []Filter{rangeFilter(OperatorGTE, start), rangeFilter(OperatorLT, end)}, nil
+	case OperatorGT:
+		return []Filter{rangeFilter(OperatorGTE, end)}, nil
+	case OperatorGTE:
+		return []Filter{rangeFilter(OperatorGTE, start)}, nil
+	case OperatorLT:
+		return []Filter{rangeFilter(OperatorLT, start)}, nil
+	case OperatorLTE:
+		return []Filter{rangeFilter(OperatorLT, end)}, nil
+	default:
+		return nil, fmt.Errorf("operator '%s' is not supported with date()", operator)
+	}
+}
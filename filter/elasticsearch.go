@@ -0,0 +1,86 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+)
+
+// esRangeOperators maps comparison operators to Elasticsearch range parameters
+var esRangeOperators = map[Operator]string{
+	OperatorGT:  "gt",
+	OperatorGTE: "gte",
+	OperatorLT:  "lt",
+	OperatorLTE: "lte",
+}
+
+// BuildElasticsearch converts filters into an Elasticsearch bool query.
+// The result is the value of the "query" key, e.g.:
+//
+//	{"bool": {"filter": [...], "must_not": [...]}}
+//
+// eq/in become term/terms filters, ne/not_in become must_not clauses,
+// comparisons become range queries and like becomes a wildcard query.
+func BuildElasticsearch(filters []Filter) (map[string]interface{}, error) {
+	filterClauses := make([]interface{}, 0, len(filters))
+	mustNot := make([]interface{}, 0)
+
+	for _, f := range filters {
+		switch f.Operator {
+		case OperatorEQ:
+			filterClauses = append(filterClauses, esTerm(f.Field, f.Value))
+		case OperatorNE:
+			mustNot = append(mustNot, esTerm(f.Field, f.Value))
+		case OperatorGT, OperatorGTE, OperatorLT, OperatorLTE:
+			filterClauses = append(filterClauses, map[string]interface{}{
+				"range": map[string]interface{}{
+					f.Field: map[string]interface{}{esRangeOperators[f.Operator]: f.Value},
+				},
+			})
+		case OperatorLIKE:
+			filterClauses = append(filterClauses, map[string]interface{}{
+				"wildcard": map[string]interface{}{
+					f.Field: map[string]interface{}{"value": "*" + escapeWildcard(fmt.Sprintf("%v", f.Value)) + "*"},
+				},
+			})
+		case OperatorIN:
+			filterClauses = append(filterClauses, esTerms(f.Field, f.Value))
+		case OperatorNOTIN:
+			mustNot = append(mustNot, esTerms(f.Field, f.Value))
+		default:
+			return nil, fmt.Errorf("operator '%s' is not supported by the Elasticsearch backend", f.Operator)
+		}
+	}
+
+	boolQuery := map[string]interface{}{
+		"filter": filterClauses,
+	}
+	if len(mustNot) > 0 {
+		boolQuery["must_not"] = mustNot
+	}
+
+	return map[string]interface{}{"bool": boolQuery}, nil
+}
+
+// esTerm builds an exact-match term query
+func esTerm(field string, value interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"term": map[string]interface{}{field: value},
+	}
+}
+
+// esTerms builds a terms query for a list of values
+func esTerms(field string, value interface{}) map[string]interface{} {
+	values := toSlice(value)
+	if values == nil {
+		values = []interface{}{}
+	}
+	return map[string]interface{}{
+		"terms": map[string]interface{}{field: values},
+	}
+}
+
+// escapeWildcard escapes wildcard metacharacters so user input is matched literally
+func escapeWildcard(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`)
+	return replacer.Replace(value)
+}
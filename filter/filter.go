@@ -3,9 +3,9 @@ package filter
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/kerimovok/go-pkg-utils/httpx"
 	"gorm.io/gorm"
 )
 
@@ -51,7 +51,9 @@ const (
 	TypeUUID = "uuid" // UUID columns: eq/ne use value as-is; LIKE uses CAST(column AS TEXT)
 )
 
-// Filter represents a single filter condition
+// Filter represents a single filter condition.
+// It is backend-neutral: ApplyFilters translates it for GORM, while BuildSQL,
+// BuildMongo and BuildElasticsearch target other data stores.
 type Filter struct {
 	Field     string
 	Operator  Operator
@@ -70,6 +72,26 @@ type Config struct {
 	FieldMapping map[string]string
 	// CustomValidators allows custom validation for specific fields
 	CustomValidators map[string]func(value string) error
+	// Strict rejects operators that don't fit the field type (e.g. like on int, gt on bool)
+	// and malformed bool/UUID values with errors.ValidationError before any SQL is built
+	Strict bool
+	// MaxInValues limits the number of values of an in/not_in filter (0 = unlimited)
+	MaxInValues int
+	// MaxFilters limits the number of filters per request (0 = unlimited)
+	MaxFilters int
+	// SoftDelete enables include_deleted/only_deleted handling in ApplyFiltersFromContext
+	SoftDelete bool
+	// DeletedAtColumn is the soft-delete column (defaults to DefaultDeletedAtColumn)
+	DeletedAtColumn string
+	// CanViewDeleted restricts include_deleted/only_deleted (nil = allowed for everyone)
+	CanViewDeleted func(c *fiber.Ctx) bool
+	// Scopes are row-level constraints (e.g. TenantScope) enforced by ApplyFiltersFromContext
+	Scopes []ScopeFunc
+	// Telemetry receives the columns and operators used by each parsed request (optional)
+	Telemetry Telemetry
+	// Explain enables debug mode: ApplyFiltersFromContext runs EXPLAIN on the composed
+	// query and passes the plan to this handler. Adds a query per request; don't enable in production.
+	Explain ExplainHandler
 }
 
 // ParseFilters parses filters from Fiber query parameters
@@ -94,10 +116,13 @@ func ParseFilters(c *fiber.Ctx, config *Config) ([]Filter, error) {
 		}
 
 		// Validate operator
-		if !AllowedOperators[operator] {
+		if !isAllowedOperator(operator) {
 			return nil, fmt.Errorf("invalid operator '%s' for field '%s'", operator, field)
 		}
 
+		// Unwrap date(field) used to compare calendar dates of time fields
+		field, dateOnly := parseDateField(field)
+
 		// Map field name if mapping is provided
 		dbField := field
 		if config != nil && config.FieldMapping != nil {
@@ -113,6 +138,35 @@ func ParseFilters(c *fiber.Ctx, config *Config) ([]Filter, error) {
 			}
 		}
 
+		fieldType := ""
+		if config != nil && config.AllowedFields != nil {
+			if ft, ok := config.AllowedFields[field]; ok {
+				fieldType = ft
+			}
+		}
+
+		strict := config != nil && config.Strict
+		if strict {
+			if err := validateOperatorForType(field, fieldType, operator); err != nil {
+				return nil, err
+			}
+		}
+
+		if dateOnly {
+			if fieldType != "" && !isTimeType(fieldType) {
+				return nil, fmt.Errorf("date() requires a time field, '%s' is of type '%s'", field, fieldType)
+			}
+			dateFilters, err := expandDateFilter(dbField, fieldType, operator, queryParams[key])
+			if err != nil {
+				return nil, fmt.Errorf("invalid date filter for field '%s': %w", field, err)
+			}
+			filters = append(filters, dateFilters...)
+			if err := validateFilterCount(len(filters), config); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
 		var convertedValue interface{}
 
 		if operator == OperatorIN || operator == OperatorNOTIN {
@@ -124,6 +178,17 @@ func ParseFilters(c *fiber.Ctx, config *Config) ([]Filter, error) {
 				values[i] = string(v)
 			}
 
+			if err := validateInValuesCount(field, len(values), config); err != nil {
+				return nil, err
+			}
+			if strict {
+				for _, v := range values {
+					if err := validateStrictValue(field, fieldType, operator, v); err != nil {
+						return nil, err
+					}
+				}
+			}
+
 			// Run custom validator once per individual value
 			if config != nil && config.CustomValidators != nil {
 				if validator, ok := config.CustomValidators[field]; ok {
@@ -142,6 +207,12 @@ func ParseFilters(c *fiber.Ctx, config *Config) ([]Filter, error) {
 		} else {
 			value := queryParams[key]
 
+			if strict {
+				if err := validateStrictValue(field, fieldType, operator, value); err != nil {
+					return nil, err
+				}
+			}
+
 			// Custom validation if provided
 			if config != nil && config.CustomValidators != nil {
 				if validator, ok := config.CustomValidators[field]; ok {
@@ -157,24 +228,35 @@ func ParseFilters(c *fiber.Ctx, config *Config) ([]Filter, error) {
 			}
 		}
 
-		fieldType := ""
-		if config != nil && config.AllowedFields != nil {
-			if ft, ok := config.AllowedFields[field]; ok {
-				fieldType = ft
-			}
-		}
-
 		filters = append(filters, Filter{
 			Field:     dbField,
 			Operator:  operator,
 			Value:     convertedValue,
 			FieldType: fieldType,
 		})
+
+		if err := validateFilterCount(len(filters), config); err != nil {
+			return nil, err
+		}
+	}
+
+	if config != nil && config.Telemetry != nil && len(filters) > 0 {
+		config.Telemetry.RecordFilters(usagesOf(filters))
 	}
 
 	return filters, nil
 }
 
+// AppliedFilters converts filters for echoing in responses (pagination.Params.Filters);
+// fields are the filtered database columns
+func AppliedFilters(filters []Filter) []httpx.AppliedFilter {
+	applied := make([]httpx.AppliedFilter, len(filters))
+	for i, f := range filters {
+		applied[i] = httpx.AppliedFilter{Field: f.Field, Operator: string(f.Operator), Value: f.Value}
+	}
+	return applied
+}
+
 // ApplyFilters applies filters to a GORM query
 func ApplyFilters(query *gorm.DB, filters []Filter) *gorm.DB {
 	for _, f := range filters {
@@ -183,22 +265,44 @@ func ApplyFilters(query *gorm.DB, filters []Filter) *gorm.DB {
 	return query
 }
 
-// ApplyFiltersFromContext is a convenience function that parses and applies filters
+// ApplyFiltersFromContext is a convenience function that parses and applies filters,
+// together with soft-delete handling and scopes from the config
 func ApplyFiltersFromContext(c *fiber.Ctx, query *gorm.DB, config *Config) (*gorm.DB, error) {
 	filters, err := ParseFilters(c, config)
 	if err != nil {
 		return nil, err
 	}
-	return ApplyFilters(query, filters), nil
+	query, err = ApplyScopes(c, query, config)
+	if err != nil {
+		return nil, err
+	}
+	query = ApplyFilters(query, filters)
+
+	if config != nil && config.Explain != nil {
+		result, err := Explain(query)
+		config.Explain(c, result, err)
+	}
+
+	return query, nil
 }
 
 // parseFilterKey parses a query key in the format "field_operator"
+// Operators containing underscores (not_in, custom operators such as within_radius)
+// are matched as suffixes before falling back to the last segment.
 func parseFilterKey(key string) (field string, operator Operator, err error) {
 	parts := strings.Split(key, "_")
 	if len(parts) < 2 {
 		return "", "", fmt.Errorf("invalid filter format")
 	}
 
+	lowerKey := strings.ToLower(key)
+	for _, op := range operatorNames() {
+		suffix := "_" + string(op)
+		if strings.Contains(string(op), "_") && strings.HasSuffix(lowerKey, suffix) && len(key) > len(suffix) {
+			return key[:len(key)-len(suffix)], op, nil
+		}
+	}
+
 	// Get operator (last part)
 	opStr := strings.ToLower(parts[len(parts)-1])
 	operator = Operator(opStr)
@@ -258,19 +362,11 @@ func convertSingleValue(value, fieldType string) (interface{}, error) {
 	case "bool", "boolean":
 		return strings.ToLower(value) == "true" || value == "1", nil
 	case "time", "datetime", "date":
-		// Try ISO 8601 format first
-		if t, err := time.Parse(time.RFC3339, value); err == nil {
-			return t, nil
-		}
-		// Try date only
-		if t, err := time.Parse("2006-01-02", value); err == nil {
-			return t, nil
-		}
-		// Try date with time
-		if t, err := time.Parse("2006-01-02T15:04:05", value); err == nil {
-			return t, nil
+		t, err := parseTimeValue(value)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("invalid time format: %s (expected RFC3339, 2006-01-02, or 2006-01-02T15:04:05)", value)
+		return t, nil
 	case TypeUUID:
 		// Keep as string; DB drivers accept string for UUID comparison; LIKE uses CAST in applyFilter
 		return value, nil
@@ -296,7 +392,7 @@ func applyFilter(query *gorm.DB, f Filter) *gorm.DB {
 	case OperatorLTE:
 		return query.Where(f.Field+" <= ?", f.Value)
 	case OperatorLIKE:
-		likeValue := fmt.Sprintf("%%%s%%", f.Value)
+		likeValue := likePattern(f.Value)
 		// UUID columns don't support LIKE; use CAST(column AS TEXT) (PostgreSQL/SQLite; standard SQL)
 		if f.FieldType == TypeUUID {
 			return query.Where("CAST("+f.Field+" AS TEXT) LIKE ?", likeValue)
@@ -307,6 +403,9 @@ func applyFilter(query *gorm.DB, f Filter) *gorm.DB {
 	case OperatorNOTIN:
 		return query.Where(f.Field+" NOT IN ?", f.Value)
 	default:
+		if fn, ok := lookupCustomOperator(f.Operator); ok {
+			return fn(query, f.Field, f.Value)
+		}
 		return query
 	}
 }
@@ -314,7 +413,7 @@ func applyFilter(query *gorm.DB, f Filter) *gorm.DB {
 // isReservedParam checks if a parameter is reserved for pagination/sorting
 // Uses case-insensitive matching to support both snake_case and camelCase
 func isReservedParam(key string) bool {
-	reserved := []string{"page", "per_page", "sort_by", "sort_order"}
+	reserved := []string{"page", "per_page", "sort_by", "sort_order", SortParam, FieldsParam, IncludeDeletedParam, OnlyDeletedParam}
 	keyLower := strings.ToLower(key)
 	for _, r := range reserved {
 		if keyLower == strings.ToLower(r) {
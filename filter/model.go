@@ -0,0 +1,171 @@
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+// ModelOptions controls how ConfigFromModel derives a Config from a model
+type ModelOptions struct {
+	// Include limits the filterable fields to these API names (empty = all fields)
+	Include []string
+	// Exclude removes API names from the filterable fields (e.g. password_hash)
+	Exclude []string
+	// Types overrides the detected field type per API name (e.g. {"external_id": TypeUUID})
+	Types map[string]string
+	// NamingStrategy resolves column names for fields without a column tag.
+	// Defaults to GORM's default naming strategy.
+	NamingStrategy schema.Namer
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ConfigFromModel builds AllowedFields and FieldMapping from a GORM model using its
+// gorm and json tags, so the filter allow-list follows the schema.
+// API names come from the json tag (falling back to the column name); columns come
+// from the gorm column tag or the naming strategy. Relations, slices and maps are skipped.
+func ConfigFromModel(model interface{}, opts *ModelOptions) (*Config, error) {
+	if opts == nil {
+		opts = &ModelOptions{}
+	}
+	namer := opts.NamingStrategy
+	if namer == nil {
+		namer = schema.NamingStrategy{}
+	}
+
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("model must be a struct or pointer to struct, got %T", model)
+	}
+
+	config := &Config{
+		AllowedFields: make(map[string]string),
+		FieldMapping:  make(map[string]string),
+	}
+	collectModelFields(t, "", namer, config)
+
+	if len(opts.Include) > 0 {
+		included := make(map[string]bool, len(opts.Include))
+		for _, name := range opts.Include {
+			if _, ok := config.AllowedFields[name]; !ok {
+				return nil, fmt.Errorf("included field '%s' does not exist on %s", name, t.Name())
+			}
+			included[name] = true
+		}
+		for name := range config.AllowedFields {
+			if !included[name] {
+				delete(config.AllowedFields, name)
+				delete(config.FieldMapping, name)
+			}
+		}
+	}
+
+	for _, name := range opts.Exclude {
+		delete(config.AllowedFields, name)
+		delete(config.FieldMapping, name)
+	}
+
+	for name, fieldType := range opts.Types {
+		if _, ok := config.AllowedFields[name]; !ok {
+			continue
+		}
+		config.AllowedFields[name] = fieldType
+	}
+
+	return config, nil
+}
+
+// collectModelFields walks struct fields (including embedded structs) and registers filterable ones
+func collectModelFields(t reflect.Type, columnPrefix string, namer schema.Namer, config *Config) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		gormTag := sf.Tag.Get("gorm")
+		if gormTag == "-" || strings.HasPrefix(gormTag, "-:") {
+			continue
+		}
+		settings := schema.ParseTagSetting(gormTag, ";")
+
+		fieldType := sf.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// Embedded structs (anonymous or gorm:"embedded") contribute their fields
+		if _, embedded := settings["EMBEDDED"]; (sf.Anonymous || embedded) && fieldType.Kind() == reflect.Struct && modelFieldType(fieldType) == "" {
+			collectModelFields(fieldType, columnPrefix+settings["EMBEDDEDPREFIX"], namer, config)
+			continue
+		}
+
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+
+		valueType := modelFieldType(fieldType)
+		if valueType == "" {
+			continue
+		}
+
+		column := settings["COLUMN"]
+		if column == "" {
+			column = namer.ColumnName("", sf.Name)
+		}
+		column = columnPrefix + column
+
+		name := jsonName
+		if name == "" {
+			name = column
+		}
+
+		config.AllowedFields[name] = valueType
+		config.FieldMapping[name] = column
+	}
+}
+
+// modelFieldType maps a Go type to a filter field type ("" = not filterable)
+func modelFieldType(t reflect.Type) string {
+	if t == timeType {
+		return "time"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	case reflect.Array:
+		// uuid.UUID and similar 16-byte identifiers
+		if t.Len() == 16 && t.Elem().Kind() == reflect.Uint8 {
+			return TypeUUID
+		}
+	case reflect.Struct:
+		// Nullable wrappers such as sql.NullString or gorm.DeletedAt: {Value, Valid bool}
+		if t.NumField() == 2 {
+			if valid, ok := t.FieldByName("Valid"); ok && valid.Type.Kind() == reflect.Bool {
+				for i := 0; i < t.NumField(); i++ {
+					if f := t.Field(i); f.Name != "Valid" {
+						return modelFieldType(f.Type)
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
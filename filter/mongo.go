@@ -0,0 +1,68 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// mongoOperators maps filter operators to MongoDB query operators
+var mongoOperators = map[Operator]string{
+	OperatorEQ:    "$eq",
+	OperatorNE:    "$ne",
+	OperatorGT:    "$gt",
+	OperatorGTE:   "$gte",
+	OperatorLT:    "$lt",
+	OperatorLTE:   "$lte",
+	OperatorLIKE:  "$regex",
+	OperatorIN:    "$in",
+	OperatorNOTIN: "$nin",
+}
+
+// BuildMongo converts filters into a MongoDB query document.
+// The result is a plain map (compatible with bson.M) that can be passed to collection.Find.
+// Conditions on the same field are merged (e.g. {"size": {"$gt": 1, "$lt": 10}});
+// a repeated operator on the same field falls back to an $and clause.
+func BuildMongo(filters []Filter) (map[string]interface{}, error) {
+	query := make(map[string]interface{})
+	var and []interface{}
+
+	for _, f := range filters {
+		op, ok := mongoOperators[f.Operator]
+		if !ok {
+			return nil, fmt.Errorf("operator '%s' is not supported by the MongoDB backend", f.Operator)
+		}
+
+		var value interface{}
+		switch f.Operator {
+		case OperatorLIKE:
+			value = regexp.QuoteMeta(fmt.Sprintf("%v", f.Value))
+		case OperatorIN, OperatorNOTIN:
+			value = toSlice(f.Value)
+			if value == nil {
+				value = []interface{}{}
+			}
+		default:
+			value = f.Value
+		}
+
+		fieldDoc, exists := query[f.Field].(map[string]interface{})
+		if !exists {
+			fieldDoc = make(map[string]interface{})
+			query[f.Field] = fieldDoc
+		}
+
+		if _, conflict := fieldDoc[op]; conflict {
+			and = append(and, map[string]interface{}{
+				f.Field: map[string]interface{}{op: value},
+			})
+			continue
+		}
+		fieldDoc[op] = value
+	}
+
+	if len(and) > 0 {
+		query["$and"] = and
+	}
+
+	return query, nil
+}
@@ -0,0 +1,107 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kerimovok/go-pkg-utils/validator"
+	"gorm.io/gorm"
+)
+
+// OperatorFunc applies a custom operator to a GORM query.
+// field is the mapped database column and value is the converted filter value.
+type OperatorFunc func(query *gorm.DB, field string, value interface{}) *gorm.DB
+
+var (
+	customOperatorsMu sync.RWMutex
+	customOperators   = make(map[Operator]OperatorFunc)
+)
+
+// init makes the built-in operators acceptable to the validator's filterexpr rule
+func init() {
+	for op := range AllowedOperators {
+		validator.RegisterFilterOperators(string(op))
+	}
+}
+
+// RegisterOperator registers a domain-specific operator (e.g. "within_radius", "tsquery")
+// usable in query parameters as field_<name>=value and applied by ApplyFilters.
+// Names are case-insensitive like query keys and stored lowercased ("inRange" matches
+// field_inrange). It is intended to be called during initialization and panics if name
+// is empty, fn is nil or name collides with a built-in operator. The operator is also
+// accepted by the validator's filterexpr rule.
+func RegisterOperator(name string, fn OperatorFunc) {
+	name = strings.ToLower(name)
+	if name == "" {
+		panic("filter: operator name cannot be empty")
+	}
+	if fn == nil {
+		panic(fmt.Sprintf("filter: operator function for '%s' cannot be nil", name))
+	}
+	if AllowedOperators[Operator(name)] {
+		panic(fmt.Sprintf("filter: cannot override built-in operator '%s'", name))
+	}
+
+	customOperatorsMu.Lock()
+	defer customOperatorsMu.Unlock()
+	customOperators[Operator(name)] = fn
+	validator.RegisterFilterOperators(name)
+}
+
+// UnregisterOperator removes a previously registered custom operator
+func UnregisterOperator(name string) {
+	name = strings.ToLower(name)
+	customOperatorsMu.Lock()
+	defer customOperatorsMu.Unlock()
+	if _, ok := customOperators[Operator(name)]; !ok {
+		return // Never remove a built-in operator from the validator
+	}
+	delete(customOperators, Operator(name))
+	validator.UnregisterFilterOperators(name)
+}
+
+// IsCustomOperator returns true if operator was registered via RegisterOperator
+func IsCustomOperator(operator Operator) bool {
+	_, ok := lookupCustomOperator(operator)
+	return ok
+}
+
+// lookupCustomOperator returns the function registered for a custom operator
+func lookupCustomOperator(operator Operator) (OperatorFunc, bool) {
+	customOperatorsMu.RLock()
+	defer customOperatorsMu.RUnlock()
+	fn, ok := customOperators[operator]
+	return fn, ok
+}
+
+// isAllowedOperator checks built-in and custom operators
+func isAllowedOperator(operator Operator) bool {
+	if AllowedOperators[operator] {
+		return true
+	}
+	_, ok := lookupCustomOperator(operator)
+	return ok
+}
+
+// operatorNames returns all known operators, longest first, for suffix matching
+func operatorNames() []Operator {
+	customOperatorsMu.RLock()
+	names := make([]Operator, 0, len(AllowedOperators)+len(customOperators))
+	for op := range AllowedOperators {
+		names = append(names, op)
+	}
+	for op := range customOperators {
+		names = append(names, op)
+	}
+	customOperatorsMu.RUnlock()
+
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i]) != len(names[j]) {
+			return len(names[i]) > len(names[j])
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
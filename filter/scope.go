@@ -0,0 +1,131 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kerimovok/go-pkg-utils/errors"
+	"gorm.io/gorm"
+)
+
+const (
+	// IncludeDeletedParam includes soft-deleted rows (e.g. include_deleted=true)
+	IncludeDeletedParam = "include_deleted"
+	// OnlyDeletedParam returns only soft-deleted rows (e.g. only_deleted=true)
+	OnlyDeletedParam = "only_deleted"
+	// DefaultDeletedAtColumn is the soft-delete column used by gorm.DeletedAt
+	DefaultDeletedAtColumn = "deleted_at"
+)
+
+// SoftDeleteMode controls which rows are returned for soft-deletable tables
+type SoftDeleteMode int
+
+const (
+	// SoftDeleteExclude returns only rows that are not deleted (default)
+	SoftDeleteExclude SoftDeleteMode = iota
+	// SoftDeleteInclude returns deleted and non-deleted rows
+	SoftDeleteInclude
+	// SoftDeleteOnly returns only deleted rows
+	SoftDeleteOnly
+)
+
+// ScopeFunc applies a row-level constraint (e.g. tenant isolation) for the current request.
+// Returning an error aborts the query, e.g. when the tenant cannot be resolved.
+type ScopeFunc func(c *fiber.Ctx, query *gorm.DB) (*gorm.DB, error)
+
+// TenantScope returns a ScopeFunc restricting rows to the tenant resolved from the request
+func TenantScope(column string, tenantID func(c *fiber.Ctx) (interface{}, error)) ScopeFunc {
+	return func(c *fiber.Ctx, query *gorm.DB) (*gorm.DB, error) {
+		id, err := tenantID(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tenant: %w", err)
+		}
+		if id == nil {
+			return nil, fmt.Errorf("tenant is required")
+		}
+		return query.Where(column+" = ?", id), nil
+	}
+}
+
+// ParseSoftDeleteMode parses include_deleted and only_deleted from Fiber query parameters
+func ParseSoftDeleteMode(c *fiber.Ctx) (SoftDeleteMode, error) {
+	includeDeleted, err := parseBoolParam(c, IncludeDeletedParam)
+	if err != nil {
+		return SoftDeleteExclude, err
+	}
+	onlyDeleted, err := parseBoolParam(c, OnlyDeletedParam)
+	if err != nil {
+		return SoftDeleteExclude, err
+	}
+
+	switch {
+	case includeDeleted && onlyDeleted:
+		return SoftDeleteExclude, fmt.Errorf("'%s' and '%s' cannot be combined", IncludeDeletedParam, OnlyDeletedParam)
+	case onlyDeleted:
+		return SoftDeleteOnly, nil
+	case includeDeleted:
+		return SoftDeleteInclude, nil
+	default:
+		return SoftDeleteExclude, nil
+	}
+}
+
+// parseBoolParam parses an optional boolean query parameter
+func parseBoolParam(c *fiber.Ctx, name string) (bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid value for '%s': %s", name, value)
+	}
+	return parsed, nil
+}
+
+// ApplySoftDelete applies the soft-delete mode to a GORM query.
+// The condition is added explicitly, so it also applies to queries on raw tables
+// without a gorm.DeletedAt field.
+func ApplySoftDelete(query *gorm.DB, mode SoftDeleteMode, column string) *gorm.DB {
+	if column == "" {
+		column = DefaultDeletedAtColumn
+	}
+
+	switch mode {
+	case SoftDeleteInclude:
+		return query.Unscoped()
+	case SoftDeleteOnly:
+		return query.Unscoped().Where(column + " IS NOT NULL")
+	default:
+		return query.Unscoped().Where(column + " IS NULL")
+	}
+}
+
+// ApplyScopes applies soft-delete handling and all configured scopes for the current request
+func ApplyScopes(c *fiber.Ctx, query *gorm.DB, config *Config) (*gorm.DB, error) {
+	if config == nil {
+		return query, nil
+	}
+
+	if config.SoftDelete {
+		mode, err := ParseSoftDeleteMode(c)
+		if err != nil {
+			return nil, err
+		}
+		if mode != SoftDeleteExclude && config.CanViewDeleted != nil && !config.CanViewDeleted(c) {
+			return nil, errors.ForbiddenError("FILTER_DELETED_FORBIDDEN", "not allowed to view deleted records")
+		}
+		query = ApplySoftDelete(query, mode, config.DeletedAtColumn)
+	}
+
+	for _, scope := range config.Scopes {
+		var err error
+		query, err = scope(c, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return query, nil
+}
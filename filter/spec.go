@@ -0,0 +1,230 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kerimovok/go-pkg-utils/httpx"
+	"gorm.io/gorm"
+)
+
+const (
+	// SortParam is the query parameter for multi-column sorting (e.g. sort=-created_at,name)
+	SortParam = "sort"
+	// FieldsParam is the query parameter for field selection (e.g. fields=id,name)
+	FieldsParam = "fields"
+)
+
+// FieldSpec declares what a single API field may be used for
+type FieldSpec struct {
+	Column     string // Database column; defaults to the field name
+	Type       string // Value type for filtering ("string", "int", "time", TypeUUID, ...)
+	Filterable bool
+	Sortable   bool
+	Selectable bool
+}
+
+// SpecConfig is the single allow-list for filtering, sorting and field selection
+type SpecConfig struct {
+	// Fields maps API field names to their capabilities
+	Fields map[string]FieldSpec
+	// DefaultSort is used when no sort parameter is given (e.g. "-created_at")
+	DefaultSort string
+	// MaxSortFields limits the number of sort columns (0 = unlimited)
+	MaxSortFields int
+	// CustomValidators allows custom validation for specific filter fields
+	CustomValidators map[string]func(value string) error
+	// Strict, MaxInValues and MaxFilters are passed through to the filter Config
+	Strict      bool
+	MaxInValues int
+	MaxFilters  int
+}
+
+// SortField represents one column of a multi-column sort
+type SortField struct {
+	Field  string // API field name
+	Column string // Database column
+	Desc   bool
+}
+
+// QuerySpec is the combined result of parsing filters, sorting and field selection
+type QuerySpec struct {
+	Filters []Filter
+	Sort    []SortField
+	Fields  []string // Selected database columns (empty = all)
+}
+
+// FilterConfig derives the filter Config for all filterable fields
+func (sc *SpecConfig) FilterConfig() *Config {
+	config := &Config{
+		AllowedFields:    make(map[string]string),
+		FieldMapping:     make(map[string]string),
+		CustomValidators: sc.CustomValidators,
+		Strict:           sc.Strict,
+		MaxInValues:      sc.MaxInValues,
+		MaxFilters:       sc.MaxFilters,
+	}
+	for name, spec := range sc.Fields {
+		if !spec.Filterable {
+			continue
+		}
+		fieldType := spec.Type
+		if fieldType == "" {
+			fieldType = "string"
+		}
+		config.AllowedFields[name] = fieldType
+		config.FieldMapping[name] = spec.column(name)
+	}
+	return config
+}
+
+// column returns the database column for a field
+func (fs FieldSpec) column(name string) string {
+	if fs.Column != "" {
+		return fs.Column
+	}
+	return name
+}
+
+// ParseQuerySpec parses filters, sort and fields from Fiber query parameters in one call
+func ParseQuerySpec(c *fiber.Ctx, config *SpecConfig) (*QuerySpec, error) {
+	if config == nil {
+		return nil, fmt.Errorf("spec config is required")
+	}
+
+	filters, err := ParseFilters(c, config.FilterConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	sortValue := c.Query(SortParam)
+	if sortValue == "" {
+		sortValue = config.DefaultSort
+	}
+	sort, err := ParseSort(sortValue, config)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := ParseFields(c.Query(FieldsParam), config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QuerySpec{
+		Filters: filters,
+		Sort:    sort,
+		Fields:  fields,
+	}, nil
+}
+
+// ParseSort parses a sort expression like "-created_at,name" ("-" prefix = descending)
+func ParseSort(value string, config *SpecConfig) ([]SortField, error) {
+	var sort []SortField
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		desc := false
+		if strings.HasPrefix(part, "-") {
+			desc = true
+			part = part[1:]
+		} else if strings.HasPrefix(part, "+") {
+			part = part[1:]
+		}
+
+		spec, ok := config.Fields[part]
+		if !ok || !spec.Sortable {
+			return nil, fmt.Errorf("field '%s' is not allowed for sorting", part)
+		}
+		if seen[part] {
+			return nil, fmt.Errorf("field '%s' is specified more than once in sort", part)
+		}
+		seen[part] = true
+
+		sort = append(sort, SortField{Field: part, Column: spec.column(part), Desc: desc})
+	}
+
+	if config.MaxSortFields > 0 && len(sort) > config.MaxSortFields {
+		return nil, fmt.Errorf("at most %d sort fields are allowed", config.MaxSortFields)
+	}
+
+	return sort, nil
+}
+
+// ParseFields parses a comma-separated field selection into database columns
+func ParseFields(value string, config *SpecConfig) ([]string, error) {
+	var columns []string
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" || seen[part] {
+			continue
+		}
+
+		spec, ok := config.Fields[part]
+		if !ok || !spec.Selectable {
+			return nil, fmt.Errorf("field '%s' is not allowed for selection", part)
+		}
+		seen[part] = true
+		columns = append(columns, spec.column(part))
+	}
+
+	return columns, nil
+}
+
+// OrderClause returns the ORDER BY clause (without keyword), e.g. "created_at DESC, name ASC".
+// Assign it to pagination.Params.OrderBy so pagination uses the same ordering.
+func (qs *QuerySpec) OrderClause() string {
+	parts := make([]string, len(qs.Sort))
+	for i, s := range qs.Sort {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+		parts[i] = s.Column + " " + dir
+	}
+	return strings.Join(parts, ", ")
+}
+
+// AppliedSort returns the sort for echoing in responses (pagination.Params.Sort)
+func (qs *QuerySpec) AppliedSort() []httpx.AppliedSort {
+	sort := make([]httpx.AppliedSort, len(qs.Sort))
+	for i, s := range qs.Sort {
+		sort[i] = httpx.AppliedSort{Field: s.Field, Order: "asc"}
+		if s.Desc {
+			sort[i].Order = "desc"
+		}
+	}
+	return sort
+}
+
+// AppliedFilters returns the filters for echoing in responses (pagination.Params.Filters)
+func (qs *QuerySpec) AppliedFilters() []httpx.AppliedFilter {
+	return AppliedFilters(qs.Filters)
+}
+
+// ApplyFilters applies the parsed filters and field selection to a GORM query (no ordering).
+// Use this when ordering is delegated to the pagination package.
+func (qs *QuerySpec) ApplyFilters(query *gorm.DB) *gorm.DB {
+	query = ApplyFilters(query, qs.Filters)
+	if len(qs.Fields) > 0 {
+		query = query.Select(qs.Fields)
+	}
+	return query
+}
+
+// Apply applies filters, field selection and ordering to a GORM query
+func (qs *QuerySpec) Apply(query *gorm.DB) *gorm.DB {
+	query = qs.ApplyFilters(query)
+	if order := qs.OrderClause(); order != "" {
+		query = query.Order(order)
+	}
+	return query
+}
@@ -0,0 +1,131 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PlaceholderFormat controls how bind parameters are rendered in generated SQL
+type PlaceholderFormat int
+
+const (
+	// PlaceholderQuestion renders bind parameters as ? (MySQL, SQLite, squirrel default)
+	PlaceholderQuestion PlaceholderFormat = iota
+	// PlaceholderDollar renders bind parameters as $1, $2, ... (PostgreSQL)
+	PlaceholderDollar
+)
+
+// SQLCondition is a WHERE clause fragment together with its bind arguments.
+// It implements squirrel's Sqlizer interface, so it can be passed directly to
+// squirrel's Where (use PlaceholderQuestion and let squirrel rewrite placeholders).
+type SQLCondition struct {
+	SQL  string
+	Args []interface{}
+}
+
+// ToSql returns the SQL fragment and its arguments (squirrel.Sqlizer)
+func (c SQLCondition) ToSql() (string, []interface{}, error) {
+	return c.SQL, c.Args, nil
+}
+
+// IsEmpty returns true if the condition has no SQL
+func (c SQLCondition) IsEmpty() bool {
+	return c.SQL == ""
+}
+
+// BuildSQL converts filters into a database/sql WHERE fragment (without the WHERE keyword).
+// Conditions are joined with AND. An empty filter set returns an empty condition.
+func BuildSQL(filters []Filter, format PlaceholderFormat) (SQLCondition, error) {
+	var clauses []string
+	var args []interface{}
+
+	placeholder := func() string {
+		if format == PlaceholderDollar {
+			return "$" + strconv.Itoa(len(args))
+		}
+		return "?"
+	}
+
+	for _, f := range filters {
+		switch f.Operator {
+		case OperatorEQ, OperatorNE, OperatorGT, OperatorGTE, OperatorLT, OperatorLTE:
+			args = append(args, f.Value)
+			clauses = append(clauses, f.Field+" "+sqlComparison(f.Operator)+" "+placeholder())
+		case OperatorLIKE:
+			args = append(args, likePattern(f.Value))
+			column := f.Field
+			if f.FieldType == TypeUUID {
+				column = "CAST(" + f.Field + " AS TEXT)"
+			}
+			clauses = append(clauses, column+" LIKE "+placeholder())
+		case OperatorIN, OperatorNOTIN:
+			values := toSlice(f.Value)
+			if len(values) == 0 {
+				// IN () is invalid SQL; an empty IN matches nothing, an empty NOT IN matches everything
+				if f.Operator == OperatorIN {
+					clauses = append(clauses, "1 = 0")
+				}
+				continue
+			}
+			placeholders := make([]string, len(values))
+			for i, v := range values {
+				args = append(args, v)
+				placeholders[i] = placeholder()
+			}
+			keyword := "IN"
+			if f.Operator == OperatorNOTIN {
+				keyword = "NOT IN"
+			}
+			clauses = append(clauses, f.Field+" "+keyword+" ("+strings.Join(placeholders, ", ")+")")
+		default:
+			return SQLCondition{}, fmt.Errorf("operator '%s' is not supported by the SQL backend", f.Operator)
+		}
+	}
+
+	return SQLCondition{
+		SQL:  strings.Join(clauses, " AND "),
+		Args: args,
+	}, nil
+}
+
+// sqlComparison returns the SQL comparison operator for a scalar filter operator
+func sqlComparison(operator Operator) string {
+	switch operator {
+	case OperatorNE:
+		return "!="
+	case OperatorGT:
+		return ">"
+	case OperatorGTE:
+		return ">="
+	case OperatorLT:
+		return "<"
+	case OperatorLTE:
+		return "<="
+	default:
+		return "="
+	}
+}
+
+// likePattern wraps a value in % wildcards for substring matching
+func likePattern(value interface{}) string {
+	return fmt.Sprintf("%%%s%%", value)
+}
+
+// toSlice normalizes an IN / NOT_IN value to []interface{}
+func toSlice(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case []string:
+		result := make([]interface{}, len(v))
+		for i, s := range v {
+			result[i] = s
+		}
+		return result
+	case nil:
+		return nil
+	default:
+		return []interface{}{v}
+	}
+}
@@ -0,0 +1,146 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// FilterUsage describes a parsed filter without its value
+type FilterUsage struct {
+	Column    string
+	Operator  Operator
+	FieldType string
+}
+
+// Telemetry receives the filters used by each parsed request, e.g. to find
+// frequently filtered columns that lack an index
+type Telemetry interface {
+	RecordFilters(usages []FilterUsage)
+}
+
+// ExplainHandler receives the EXPLAIN output of a composed query (or the error running it)
+type ExplainHandler func(c *fiber.Ctx, result *ExplainResult, err error)
+
+// ExplainResult holds the composed SQL and the database's query plan rows
+type ExplainResult struct {
+	SQL  string
+	Vars []interface{}
+	Plan []map[string]interface{}
+}
+
+// usagesOf converts filters to telemetry usages
+func usagesOf(filters []Filter) []FilterUsage {
+	usages := make([]FilterUsage, len(filters))
+	for i, f := range filters {
+		usages[i] = FilterUsage{Column: f.Field, Operator: f.Operator, FieldType: f.FieldType}
+	}
+	return usages
+}
+
+// UsageCount is the aggregated number of uses of a column/operator pair
+type UsageCount struct {
+	Column   string
+	Operator Operator
+	Count    int64
+}
+
+// UsageCounter is an in-memory Telemetry that aggregates column/operator usage
+type UsageCounter struct {
+	mu     sync.Mutex
+	counts map[FilterUsage]int64
+}
+
+// NewUsageCounter creates a new in-memory usage counter
+func NewUsageCounter() *UsageCounter {
+	return &UsageCounter{counts: make(map[FilterUsage]int64)}
+}
+
+// RecordFilters implements Telemetry
+func (uc *UsageCounter) RecordFilters(usages []FilterUsage) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	for _, u := range usages {
+		uc.counts[FilterUsage{Column: u.Column, Operator: u.Operator}]++
+	}
+}
+
+// Counts returns the aggregated usage, most used first
+func (uc *UsageCounter) Counts() []UsageCount {
+	uc.mu.Lock()
+	counts := make([]UsageCount, 0, len(uc.counts))
+	for u, n := range uc.counts {
+		counts = append(counts, UsageCount{Column: u.Column, Operator: u.Operator, Count: n})
+	}
+	uc.mu.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if counts[i].Column != counts[j].Column {
+			return counts[i].Column < counts[j].Column
+		}
+		return counts[i].Operator < counts[j].Operator
+	})
+	return counts
+}
+
+// Reset clears the aggregated usage
+func (uc *UsageCounter) Reset() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.counts = make(map[FilterUsage]int64)
+}
+
+// Explain runs EXPLAIN on the SELECT statement a GORM query would execute.
+// The query must have a model or table set. Intended for debugging only.
+func Explain(query *gorm.DB) (*ExplainResult, error) {
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&[]map[string]interface{}{}).Statement
+	if stmt.Error != nil {
+		return nil, fmt.Errorf("failed to build query: %w", stmt.Error)
+	}
+
+	result := &ExplainResult{
+		SQL:  stmt.SQL.String(),
+		Vars: stmt.Vars,
+	}
+
+	// Run through the connection pool directly: the SQL already contains dialect-specific bind vars
+	rows, err := query.Statement.ConnPool.QueryContext(query.Statement.Context, "EXPLAIN "+result.SQL, result.Vars...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run explain: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read explain columns: %w", err)
+	}
+
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("failed to scan explain row: %w", err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+		result.Plan = append(result.Plan, row)
+	}
+
+	return result, rows.Err()
+}
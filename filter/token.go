@@ -0,0 +1,233 @@
+package filter
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/hmac"
+)
+
+// TokenOptions bind a filter token to a use and limit its lifetime.
+// Both are part of the signed payload and checked by Decode.
+type TokenOptions struct {
+	Audience string        // e.g. "orders-export"; Decode rejects tokens issued for another audience
+	TTL      time.Duration // How long the token is valid (<= 0 = no expiry)
+}
+
+// filterToken is the signed payload of a filter token
+type filterToken struct {
+	IssuedAt  int64           `json:"iat"`
+	ExpiresAt int64           `json:"exp,omitempty"`
+	Audience  string          `json:"aud,omitempty"`
+	Filters   []encodedFilter `json:"fs"`
+}
+
+// encodedFilter is the compact wire form of a Filter inside a token
+type encodedFilter struct {
+	Field     string   `json:"f"`
+	Operator  Operator `json:"o"`
+	FieldType string   `json:"t,omitempty"`
+	Kind      string   `json:"k,omitempty"` // Go type of the value(s), restored when set
+	Value     *string  `json:"v,omitempty"`
+	Values    []string `json:"vs,omitempty"`
+}
+
+// Encode serializes the active filter set into a compact signed token
+// (base64url payload + "." + HMAC signature) so export jobs and next-page
+// cursors can reproduce the exact query without trusting client input.
+func Encode(filters []Filter, secret string, opts TokenOptions) (string, error) {
+	if secret == "" {
+		return "", fmt.Errorf("secret is required to sign filters")
+	}
+
+	now := time.Now()
+	token := filterToken{
+		IssuedAt: now.Unix(),
+		Audience: opts.Audience,
+		Filters:  make([]encodedFilter, len(filters)),
+	}
+	if opts.TTL > 0 {
+		token.ExpiresAt = now.Add(opts.TTL).Unix()
+	}
+
+	for i, f := range filters {
+		ef := encodedFilter{
+			Field:     f.Field,
+			Operator:  f.Operator,
+			FieldType: f.FieldType,
+		}
+		if f.Operator == OperatorIN || f.Operator == OperatorNOTIN {
+			values := toSlice(f.Value)
+			ef.Values = make([]string, 0, len(values))
+			for j, v := range values {
+				kind := tokenKind(v)
+				if j == 0 {
+					ef.Kind = kind
+				} else if kind != ef.Kind {
+					// Mixed types fall back to FieldType conversion
+					ef.Kind = ""
+				}
+				ef.Values = append(ef.Values, formatTokenValue(v))
+			}
+		} else {
+			value := formatTokenValue(f.Value)
+			ef.Value = &value
+			ef.Kind = tokenKind(f.Value)
+		}
+		token.Filters[i] = ef
+	}
+
+	payload, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal filters: %w", err)
+	}
+
+	payloadEncoded := base64.RawURLEncoding.EncodeToString(payload)
+	return payloadEncoded + "." + hmac.Sign([]byte(payloadEncoded), secret), nil
+}
+
+// Decode verifies a token produced by Encode and restores the filter set.
+// The token must not be expired and must have been issued for opts.Audience.
+// Values are converted back to their Go types (int64, time.Time, ...).
+func Decode(token, secret string, opts TokenOptions) ([]Filter, error) {
+	payloadEncoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, fmt.Errorf("invalid filter token format")
+	}
+	if !hmac.Verify([]byte(payloadEncoded), signature, secret) {
+		return nil, fmt.Errorf("invalid filter token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(payloadEncoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode filter token: %w", err)
+	}
+
+	var decoded filterToken
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal filter token: %w", err)
+	}
+	if decoded.Audience != opts.Audience {
+		return nil, fmt.Errorf("filter token audience mismatch")
+	}
+	if decoded.ExpiresAt != 0 && time.Now().Unix() >= decoded.ExpiresAt {
+		return nil, fmt.Errorf("filter token expired")
+	}
+
+	filters := make([]Filter, len(decoded.Filters))
+	for i, ef := range decoded.Filters {
+		f := Filter{
+			Field:     ef.Field,
+			Operator:  ef.Operator,
+			FieldType: ef.FieldType,
+		}
+		if ef.Value != nil {
+			f.Value, err = parseTokenValue(*ef.Value, ef.Kind, ef.FieldType)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert value for field '%s': %w", ef.Field, err)
+			}
+		} else {
+			values := make([]interface{}, 0, len(ef.Values))
+			for _, v := range ef.Values {
+				converted, err := parseTokenValue(v, ef.Kind, ef.FieldType)
+				if err != nil {
+					return nil, fmt.Errorf("failed to convert value for field '%s': %w", ef.Field, err)
+				}
+				values = append(values, converted)
+			}
+			f.Value = values
+		}
+		filters[i] = f
+	}
+
+	return filters, nil
+}
+
+// formatTokenValue renders a filter value as a string that parseTokenValue can parse back
+func formatTokenValue(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// tokenKind names the Go type of value for parseTokenValue ("" for strings and unknown types)
+func tokenKind(value interface{}) string {
+	if _, ok := value.(time.Time); ok {
+		return "time"
+	}
+	if value == nil {
+		return ""
+	}
+	switch kind := reflect.TypeOf(value).Kind(); kind {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return kind.String()
+	default:
+		return ""
+	}
+}
+
+// parseTokenValue restores a value of the given kind, falling back to the field type
+func parseTokenValue(value, kind, fieldType string) (interface{}, error) {
+	var (
+		result interface{}
+		err    error
+	)
+	switch kind {
+	case "":
+		return convertSingleValue(value, fieldType)
+	case "time":
+		result, err = time.Parse(time.RFC3339Nano, value)
+	case "bool":
+		result, err = strconv.ParseBool(value)
+	case "int":
+		var n int64
+		n, err = strconv.ParseInt(value, 10, 0)
+		result = int(n)
+	case "int8", "int16", "int32", "int64":
+		var n int64
+		n, err = strconv.ParseInt(value, 10, 64)
+		result = reflect.ValueOf(n).Convert(tokenKinds[kind]).Interface()
+	case "uint", "uint8", "uint16", "uint32", "uint64":
+		var n uint64
+		n, err = strconv.ParseUint(value, 10, 64)
+		result = reflect.ValueOf(n).Convert(tokenKinds[kind]).Interface()
+	case "float32", "float64":
+		var n float64
+		n, err = strconv.ParseFloat(value, 64)
+		result = reflect.ValueOf(n).Convert(tokenKinds[kind]).Interface()
+	default:
+		return nil, fmt.Errorf("unknown value kind: %s", kind)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s value: %s", kind, value)
+	}
+	return result, nil
+}
+
+// tokenKinds maps the sized numeric kinds to their types
+var tokenKinds = map[string]reflect.Type{
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
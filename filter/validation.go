@@ -0,0 +1,110 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kerimovok/go-pkg-utils/errors"
+	uuidx "github.com/kerimovok/go-pkg-utils/uuid"
+)
+
+// Error codes returned by filter validation
+const (
+	ErrCodeTooManyFilters      = "FILTER_TOO_MANY"
+	ErrCodeTooManyValues       = "FILTER_TOO_MANY_VALUES"
+	ErrCodeOperatorNotAllowed  = "FILTER_OPERATOR_NOT_ALLOWED"
+	ErrCodeInvalidFilterValues = "FILTER_INVALID_VALUE"
+)
+
+var (
+	comparableOperators = map[Operator]bool{
+		OperatorEQ: true, OperatorNE: true, OperatorGT: true, OperatorGTE: true,
+		OperatorLT: true, OperatorLTE: true, OperatorIN: true, OperatorNOTIN: true,
+	}
+	boolOperators = map[Operator]bool{
+		OperatorEQ: true, OperatorNE: true,
+	}
+	uuidOperators = map[Operator]bool{
+		OperatorEQ: true, OperatorNE: true, OperatorLIKE: true, OperatorIN: true, OperatorNOTIN: true,
+	}
+)
+
+// operatorsForType returns the built-in operators valid for a field type (nil = all)
+func operatorsForType(fieldType string) map[Operator]bool {
+	switch strings.ToLower(fieldType) {
+	case "int", "integer", "float", "float64", "time", "datetime", "date":
+		return comparableOperators
+	case "bool", "boolean":
+		return boolOperators
+	case TypeUUID:
+		return uuidOperators
+	default:
+		return nil
+	}
+}
+
+// validateOperatorForType rejects built-in operators that make no sense for the field type
+// (e.g. like on int, gt on bool). Custom operators are accepted for any type.
+func validateOperatorForType(field, fieldType string, operator Operator) error {
+	if IsCustomOperator(operator) {
+		return nil
+	}
+	allowed := operatorsForType(fieldType)
+	if allowed == nil || allowed[operator] {
+		return nil
+	}
+	return errors.ValidationError(ErrCodeOperatorNotAllowed,
+		fmt.Sprintf("operator '%s' is not supported for field '%s' of type '%s'", operator, field, fieldType)).
+		WithMetadata("field", field).
+		WithMetadata("operator", string(operator)).
+		WithMetadata("type", fieldType)
+}
+
+// validateStrictValue checks values that convertSingleValue would otherwise accept loosely
+func validateStrictValue(field, fieldType string, operator Operator, value string) error {
+	switch strings.ToLower(fieldType) {
+	case "bool", "boolean":
+		switch strings.ToLower(value) {
+		case "true", "false", "1", "0":
+			return nil
+		}
+		return invalidValueError(field, value, "expected true, false, 1 or 0")
+	case TypeUUID:
+		if operator == OperatorLIKE {
+			return nil
+		}
+		if _, err := uuidx.Parse(value); err != nil {
+			return invalidValueError(field, value, "expected a UUID")
+		}
+	}
+	return nil
+}
+
+// invalidValueError builds a structured validation error for a bad filter value
+func invalidValueError(field, value, expected string) error {
+	return errors.ValidationError(ErrCodeInvalidFilterValues,
+		fmt.Sprintf("invalid value for field '%s': %s", field, expected)).
+		WithMetadata("field", field).
+		WithMetadata("value", value)
+}
+
+// validateInValuesCount enforces Config.MaxInValues
+func validateInValuesCount(field string, count int, config *Config) error {
+	if config == nil || config.MaxInValues <= 0 || count <= config.MaxInValues {
+		return nil
+	}
+	return errors.ValidationError(ErrCodeTooManyValues,
+		fmt.Sprintf("field '%s' accepts at most %d values, got %d", field, config.MaxInValues, count)).
+		WithMetadata("field", field).
+		WithMetadata("max", config.MaxInValues)
+}
+
+// validateFilterCount enforces Config.MaxFilters
+func validateFilterCount(count int, config *Config) error {
+	if config == nil || config.MaxFilters <= 0 || count <= config.MaxFilters {
+		return nil
+	}
+	return errors.ValidationError(ErrCodeTooManyFilters,
+		fmt.Sprintf("at most %d filters are allowed, got %d", config.MaxFilters, count)).
+		WithMetadata("max", config.MaxFilters)
+}
@@ -2,6 +2,7 @@ package hmac
 
 import (
 	"bytes"
+	"context"
 	"crypto/hmac"
 	"encoding/hex"
 	"encoding/json"
@@ -25,14 +26,24 @@ const (
 type Client struct {
 	BaseURL    string
 	HMACSecret string
-	HTTPClient *http.Client
+	Keyring    *Keyring // Optional: signs with the current key and sends its ID instead of HMACSecret
+	Scheme     SignatureScheme
+	Retry      RetryPolicy
+	// VerifyResponses requires responses to be signed by the server (MiddlewareOptions.SignResponses)
+	VerifyResponses bool
+	HTTPClient      *http.Client
 }
 
 // Config holds configuration for HMAC client
 type Config struct {
 	BaseURL    string
 	HMACSecret string
-	Timeout    time.Duration
+	Keyring    *Keyring        // Optional: rotating keys, used instead of HMACSecret
+	Scheme     SignatureScheme // Optional: canonicalization, algorithm and headers (zero value = original scheme)
+	Retry      RetryPolicy     // Optional: retries failed requests (disabled by default)
+	// VerifyResponses rejects responses without a valid signature of their body
+	VerifyResponses bool
+	Timeout         time.Duration
 }
 
 // NewClient creates a new HMAC HTTP client
@@ -43,8 +54,12 @@ func NewClient(config Config) *Client {
 	}
 
 	return &Client{
-		BaseURL:    config.BaseURL,
-		HMACSecret: config.HMACSecret,
+		BaseURL:         config.BaseURL,
+		HMACSecret:      config.HMACSecret,
+		Keyring:         config.Keyring,
+		Scheme:          config.Scheme,
+		Retry:           config.Retry,
+		VerifyResponses: config.VerifyResponses,
 		HTTPClient: &http.Client{
 			Timeout: timeout,
 		},
@@ -70,8 +85,12 @@ func ComputeSignature(method, path, query, timestamp string, body []byte, secret
 
 // DoRequest makes an HMAC-authenticated HTTP request
 func (c *Client) DoRequest(method, path string, body interface{}) (*http.Response, error) {
-	url := c.BaseURL + path
+	return c.DoRequestCtx(context.Background(), method, path, body)
+}
 
+// DoRequestCtx makes an HMAC-authenticated HTTP request bound to ctx, retrying it
+// according to the client's retry policy
+func (c *Client) DoRequestCtx(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
 	var bodyBytes []byte
 	var err error
 	if body != nil {
@@ -81,62 +100,101 @@ func (c *Client) DoRequest(method, path string, body interface{}) (*http.Respons
 		}
 	}
 
-	// Create request
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	return c.DoRequestWithBodyCtx(ctx, method, path, bodyBytes)
+}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	req.Header.Set(HeaderTimestamp, timestamp)
+// DoRequestWithBody makes an HMAC-authenticated HTTP request with raw body bytes
+func (c *Client) DoRequestWithBody(method, path string, bodyBytes []byte) (*http.Response, error) {
+	return c.DoRequestWithBodyCtx(context.Background(), method, path, bodyBytes)
+}
 
-	// Compute signature using the parsed URL components
-	// Extract path and query from the parsed URL to avoid double-inclusion
-	signaturePath := req.URL.Path
-	query := req.URL.RawQuery
-	signature := ComputeSignature(method, signaturePath, query, timestamp, bodyBytes, c.HMACSecret)
-	req.Header.Set(HeaderSignature, signature)
+// DoRequestWithBodyCtx makes an HMAC-authenticated HTTP request with raw body bytes bound
+// to ctx. Every attempt is signed with a fresh timestamp.
+func (c *Client) DoRequestWithBodyCtx(ctx context.Context, method, path string, bodyBytes []byte) (*http.Response, error) {
+	return c.send(ctx, method, func() (*http.Request, error) {
+		// Create request
+		req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewBuffer(bodyBytes))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	// Make request
-	resp, err := c.HTTPClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		// Set headers
+		req.Header.Set("Content-Type", "application/json")
+		if err := c.signRequest(req, bodyBytes); err != nil {
+			return nil, err
+		}
+		return req, nil
+	})
+}
+
+// send performs the requests built by newRequest until one succeeds or the retry
+// policy gives up
+func (c *Client) send(ctx context.Context, method string, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	retries := 0
+	if c.Retry.allows(method) {
+		retries = c.Retry.MaxRetries
 	}
 
-	return resp, nil
-}
+	for attempt := 0; ; attempt++ {
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
 
-// DoRequestWithBody makes an HMAC-authenticated HTTP request with raw body bytes
-func (c *Client) DoRequestWithBody(method, path string, bodyBytes []byte) (*http.Response, error) {
-	url := c.BaseURL + path
+		// Make request
+		resp, err := c.HTTPClient.Do(req)
+		if attempt >= retries || ctx.Err() != nil || !c.Retry.shouldRetry(resp, err) {
+			if err != nil {
+				return nil, fmt.Errorf("failed to make request: %w", err)
+			}
+			if c.VerifyResponses {
+				return c.verifyResponse(resp)
+			}
+			return resp, nil
+		}
 
-	// Create request
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(bodyBytes))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		delay := c.Retry.delay(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleepCtx(ctx, delay); err != nil {
+			return nil, fmt.Errorf("failed to make request: %w", err)
+		}
 	}
+}
+
+// signRequest sets the timestamp, signature and (with a keyring) key ID headers
+func (c *Client) signRequest(req *http.Request, bodyBytes []byte) error {
+	return signRequest(req, bodyBytes, c.Scheme, c.HMACSecret, c.Keyring)
+}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
+// signRequest signs req using scheme with secret, or with the current key of keyring if it is set
+func signRequest(req *http.Request, bodyBytes []byte, scheme SignatureScheme, secret string, keyring *Keyring) error {
 	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
-	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(scheme.timestampHeader(), timestamp)
+
+	if keyring != nil {
+		key := keyring.Current()
+		secret = key.Secret
+		req.Header.Set(scheme.keyIDHeader(), key.ID)
+	}
 
 	// Compute signature using the parsed URL components
 	// Extract path and query from the parsed URL to avoid double-inclusion
-	signaturePath := req.URL.Path
-	query := req.URL.RawQuery
-	signature := ComputeSignature(method, signaturePath, query, timestamp, bodyBytes, c.HMACSecret)
-	req.Header.Set(HeaderSignature, signature)
-
-	// Make request
-	resp, err := c.HTTPClient.Do(req)
+	signature, err := scheme.Sign(CanonicalRequest{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Query:     req.URL.RawQuery,
+		Timestamp: timestamp,
+		Body:      bodyBytes,
+		Header:    req.Header.Get,
+	}, secret)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return fmt.Errorf("failed to sign request: %w", err)
 	}
-
-	return resp, nil
+	req.Header.Set(scheme.signatureHeader(), signature)
+	return nil
 }
 
 // ParseJSONResponse parses a JSON response from an HTTP response
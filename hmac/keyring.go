@@ -0,0 +1,120 @@
+package hmac
+
+import (
+	"sync"
+	"time"
+)
+
+// HeaderKeyID is the header identifying the key a request was signed with
+const HeaderKeyID = "X-Key-Id"
+
+// Key is a signing secret identified by ID
+type Key struct {
+	ID        string
+	Secret    string
+	ExpiresAt time.Time // Zero means the key does not expire; expired keys are no longer accepted
+}
+
+// expired reports whether the key may no longer be used for verification
+func (k Key) expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && now.After(k.ExpiresAt)
+}
+
+// Keyring holds the current signing key and previous keys that are still accepted, so secrets
+// can be rotated with an overlap period. It is safe for concurrent use.
+type Keyring struct {
+	mu       sync.RWMutex
+	current  Key
+	previous []Key
+}
+
+// NewKeyring creates a keyring signing with current and also accepting previous keys
+func NewKeyring(current Key, previous ...Key) *Keyring {
+	return &Keyring{current: current, previous: previous}
+}
+
+// Current returns the key used for signing
+func (k *Keyring) Current() Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.current
+}
+
+// Rotate makes next the signing key; the former current key stays accepted for overlap
+// (forever if overlap <= 0, until removed)
+func (k *Keyring) Rotate(next Key, overlap time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	former := k.current
+	if overlap > 0 {
+		former.ExpiresAt = time.Now().Add(overlap)
+	}
+	k.previous = append([]Key{former}, k.removeLocked(next.ID)...)
+	k.current = next
+}
+
+// Remove stops accepting the previous key with the given ID
+func (k *Keyring) Remove(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.previous = k.removeLocked(id)
+}
+
+// removeLocked returns the previous keys without id and without expired keys
+func (k *Keyring) removeLocked(id string) []Key {
+	now := time.Now()
+	kept := make([]Key, 0, len(k.previous))
+	for _, key := range k.previous {
+		if key.ID != id && !key.expired(now) {
+			kept = append(kept, key)
+		}
+	}
+	return kept
+}
+
+// candidates returns the unexpired keys to verify with: the one identified by keyID first,
+// then the others, newest first
+func (k *Keyring) candidates(keyID string) []Key {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	now := time.Now()
+	keys := make([]Key, 0, len(k.previous)+1)
+	for _, key := range append([]Key{k.current}, k.previous...) {
+		if key.expired(now) {
+			continue
+		}
+		if keyID != "" && key.ID == keyID {
+			keys = append([]Key{key}, keys...)
+		} else {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// ValidateSignature validates an HMAC signature against the key identified by keyID first,
+// then any other still-valid key, and returns the matching key
+func (k *Keyring) ValidateSignature(keyID, method, path, query, timestamp string, body []byte, signature string) (Key, bool) {
+	return k.validate(keyID, func(secret string) bool {
+		return ValidateSignature(method, path, query, timestamp, body, signature, secret)
+	})
+}
+
+// ValidateScheme validates a signature header value of scheme like ValidateSignature
+func (k *Keyring) ValidateScheme(scheme SignatureScheme, keyID string, r CanonicalRequest, signature string) (Key, bool) {
+	return k.validate(keyID, func(secret string) bool {
+		return scheme.Validate(r, signature, secret)
+	})
+}
+
+// validate returns the first candidate key whose secret passes valid
+func (k *Keyring) validate(keyID string, valid func(secret string) bool) (Key, bool) {
+	for _, key := range k.candidates(keyID) {
+		if valid(key.Secret) {
+			return key, true
+		}
+	}
+	return Key{}, false
+}
@@ -0,0 +1,182 @@
+package hmac
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kerimovok/go-pkg-utils/httpx"
+)
+
+// DefaultMaxClockSkew is how far X-Timestamp may be from the server clock by default
+const DefaultMaxClockSkew = 5 * time.Minute
+
+// MiddlewareOptions configures FiberMiddleware
+type MiddlewareOptions struct {
+	// MaxClockSkew is the accepted age (or future drift) of X-Timestamp - defaults to DefaultMaxClockSkew
+	MaxClockSkew time.Duration
+	// Skip excludes requests from verification (e.g. health checks)
+	Skip func(c *fiber.Ctx) bool
+	// Keyring verifies with rotating keys (X-Key-Id first, then other valid keys) instead of the secret
+	Keyring *Keyring
+	// Schemes are the accepted signature schemes, matched by signature header and version -
+	// defaults to the original scheme only
+	Schemes []SignatureScheme
+	// SignResponses signs every response body (with the request's scheme) for clients
+	// verifying responses (Config.VerifyResponses)
+	SignResponses bool
+}
+
+// FiberMiddleware verifies the signature and timestamp of incoming requests signed by Client
+// with secret (or a key of opts.Keyring).
+// The canonical string is rebuilt from the request method, path, raw query and body, and
+// requests that are unsigned, stale or tampered with are rejected with 401 Unauthorized.
+// Requests carrying X-Content-SHA256 are verified with the streaming (digest) scheme.
+func FiberMiddleware(secret string, opts MiddlewareOptions) fiber.Handler {
+	if opts.MaxClockSkew <= 0 {
+		opts.MaxClockSkew = DefaultMaxClockSkew
+	}
+	if len(opts.Schemes) == 0 {
+		opts.Schemes = []SignatureScheme{{}}
+	}
+
+	verify := func(c *fiber.Ctx) error {
+		if opts.Skip != nil && opts.Skip(c) {
+			return c.Next()
+		}
+
+		scheme, signature, ok := detectScheme(c, opts.Schemes)
+		if signature == "" {
+			return httpx.SendResponse(c, httpx.Unauthorized("Missing request signature"))
+		}
+		if !ok {
+			return httpx.SendResponse(c, httpx.Unauthorized("Unsupported signature scheme"))
+		}
+
+		timestamp := c.Get(scheme.timestampHeader())
+		if timestamp == "" {
+			return httpx.SendResponse(c, httpx.Unauthorized("Missing request signature"))
+		}
+		if err := checkTimestamp(timestamp, opts.MaxClockSkew); errors.Is(err, ErrInvalidTimestamp) {
+			return httpx.SendResponse(c, httpx.Unauthorized("Invalid request timestamp"))
+		} else if err != nil {
+			return httpx.SendResponse(c, httpx.Unauthorized("Request timestamp outside the allowed window"))
+		}
+
+		// Bodies signed by digest: check the body against it and verify the digest
+		body := c.Body()
+		if bodyHash := c.Get(HeaderContentSHA256); bodyHash != "" {
+			if actual, _ := HashBody(bytes.NewReader(body)); actual != bodyHash {
+				return httpx.SendResponse(c, httpx.Unauthorized("Request body does not match its digest"))
+			}
+			body = digestComponent(bodyHash)
+		}
+
+		request := CanonicalRequest{
+			Method:    c.Method(),
+			Path:      string(c.Request().URI().Path()),
+			Query:     string(c.Request().URI().QueryString()),
+			Timestamp: timestamp,
+			Body:      body,
+			Header:    func(name string) string { return c.Get(name) },
+		}
+		valid := false
+		if opts.Keyring != nil {
+			_, valid = opts.Keyring.ValidateScheme(scheme, c.Get(scheme.keyIDHeader()), request, signature)
+		} else {
+			valid = scheme.Validate(request, signature, secret)
+		}
+		if !valid {
+			return httpx.SendResponse(c, httpx.Unauthorized("Invalid request signature"))
+		}
+
+		return c.Next()
+	}
+	if !opts.SignResponses {
+		return verify
+	}
+
+	return func(c *fiber.Ctx) error {
+		// Let the error handler write the response now, so it is signed too
+		if err := verify(c); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				return err
+			}
+		}
+
+		scheme, _, ok := detectScheme(c, opts.Schemes)
+		if !ok {
+			scheme = opts.Schemes[0]
+		}
+		return signResponse(c, scheme, secret, opts.Keyring)
+	}
+}
+
+// signResponse signs the response body bound to the request method, path and query
+func signResponse(c *fiber.Ctx, scheme SignatureScheme, secret string, keyring *Keyring) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	if keyring != nil {
+		key := keyring.Current()
+		secret = key.Secret
+		c.Set(scheme.keyIDHeader(), key.ID)
+	}
+
+	signature, err := responseScheme(scheme).Sign(CanonicalRequest{
+		Method:    c.Method(),
+		Path:      string(c.Request().URI().Path()),
+		Query:     string(c.Request().URI().QueryString()),
+		Timestamp: timestamp,
+		Body:      c.Response().Body(),
+	}, secret)
+	if err != nil {
+		return fmt.Errorf("failed to sign response: %w", err)
+	}
+	c.Set(scheme.timestampHeader(), timestamp)
+	c.Set(scheme.signatureHeader(), signature)
+	return nil
+}
+
+// detectScheme returns the first accepted scheme whose signature header is set and matches its
+// version, along with the signature (empty if no scheme's header is set)
+func detectScheme(c *fiber.Ctx, schemes []SignatureScheme) (SignatureScheme, string, bool) {
+	var first string
+	for _, scheme := range schemes {
+		signature := c.Get(scheme.signatureHeader())
+		if signature == "" {
+			continue
+		}
+		if _, ok := scheme.matches(signature); ok {
+			return scheme, signature, true
+		}
+		if first == "" {
+			first = signature
+		}
+	}
+	return SignatureScheme{}, first, false
+}
+
+// Timestamp verification errors
+var (
+	ErrInvalidTimestamp = errors.New("invalid timestamp")
+	ErrTimestampExpired = errors.New("timestamp outside the allowed window")
+)
+
+// checkTimestamp checks that a Unix timestamp is within maxSkew of the server clock
+func checkTimestamp(timestamp string, maxSkew time.Duration) error {
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	skew := time.Since(time.Unix(unix, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxSkew {
+		return ErrTimestampExpired
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package hmac
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+
+	"github.com/kerimovok/go-pkg-utils/crypto"
+)
+
+// Sign computes a hex-encoded HMAC-SHA256 signature of arbitrary data
+// (tokens, cursors, exported payloads) outside of the HTTP request scheme
+func Sign(data []byte, secret string) string {
+	return hex.EncodeToString(crypto.HMACSHA256(data, []byte(secret)))
+}
+
+// Verify validates a signature produced by Sign using constant-time comparison
+func Verify(data []byte, signature, secret string) bool {
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(signatureBytes, crypto.HMACSHA256(data, []byte(secret)))
+}
@@ -0,0 +1,58 @@
+package hmac
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrInvalidResponseSignature is returned for responses that are unsigned, stale or tampered
+// with when the client verifies responses
+var ErrInvalidResponseSignature = errors.New("invalid response signature")
+
+// responseScheme returns the scheme responses are signed with: request headers are not signed
+func responseScheme(scheme SignatureScheme) SignatureScheme {
+	scheme.SignedHeaders = nil
+	return scheme
+}
+
+// verifyResponse checks the signature of a response body, which is bound to the request
+// method, path and query. The body is buffered and remains readable by the caller.
+func (c *Client) verifyResponse(resp *http.Response) (*http.Response, error) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	signature := resp.Header.Get(c.Scheme.signatureHeader())
+	timestamp := resp.Header.Get(c.Scheme.timestampHeader())
+	if signature == "" || timestamp == "" {
+		return nil, fmt.Errorf("%w: missing signature (status %d)", ErrInvalidResponseSignature, resp.StatusCode)
+	}
+	if err := checkTimestamp(timestamp, DefaultMaxClockSkew); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidResponseSignature, err)
+	}
+
+	request := CanonicalRequest{
+		Method:    resp.Request.Method,
+		Path:      resp.Request.URL.Path,
+		Query:     resp.Request.URL.RawQuery,
+		Timestamp: timestamp,
+		Body:      body,
+	}
+	scheme := responseScheme(c.Scheme)
+	valid := false
+	if c.Keyring != nil {
+		_, valid = c.Keyring.ValidateScheme(scheme, resp.Header.Get(c.Scheme.keyIDHeader()), request, signature)
+	} else {
+		valid = scheme.Validate(request, signature, c.HMACSecret)
+	}
+	if !valid {
+		return nil, fmt.Errorf("%w (status %d)", ErrInvalidResponseSignature, resp.StatusCode)
+	}
+	return resp, nil
+}
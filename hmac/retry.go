@@ -0,0 +1,103 @@
+package hmac
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures retries of failed requests. The zero value disables retries.
+// Only idempotent methods are retried unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	MaxRetries         int            // Retries after the first attempt - 0 disables retries
+	InitialDelay       time.Duration  // Delay before the first retry - defaults to 200ms
+	MaxDelay           time.Duration  // Cap of the exponential backoff and of Retry-After - defaults to 10s
+	RetryNonIdempotent bool           // Also retry POST and PATCH requests
+	RetryableStatus    func(int) bool // Response statuses to retry - defaults to DefaultRetryableStatus
+}
+
+// DefaultRetryableStatus retries 429 Too Many Requests and 502, 503 and 504 responses
+func DefaultRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// allows reports whether requests with method may be retried
+func (p RetryPolicy) allows(method string) bool {
+	if p.MaxRetries <= 0 {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return p.RetryNonIdempotent
+	}
+}
+
+// shouldRetry reports whether an attempt failed with a transport error or retryable status
+func (p RetryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	if p.RetryableStatus != nil {
+		return p.RetryableStatus(resp.StatusCode)
+	}
+	return DefaultRetryableStatus(resp.StatusCode)
+}
+
+// delay returns the wait before retrying attempt: the server's Retry-After if present,
+// otherwise exponential backoff with full jitter, capped at MaxDelay
+func (p RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	initial, maxDelay := p.InitialDelay, p.MaxDelay
+	if initial <= 0 {
+		initial = 200 * time.Millisecond
+	}
+	if maxDelay <= 0 {
+		maxDelay = 10 * time.Second
+	}
+
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(retryAfter, maxDelay)
+		}
+	}
+
+	backoff := maxDelay
+	if attempt < 30 && initial<<attempt < maxDelay {
+		backoff = initial << attempt
+	}
+	return time.Duration(rand.Int63n(int64(backoff) + 1))
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// sleepCtx waits for d or until ctx is done
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,53 @@
+package hmac
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Transport is an http.RoundTripper signing every outgoing request like Client does, so
+// existing http.Client-based code (including generated API clients) gains HMAC auth
+type Transport struct {
+	Secret  string
+	Keyring *Keyring          // Optional: signs with the current key and sends its ID instead of Secret
+	Scheme  SignatureScheme   // Optional: canonicalization, algorithm and headers (zero value = original scheme)
+	Next    http.RoundTripper // Transport performing the request - defaults to http.DefaultTransport
+}
+
+// NewTransport creates a transport signing requests with secret before passing them to next
+// (http.DefaultTransport if nil)
+func NewTransport(secret string, next http.RoundTripper) *Transport {
+	return &Transport{Secret: secret, Next: next}
+}
+
+// RoundTrip signs a copy of req (the original is not modified) and sends it
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var bodyBytes []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		bodyBytes, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	signed := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		signed.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+		signed.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(bodyBytes)), nil
+		}
+	}
+	if err := signRequest(signed, bodyBytes, t.Scheme, t.Secret, t.Keyring); err != nil {
+		return nil, err
+	}
+
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return next.RoundTrip(signed)
+}
@@ -0,0 +1,112 @@
+package hmac
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kerimovok/go-pkg-utils/errors"
+)
+
+// envelope is the httpx.Response envelope with the data left undecoded
+type envelope struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+	Status  int             `json:"status"`
+}
+
+// Get performs a signed GET request and decodes the data of the httpx.Response envelope into T
+func Get[T any](c *Client, path string) (T, error) {
+	return Do[T](context.Background(), c, http.MethodGet, path, nil)
+}
+
+// Post performs a signed POST request with a JSON body and decodes the envelope data into T
+func Post[T any](c *Client, path string, body interface{}) (T, error) {
+	return Do[T](context.Background(), c, http.MethodPost, path, body)
+}
+
+// Do performs a signed request and decodes the data of the httpx.Response envelope into T.
+// Error responses are returned as *errors.Error values carrying the HTTP status, the API
+// message and, in Details, the API error.
+func Do[T any](ctx context.Context, c *Client, method, path string, body interface{}) (T, error) {
+	var data T
+
+	resp, err := c.DoRequestCtx(ctx, method, path, body)
+	if err != nil {
+		return data, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return data, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(bodyBytes, &env); err != nil {
+		if resp.StatusCode >= 400 {
+			return data, apiError(resp.StatusCode, http.StatusText(resp.StatusCode), "")
+		}
+		return data, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 || !env.Success {
+		status := resp.StatusCode
+		if status < 400 && env.Status >= 400 {
+			status = env.Status
+		}
+		return data, apiError(status, env.Message, env.Error)
+	}
+
+	if len(env.Data) > 0 && string(env.Data) != "null" {
+		if err := json.Unmarshal(env.Data, &data); err != nil {
+			return data, fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// apiError converts an error response into an *errors.Error of the matching type
+func apiError(status int, message, details string) *errors.Error {
+	var errorType errors.ErrorType
+	switch {
+	case status == http.StatusBadRequest:
+		errorType = errors.ErrorTypeBadRequest
+	case status == http.StatusUnauthorized:
+		errorType = errors.ErrorTypeUnauthorized
+	case status == http.StatusForbidden:
+		errorType = errors.ErrorTypeForbidden
+	case status == http.StatusNotFound:
+		errorType = errors.ErrorTypeNotFound
+	case status == http.StatusConflict:
+		errorType = errors.ErrorTypeConflict
+	case status == http.StatusUnprocessableEntity:
+		errorType = errors.ErrorTypeValidation
+	case status == http.StatusTooManyRequests:
+		errorType = errors.ErrorTypeRateLimit
+	case status == http.StatusServiceUnavailable:
+		errorType = errors.ErrorTypeServiceUnavailable
+	case status == http.StatusGatewayTimeout || status == http.StatusRequestTimeout:
+		errorType = errors.ErrorTypeTimeout
+	case status >= 500:
+		errorType = errors.ErrorTypeExternal
+	default:
+		errorType = errors.ErrorTypeBadRequest
+	}
+
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	err := errors.NewError(errorType, fmt.Sprintf("HTTP_%d", status), message).WithHTTPStatus(status)
+	if details != "" {
+		err = err.WithDetails(details)
+	}
+	if DefaultRetryableStatus(status) {
+		err = err.MarkRetryable()
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package hmac
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/crypto"
+)
+
+// Webhook signature headers
+const (
+	// HeaderWebhookSignature carries signatures produced by SignWebhook ("t=<unix>,v1=<hex>")
+	HeaderWebhookSignature = "X-Webhook-Signature"
+	// HeaderStripeSignature is Stripe's webhook signature header
+	HeaderStripeSignature = "Stripe-Signature"
+	// HeaderGitHubSignature is GitHub's webhook signature header ("sha256=<hex>")
+	HeaderGitHubSignature = "X-Hub-Signature-256"
+)
+
+// DefaultWebhookTolerance is the accepted age of timestamped webhook signatures by default
+const DefaultWebhookTolerance = 5 * time.Minute
+
+// ErrInvalidWebhookSignature is returned when a webhook signature is malformed or doesn't match
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// SignWebhook signs an outbound webhook payload in the Stripe-style timestamped format:
+// "t=<unix>,v1=<hex HMAC-SHA256(t + "." + payload)>"
+func SignWebhook(payload []byte, secret string, timestamp time.Time) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", t, hex.EncodeToString(crypto.HMACSHA256(timestampedPayload(t, payload), []byte(secret))))
+}
+
+// VerifyWebhook verifies a signature produced by SignWebhook, accepting it if any v1 value
+// matches and the timestamp is within tolerance (DefaultWebhookTolerance if <= 0)
+func VerifyWebhook(payload []byte, header, secret string, tolerance time.Duration) error {
+	return verifyTimestamped(payload, header, "v1", secret, tolerance)
+}
+
+// VerifyStripeSignature verifies a Stripe-Signature header; Stripe uses the same format as
+// SignWebhook with the endpoint's signing secret
+func VerifyStripeSignature(payload []byte, header, secret string, tolerance time.Duration) error {
+	return verifyTimestamped(payload, header, "v1", secret, tolerance)
+}
+
+// VerifyGitHubSignature verifies an X-Hub-Signature-256 header ("sha256=<hex HMAC-SHA256(payload)>")
+func VerifyGitHubSignature(payload []byte, header, secret string) error {
+	signature, ok := strings.CutPrefix(header, "sha256=")
+	if !ok || !Verify(payload, signature, secret) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// VerifyBase64Signature verifies a base64-encoded HMAC-SHA256 of the payload, the format used
+// by providers such as Shopify (X-Shopify-Hmac-Sha256)
+func VerifyBase64Signature(payload []byte, signature, secret string) error {
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(signatureBytes, crypto.HMACSHA256(payload, []byte(secret))) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
+
+// verifyTimestamped verifies a "t=<unix>,<scheme>=<hex>,..." header
+func verifyTimestamped(payload []byte, header, scheme, secret string, tolerance time.Duration) error {
+	if tolerance <= 0 {
+		tolerance = DefaultWebhookTolerance
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case scheme:
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidWebhookSignature
+	}
+	if err := checkTimestamp(timestamp, tolerance); err != nil {
+		return fmt.Errorf("webhook %w", err)
+	}
+
+	signed := timestampedPayload(timestamp, payload)
+	for _, signature := range signatures {
+		if Verify(signed, signature, secret) {
+			return nil
+		}
+	}
+	return ErrInvalidWebhookSignature
+}
+
+// timestampedPayload returns the signed message t + "." + payload
+func timestampedPayload(timestamp string, payload []byte) []byte {
+	return append([]byte(timestamp+"."), payload...)
+}
@@ -0,0 +1,158 @@
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// EnvelopeVersionHeader lets clients request a specific envelope version
+const EnvelopeVersionHeader = "X-Envelope-Version"
+
+// EnvelopeProfile describes how the envelope is emitted for one schema version.
+// Field maps rename canonical JSON keys (as declared on the response structs) to the
+// names the profile's clients expect; keys inside data are never touched.
+type EnvelopeProfile struct {
+	// Version is written to the envelope's "version" field
+	Version string
+	// Fields renames top-level envelope keys (e.g. "validation_errors" -> "errors")
+	Fields map[string]string
+	// PaginationFields renames keys of the pagination block (e.g. "perPage" -> "per_page")
+	PaginationFields map[string]string
+}
+
+var (
+	// ProfileV1 is the legacy envelope: camelCase pagination keys and validation_errors
+	ProfileV1 = &EnvelopeProfile{Version: "1"}
+
+	// ProfileV2 uses snake_case pagination keys and errors for validation errors
+	ProfileV2 = &EnvelopeProfile{
+		Version: "2",
+		Fields: map[string]string{
+			"validation_errors": "errors",
+		},
+		PaginationFields: map[string]string{
+			"perPage":      "per_page",
+			"totalPages":   "total_pages",
+			"hasNext":      "has_next",
+			"hasPrevious":  "has_previous",
+			"nextPage":     "next_page",
+			"previousPage": "previous_page",
+			"nextCursor":   "next_cursor",
+		},
+	}
+)
+
+var (
+	profilesMu     sync.RWMutex
+	profiles       = map[string]*EnvelopeProfile{"1": ProfileV1, "2": ProfileV2}
+	defaultProfile *EnvelopeProfile
+)
+
+// RegisterProfile registers (or replaces) an envelope profile under its version
+func RegisterProfile(profile *EnvelopeProfile) {
+	if profile == nil || profile.Version == "" {
+		panic("httpx: envelope profile version is required")
+	}
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	profiles[profile.Version] = profile
+}
+
+// LookupProfile returns the registered profile for a version
+func LookupProfile(version string) (*EnvelopeProfile, bool) {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	profile, ok := profiles[version]
+	return profile, ok
+}
+
+// SetDefaultProfile sets the profile used when a request does not ask for a version.
+// With no default profile (nil) responses are emitted unchanged and without a version field.
+func SetDefaultProfile(profile *EnvelopeProfile) {
+	profilesMu.Lock()
+	defer profilesMu.Unlock()
+	defaultProfile = profile
+}
+
+// ResolveProfile returns the profile requested via EnvelopeVersionHeader,
+// falling back to the default profile for missing or unknown versions
+func ResolveProfile(c *fiber.Ctx) *EnvelopeProfile {
+	if version := c.Get(EnvelopeVersionHeader); version != "" {
+		if profile, ok := LookupProfile(version); ok {
+			return profile
+		}
+	}
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	return defaultProfile
+}
+
+// version returns the profile version (empty for a nil profile)
+func (p *EnvelopeProfile) version() string {
+	if p == nil {
+		return ""
+	}
+	return p.Version
+}
+
+// Transform marshals a response and renames envelope keys according to the profile
+func (p *EnvelopeProfile) Transform(response interface{}) (interface{}, error) {
+	if p == nil || (len(p.Fields) == 0 && len(p.PaginationFields) == 0) {
+		return response, nil
+	}
+
+	raw, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response envelope: %w", err)
+	}
+
+	// The applied query echo shares the page/perPage keys of the pagination block
+	for _, key := range []string{"pagination", "query"} {
+		block, ok := envelope[key]
+		if !ok || len(p.PaginationFields) == 0 {
+			continue
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(block, &fields); err == nil && fields != nil {
+			renamed, err := json.Marshal(renameKeys(fields, p.PaginationFields))
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal %s: %w", key, err)
+			}
+			envelope[key] = renamed
+		}
+	}
+
+	return renameKeys(envelope, p.Fields), nil
+}
+
+// renameKeys returns a copy of m with keys renamed according to names
+func renameKeys(m map[string]json.RawMessage, names map[string]string) map[string]json.RawMessage {
+	if len(names) == 0 {
+		return m
+	}
+	renamed := make(map[string]json.RawMessage, len(m))
+	for key, value := range m {
+		if name, ok := names[key]; ok {
+			key = name
+		}
+		renamed[key] = value
+	}
+	return renamed
+}
+
+// sendWithProfile writes a response shaped by the profile resolved for the request
+func sendWithProfile(c *fiber.Ctx, status int, profile *EnvelopeProfile, response interface{}) error {
+	body, err := profile.Transform(response)
+	if err != nil {
+		return err
+	}
+	return c.Status(status).JSON(body)
+}
@@ -0,0 +1,61 @@
+package httpx
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PageParam is the query parameter Link headers set to address pages
+const PageParam = "page"
+
+// Link is a target of an RFC 8288 Link header
+type Link struct {
+	URL string
+	Rel string
+}
+
+// FormatLinks formats links as a Link header value: <url>; rel="next", ...
+func FormatLinks(links []Link) string {
+	parts := make([]string, len(links))
+	for i, link := range links {
+		parts[i] = fmt.Sprintf(`<%s>; rel="%s"`, link.URL, link.Rel)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RequestURLWith returns the request path with its query parameters, overriding params.
+// The URL is relative to the request, so it is correct behind proxies that rewrite the host.
+func RequestURLWith(c *fiber.Ctx, params map[string]string) string {
+	query, _ := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if query == nil {
+		query = url.Values{}
+	}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+	return c.Path() + "?" + query.Encode()
+}
+
+// PaginationLinks returns the first, prev, next and last links of page-based pagination
+// (last is omitted when the total is unknown)
+func PaginationLinks(c *fiber.Ctx, pagination *Pagination) []Link {
+	page := func(n int) string {
+		return RequestURLWith(c, map[string]string{PageParam: strconv.Itoa(n)})
+	}
+
+	links := []Link{{URL: page(1), Rel: "first"}}
+	if pagination.PreviousPage != nil {
+		links = append(links, Link{URL: page(*pagination.PreviousPage), Rel: "prev"})
+	}
+	if pagination.NextPage != nil {
+		links = append(links, Link{URL: page(*pagination.NextPage), Rel: "next"})
+	}
+	if pagination.TotalPages > 0 {
+		links = append(links, Link{URL: page(pagination.TotalPages), Rel: "last"})
+	}
+	return links
+}
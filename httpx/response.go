@@ -15,12 +15,36 @@ type Response struct {
 	Error     string      `json:"error,omitempty"`
 	Status    int         `json:"status"`
 	Timestamp time.Time   `json:"timestamp"`
+	Version   string      `json:"version,omitempty"` // Envelope schema version, set from the resolved EnvelopeProfile
 }
 
 // PaginatedResponse represents a paginated API response
 type PaginatedResponse struct {
 	Response
-	Pagination *Pagination `json:"pagination,omitempty"`
+	Pagination *Pagination   `json:"pagination,omitempty"`
+	Query      *AppliedQuery `json:"query,omitempty"`
+}
+
+// AppliedQuery echoes the effective query a paginated response was produced with, after
+// defaults and limits were applied
+type AppliedQuery struct {
+	Page    int             `json:"page,omitempty"`
+	PerPage int             `json:"perPage"`
+	Sort    []AppliedSort   `json:"sort,omitempty"`
+	Filters []AppliedFilter `json:"filters,omitempty"`
+}
+
+// AppliedSort is a sort field of an AppliedQuery
+type AppliedSort struct {
+	Field string `json:"field"`
+	Order string `json:"order"` // "asc" or "desc"
+}
+
+// AppliedFilter is a filter of an AppliedQuery
+type AppliedFilter struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
 }
 
 // Pagination contains pagination metadata
@@ -91,16 +115,47 @@ func NewPagination(page, perPage int, total int64) *Pagination {
 // SendResponse sends a response using Fiber context
 func SendResponse(c *fiber.Ctx, response Response) error {
 	datetime.NormalizeTimeFieldsToUTC(&response)
-	return c.Status(response.Status).JSON(response)
+	profile := ResolveProfile(c)
+	response.Version = profile.version()
+	return sendWithProfile(c, response.Status, profile, response)
 }
 
-// SendPaginatedResponse sends a paginated response using Fiber context
+// SendPaginatedResponse sends a paginated response using Fiber context, with an RFC 8288
+// Link header addressing the first, previous, next and last pages
 func SendPaginatedResponse(c *fiber.Ctx, response PaginatedResponse) error {
+	if response.Pagination != nil {
+		c.Set(fiber.HeaderLink, FormatLinks(PaginationLinks(c, response.Pagination)))
+	}
 	datetime.NormalizeTimeFieldsToUTC(&response)
-	return c.Status(response.Status).JSON(response)
+	profile := ResolveProfile(c)
+	response.Version = profile.version()
+	return sendWithProfile(c, response.Status, profile, response)
+}
+
+// Envelope is a response envelope embedding Response, such as a paginated response of
+// another package (e.g. pagination.CursorResponse)
+type Envelope interface {
+	envelope() *Response
+}
+
+// envelope returns the embedded response of an Envelope
+func (r *Response) envelope() *Response {
+	return r
+}
+
+// SendEnvelope sends a custom envelope (passed as a pointer) in the envelope profile
+// requested by the client, like SendResponse
+func SendEnvelope(c *fiber.Ctx, envelope Envelope) error {
+	datetime.NormalizeTimeFieldsToUTC(envelope)
+	response := envelope.envelope()
+	profile := ResolveProfile(c)
+	response.Version = profile.version()
+	return sendWithProfile(c, response.Status, profile, envelope)
 }
 
 // SendValidationResponse sends a validation error response using Fiber context
 func SendValidationResponse(c *fiber.Ctx, response ValidationResponse) error {
-	return c.Status(response.Status).JSON(response)
+	profile := ResolveProfile(c)
+	response.Version = profile.version()
+	return sendWithProfile(c, response.Status, profile, response)
 }
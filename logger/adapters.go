@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// Component returns logger with a component field, e.g. for lua.ExecutorConfig.Logger:
+// logger.Component(log, "lua")
+func Component(logger *zap.Logger, name string) *zap.Logger {
+	return logger.With(zap.String("component", name))
+}
+
+// PrintfLogger adapts a zap logger to Printf-style logging, such as queue.Logger
+type PrintfLogger struct {
+	logger *zap.Logger
+}
+
+// NewPrintfLogger creates a Printf adapter writing to logger with a component field (if set).
+// Messages with an error argument are logged at error level with the error as a field, the
+// others at info level.
+func NewPrintfLogger(logger *zap.Logger, component string) *PrintfLogger {
+	if component != "" {
+		logger = Component(logger, component)
+	}
+	return &PrintfLogger{logger: logger.WithOptions(zap.AddCallerSkip(1))}
+}
+
+// QueueLogger returns the adapter for queue.SetLogger, reporting the queue packages'
+// call sites as callers: queue.SetLogger(logger.QueueLogger(log))
+func QueueLogger(logger *zap.Logger) *PrintfLogger {
+	// Skip queue.Logf as well
+	return NewPrintfLogger(logger.WithOptions(zap.AddCallerSkip(1)), "queue")
+}
+
+// Printf logs a formatted message
+func (p *PrintfLogger) Printf(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			p.logger.Error(message, zap.Error(err))
+			return
+		}
+	}
+	p.logger.Info(message)
+}
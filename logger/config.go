@@ -1,5 +1,7 @@
 package logger
 
+import "time"
+
 // Config holds logging configuration
 type Config struct {
 	Enabled    *bool  `yaml:"enabled"`
@@ -8,6 +10,64 @@ type Config struct {
 	MaxBackups int    `yaml:"max_backups"` // Max number of backup files to retain
 	MaxAge     int    `yaml:"max_age"`     // Max age of backup files in days
 	Level      string `yaml:"level"`       // Log level: debug, info, warn, error (default: info)
+
+	Sampling  *SamplingConfig  `yaml:"sampling"`   // Optional: zap sampling of repeated entries
+	RateLimit *RateLimitConfig `yaml:"rate_limit"` // Optional: per-message rate limit
+
+	// Redact masks the values of fields by key, e.g. {"email": "email", "password": "full"}
+	// (masks: email, phone, card, name, full)
+	Redact map[string]string `yaml:"redact"`
+
+	// Sinks replaces the FilePath/stdout outputs with any combination of outputs
+	Sinks []SinkConfig `yaml:"sinks"`
+}
+
+// Sink types
+const (
+	SinkStdout = "stdout"
+	SinkStderr = "stderr"
+	SinkFile   = "file"
+	SinkSyslog = "syslog"
+	SinkOTLP   = "otlp" // OTLP/HTTP JSON logs endpoint (e.g. http://collector:4318/v1/logs)
+	SinkLoki   = "loki" // Loki push API (e.g. http://loki:3100/loki/api/v1/push)
+)
+
+// SinkConfig configures one log output
+type SinkConfig struct {
+	Type     string `yaml:"type"`     // One of the Sink* types
+	Level    string `yaml:"level"`    // Fixed minimum level of this sink, not changed by SetLevel (default: the logger's level)
+	Encoding string `yaml:"encoding"` // json or console (default json)
+
+	// file: Path, rotated according to Config.MaxSize, MaxBackups and MaxAge
+	Path string `yaml:"path"`
+
+	// syslog: Network and Address of the daemon (default: local syslog), Tag (default: program name)
+	Network string `yaml:"network"`
+	Address string `yaml:"address"`
+	Tag     string `yaml:"tag"`
+
+	// otlp and loki
+	Endpoint      string            `yaml:"endpoint"`
+	Headers       map[string]string `yaml:"headers"`        // e.g. Authorization or X-Scope-OrgID
+	Labels        map[string]string `yaml:"labels"`         // Loki stream labels / OTLP resource attributes
+	BatchSize     int               `yaml:"batch_size"`     // Entries per push (default 100)
+	FlushInterval time.Duration     `yaml:"flush_interval"` // Max delay of a push (default 1s)
+	Timeout       time.Duration     `yaml:"timeout"`        // Push timeout (default 5s)
+}
+
+// SamplingConfig configures zap sampling: per Tick, the first Initial entries with the same
+// level and message are logged, then every Thereafter-th
+type SamplingConfig struct {
+	Initial    int           `yaml:"initial"`    // Default 100
+	Thereafter int           `yaml:"thereafter"` // Default 100
+	Tick       time.Duration `yaml:"tick"`       // Default 1s
+}
+
+// RateLimitConfig limits entries with the same level and message to Limit per Interval; the
+// number of dropped entries is reported in a "suppressed" field of the next logged one
+type RateLimitConfig struct {
+	Limit    int           `yaml:"limit"`    // Default 10
+	Interval time.Duration `yaml:"interval"` // Default 1m
 }
 
 // IsEnabled returns true if logging is enabled
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// DefaultRequestIDLocal is the Fiber locals key of the request ID set by the requestid middleware
+const DefaultRequestIDLocal = "requestid"
+
+type loggerKey struct{}
+
+// NewContext returns a context carrying logger
+func NewContext(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or the global logger (zap.L()) if there is none
+func FromContext(ctx context.Context) *zap.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+			return logger
+		}
+	}
+	return zap.L()
+}
+
+// WithFields returns a context whose logger (see FromContext) also logs fields
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	return NewContext(ctx, FromContext(ctx).With(fields...))
+}
+
+// Ctx returns the request-scoped logger of a Fiber request (see ContextMiddleware)
+func Ctx(c *fiber.Ctx) *zap.Logger {
+	return FromContext(c.UserContext())
+}
+
+// ContextOptions configures ContextMiddleware
+type ContextOptions struct {
+	// RequestIDHeader is read when RequestIDLocal is not set (default X-Request-ID)
+	RequestIDHeader string
+	// RequestIDLocal is the locals key of the request ID (default DefaultRequestIDLocal)
+	RequestIDLocal string
+	// UserID returns the authenticated user's ID ("" to omit), e.g. from c.Locals set by auth middleware.
+	// Since it runs before the handlers, register ContextMiddleware after authentication.
+	UserID func(c *fiber.Ctx) string
+	// Fields returns additional request-scoped fields
+	Fields func(c *fiber.Ctx) []zap.Field
+}
+
+// ContextMiddleware stores a logger with request_id (and user_id) fields in the request's user
+// context, so handlers and the code they call log correlated fields via FromContext(ctx) or
+// Ctx(c) without passing loggers around
+func ContextMiddleware(logger *zap.Logger, opts ContextOptions) fiber.Handler {
+	if opts.RequestIDHeader == "" {
+		opts.RequestIDHeader = fiber.HeaderXRequestID
+	}
+	if opts.RequestIDLocal == "" {
+		opts.RequestIDLocal = DefaultRequestIDLocal
+	}
+
+	return func(c *fiber.Ctx) error {
+		var fields []zap.Field
+		requestID, _ := c.Locals(opts.RequestIDLocal).(string)
+		if requestID == "" {
+			requestID = c.Get(opts.RequestIDHeader)
+		}
+		if requestID != "" {
+			fields = append(fields, zap.String("request_id", requestID))
+		}
+		if opts.UserID != nil {
+			if userID := opts.UserID(c); userID != "" {
+				fields = append(fields, zap.String("user_id", userID))
+			}
+		}
+		if opts.Fields != nil {
+			fields = append(fields, opts.Fields(c)...)
+		}
+
+		c.SetUserContext(NewContext(c.UserContext(), logger.With(fields...)))
+		return c.Next()
+	}
+}
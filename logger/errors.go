@@ -0,0 +1,80 @@
+package logger
+
+import (
+	stderrors "errors"
+	"fmt"
+
+	"github.com/kerimovok/go-pkg-utils/errors"
+	"go.uber.org/zap"
+)
+
+// ErrorFields expands err into structured fields. For an *errors.Error anywhere in the chain
+// these are its type, code, details, metadata, context IDs, retryability, stack frames and
+// cause; other errors only give an "error" field.
+func ErrorFields(err error) []zap.Field {
+	fields := []zap.Field{zap.Error(err)}
+
+	var e *errors.Error
+	if !stderrors.As(err, &e) {
+		return fields
+	}
+
+	fields = append(fields,
+		zap.String("error_type", string(e.Type)),
+		zap.String("error_code", e.Code),
+		zap.Bool("retryable", e.Retryable),
+	)
+	if e.Details != "" {
+		fields = append(fields, zap.String("error_details", e.Details))
+	}
+	if len(e.Metadata) > 0 {
+		fields = append(fields, zap.Any("error_metadata", e.Metadata))
+	}
+	if e.HTTPStatus != 0 {
+		fields = append(fields, zap.Int("http_status", e.HTTPStatus))
+	}
+	if e.RequestID != "" {
+		fields = append(fields, zap.String("request_id", e.RequestID))
+	}
+	if e.UserID != "" {
+		fields = append(fields, zap.String("user_id", e.UserID))
+	}
+	if e.Operation != "" {
+		fields = append(fields, zap.String("operation", e.Operation))
+	}
+	if e.Component != "" {
+		fields = append(fields, zap.String("component", e.Component))
+	}
+	if e.Cause != nil {
+		fields = append(fields, zap.String("cause", e.Cause.Error()))
+	}
+	if len(e.StackTrace) > 0 {
+		frames := make([]string, len(e.StackTrace))
+		for i, frame := range e.StackTrace {
+			frames[i] = fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+		fields = append(fields, zap.Strings("error_stack", frames))
+	}
+	return fields
+}
+
+// LogError logs err at error level with its structured fields (see ErrorFields)
+func LogError(logger *zap.Logger, err error) {
+	if err == nil {
+		return
+	}
+	message := err.Error()
+	var e *errors.Error
+	if stderrors.As(err, &e) {
+		message = e.Message
+	}
+	logger.Error(message, ErrorFields(err)...)
+}
+
+// ErrorLogger returns a function logging errors with LogError, e.g. for errors.ErrorHandler.Logger
+// or errors.SetDefaultLogger
+func ErrorLogger(logger *zap.Logger) func(error) {
+	return func(err error) {
+		LogError(logger, err)
+	}
+}
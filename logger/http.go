@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// HTTPMiddleware wraps a net/http handler with request logging equivalent to FiberMiddleware:
+// the same fields, Info/Warn/Error by status class and stack traces for 500-level errors only.
+// Handlers also get a request-scoped logger with request_id via FromContext(r.Context()).
+func HTTPMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	// Disable automatic stack traces - we'll add them conditionally for server errors only
+	loggerWithoutStack := logger.WithOptions(zap.AddStacktrace(zapcore.FatalLevel))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			requestID := r.Header.Get("X-Request-ID")
+			if requestID != "" {
+				r = r.WithContext(NewContext(r.Context(), loggerWithoutStack.With(zap.String("request_id", requestID))))
+			}
+
+			recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			ip := r.RemoteAddr
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+			fields := []zapcore.Field{
+				zap.Duration("latency", time.Since(start)),
+				zap.Int("status", recorder.status),
+				zap.String("method", r.Method),
+				zap.String("url", r.URL.RequestURI()),
+				zap.String("ip", ip),
+				zap.String("user_agent", r.UserAgent()),
+				zap.String("request_id", requestID),
+				zap.Int("bytes_sent", recorder.written),
+				zap.Int64("bytes_received", r.ContentLength),
+				zap.String("protocol", r.Proto),
+				zap.String("host", r.Host),
+				zap.String("path", r.URL.Path),
+				zap.String("query", r.URL.RawQuery),
+				zap.String("referer", r.Referer()),
+				zap.String("content_type", r.Header.Get("Content-Type")),
+				zap.String("content_length", strconv.FormatInt(r.ContentLength, 10)),
+			}
+
+			// Only add stack trace for 500-level server errors
+			// Client errors (4xx) don't need stack traces as they're expected business logic responses
+			switch {
+			case recorder.status >= 500:
+				loggerWithoutStack.Error("Server error", append(fields, zap.Stack("stacktrace"))...)
+			case recorder.status >= 400:
+				loggerWithoutStack.Warn("Client error", fields...)
+			default:
+				loggerWithoutStack.Info("Success", fields...)
+			}
+		})
+	}
+}
+
+// responseRecorder captures the status and size of a response
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	written     int
+	wroteHeader bool
+}
+
+// WriteHeader records the status
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write counts the bytes sent
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.written += n
+	return n, err
+}
+
+// Flush flushes the response if the underlying writer supports it
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
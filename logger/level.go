@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Each logger created by NewLogger has its own atomic level, registered here so SetLevel
+// and the level handlers can change all of them at runtime
+var (
+	levelsMu     sync.Mutex
+	levels       = make(map[zap.AtomicLevel]struct{})
+	runtimeLevel = zapcore.InfoLevel // Reported by Level
+	levelSet     bool                // Whether SetLevel was called; loggers created afterwards start at runtimeLevel
+)
+
+// newLevel registers the level of a new logger: the level set at runtime if SetLevel was
+// called, otherwise the configured one
+func newLevel(configured zapcore.Level) zap.AtomicLevel {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	if levelSet {
+		configured = runtimeLevel
+	} else {
+		runtimeLevel = configured
+	}
+	l := zap.NewAtomicLevelAt(configured)
+	levels[l] = struct{}{}
+	return l
+}
+
+// releaseLevel unregisters the level of a cleaned up logger
+func releaseLevel(l zap.AtomicLevel) {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	delete(levels, l)
+}
+
+// Level returns the level last set with SetLevel, or before that the level of the most
+// recently created logger
+func Level() zapcore.Level {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	return runtimeLevel
+}
+
+// SetLevel changes the level of the loggers created by NewLogger (debug, info, warn, error, ...).
+// Loggers created later start at this level instead of their configured one. Sinks with their
+// own level are not affected.
+func SetLevel(text string) error {
+	l, err := zapcore.ParseLevel(text)
+	if err != nil {
+		return fmt.Errorf("invalid log level %q: %w", text, err)
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+	runtimeLevel, levelSet = l, true
+	for level := range levels {
+		level.SetLevel(l)
+	}
+	return nil
+}
+
+// LevelHTTPHandler returns a net/http handler reporting (GET) and changing (PUT with
+// {"level":"debug"}) the log level
+func LevelHTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body struct {
+				Level string `json:"level"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				writeLevelError(w, http.StatusBadRequest, "invalid request body")
+				return
+			}
+			if err := SetLevel(body.Level); err != nil {
+				writeLevelError(w, http.StatusBadRequest, err.Error())
+				return
+			}
+		default:
+			writeLevelError(w, http.StatusMethodNotAllowed, "only GET and PUT are supported")
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"level": Level().String()})
+	})
+}
+
+// writeLevelError writes a JSON error response of the level handler
+func writeLevelError(w http.ResponseWriter, status int, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// LevelHandler returns a Fiber handler reporting (GET) and changing (PUT/POST with
+// {"level":"debug"} or ?level=debug) the log level, e.g.
+// app.All("/loglevel", adminOnly, logger.LevelHandler()). Protect it: anyone reaching it
+// can flood the logs.
+func LevelHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet:
+		case fiber.MethodPut, fiber.MethodPost:
+			var body struct {
+				Level string `json:"level"`
+			}
+			body.Level = c.Query("level")
+			if body.Level == "" {
+				if err := c.BodyParser(&body); err != nil {
+					return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "invalid request body"})
+				}
+			}
+			if err := SetLevel(body.Level); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+			}
+		default:
+			return c.Status(fiber.StatusMethodNotAllowed).JSON(fiber.Map{"error": "only GET, PUT and POST are supported"})
+		}
+		return c.JSON(fiber.Map{"level": Level().String()})
+	}
+}
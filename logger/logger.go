@@ -8,26 +8,55 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-// NewLogger creates a new Zap logger based on configuration
+// NewLogger creates a new Zap logger based on configuration. It starts at config.Level, or at
+// the level set with SetLevel if that was called, and SetLevel changes it at runtime. Creating
+// a logger never changes the level of other loggers.
 func NewLogger(config *Config) (*zap.Logger, error) {
+	logger, _, err := NewLoggerWithCleanup(config)
+	return logger, err
+}
+
+// NewLoggerWithCleanup is NewLogger also returning a cleanup function to call on shutdown:
+// it pushes the entries still buffered for OTLP and Loki sinks, stops their background
+// flushing and closes syslog connections and log files. SetLevel no longer changes the logger
+// after cleanup.
+func NewLoggerWithCleanup(config *Config) (*zap.Logger, func() error, error) {
+	cleanup := func() error { return nil }
 	if config == nil || !config.IsEnabled() {
 		// Return a no-op logger if logging is disabled
-		return zap.NewNop(), nil
+		return zap.NewNop(), cleanup, nil
+	}
+
+	masks, err := redactMasks(config)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	var logger *zap.Logger
-	var err error
+	level := newLevel(parseLogLevel(config.Level))
+	closeOutputs := cleanup
 
-	// Configure Zap logger with Lumberjack for file rotation
-	if config.FilePath != "" {
+	if len(config.Sinks) > 0 {
+		// Explicitly configured outputs
+		var core zapcore.Core
+		core, closeOutputs, err = newSinksCore(config, level, masks)
+		if err != nil {
+			releaseLevel(level)
+			return nil, nil, err
+		}
+		logger = zap.New(wrapCore(core, config), zap.AddCaller(), zap.AddStacktrace(zapcore.FatalLevel))
+	} else if config.FilePath != "" {
+		// Configure Zap logger with Lumberjack for file rotation
 		// Production logger with file output
-		writeSyncer := zapcore.AddSync(&lumberjack.Logger{
+		file := &lumberjack.Logger{
 			Filename:   config.FilePath,
 			MaxSize:    int(config.MaxSize / (1024 * 1024)), // Convert bytes to MB
 			MaxBackups: config.MaxBackups,
 			MaxAge:     config.MaxAge,
 			Compress:   true,
-		})
+		}
+		writeSyncer := zapcore.AddSync(file)
+		closeOutputs = file.Close
 
 		// Also write to stdout in addition to file
 		multiWriteSyncer := zapcore.NewMultiWriteSyncer(
@@ -35,28 +64,35 @@ func NewLogger(config *Config) (*zap.Logger, error) {
 			zapcore.AddSync(os.Stdout),
 		)
 
-		core := zapcore.NewCore(
+		core := NewRedactingCore(zapcore.NewCore(
 			zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
 			multiWriteSyncer,
-			parseLogLevel(config.Level),
-		)
+			level,
+		), masks)
 		// Disable automatic stack traces - we'll add them conditionally in middleware
-		logger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.FatalLevel))
+		logger = zap.New(wrapCore(core, config), zap.AddCaller(), zap.AddStacktrace(zapcore.FatalLevel))
 	} else {
 		// Development logger with console output
 		devConfig := zap.NewDevelopmentConfig()
-		devConfig.Level = zap.NewAtomicLevelAt(parseLogLevel(config.Level))
+		devConfig.Level = level
 		// Disable automatic stack traces - we'll add them conditionally in middleware
 		devConfig.DisableStacktrace = true
 		logger, err = devConfig.Build()
 		if err != nil {
-			return nil, err
+			releaseLevel(level)
+			return nil, nil, err
 		}
 		// Override to only show stack traces for fatal errors (which we won't use)
-		logger = logger.WithOptions(zap.AddStacktrace(zapcore.FatalLevel))
+		logger = logger.WithOptions(zap.AddStacktrace(zapcore.FatalLevel), zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			return wrapCore(NewRedactingCore(core, masks), config)
+		}))
 	}
 
-	return logger, nil
+	cleanup = func() error {
+		releaseLevel(level)
+		return closeOutputs()
+	}
+	return logger, cleanup, nil
 }
 
 // parseLogLevel parses log level string to zapcore.Level
@@ -0,0 +1,417 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// pushRecord is a log entry buffered for an HTTP push sink
+type pushRecord struct {
+	Time    time.Time
+	Level   zapcore.Level
+	Message string
+	Line    string                 // Encoded entry
+	Fields  map[string]interface{} // Context and entry fields
+}
+
+// pushSink batches records and pushes them to an HTTP endpoint (OTLP, Loki) in the background
+type pushSink struct {
+	name      string
+	endpoint  string
+	headers   map[string]string
+	client    *http.Client
+	marshal   func([]pushRecord) ([]byte, error)
+	batchSize int
+	maxBuffer int
+	kick      chan struct{}
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	failing   bool // Whether the last background push failed; only used by run and Close
+
+	mu      sync.Mutex
+	records []pushRecord
+	dropped int // Records dropped (full buffer or rejected batch) since the last report
+
+	pushMu sync.Mutex // Serializes pushes
+}
+
+// newPushSink creates a push sink and starts its background flushing
+func newPushSink(sink SinkConfig, marshal func([]pushRecord) ([]byte, error)) *pushSink {
+	batchSize := sink.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	interval := sink.FlushInterval
+	if interval <= 0 {
+		interval = time.Second
+	}
+	timeout := sink.Timeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+
+	s := &pushSink{
+		name:      sink.Type,
+		endpoint:  sink.Endpoint,
+		headers:   sink.Headers,
+		client:    &http.Client{Timeout: timeout},
+		marshal:   marshal,
+		batchSize: batchSize,
+		maxBuffer: 10 * batchSize,
+		kick:      make(chan struct{}, 1),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.run(interval)
+	return s
+}
+
+// run flushes every interval and whenever a batch is full, until the sink is closed
+func (s *pushSink) run(interval time.Duration) {
+	defer close(s.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		case <-s.kick:
+		}
+		s.report(s.flush())
+	}
+}
+
+// report writes push failures to stderr once per outage rather than on every attempt,
+// and notes when the endpoint recovers
+func (s *pushSink) report(err error) {
+	switch {
+	case err != nil && !s.failing:
+		fmt.Fprintf(os.Stderr, "logger: %s push failed, keeping up to %d entries until it recovers: %v\n", s.name, s.maxBuffer, err)
+	case err == nil && s.failing:
+		s.mu.Lock()
+		dropped := s.dropped
+		s.dropped = 0
+		s.mu.Unlock()
+		fmt.Fprintf(os.Stderr, "logger: %s push recovered (%d entries dropped)\n", s.name, dropped)
+	}
+	s.failing = err != nil
+}
+
+// Close stops the background flushing and pushes the remaining records
+func (s *pushSink) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		err = s.flush()
+	})
+	return err
+}
+
+// add buffers a record; while the endpoint is unavailable the oldest records are dropped
+// beyond 10 batches
+func (s *pushSink) add(record pushRecord) {
+	s.mu.Lock()
+	if len(s.records) >= s.maxBuffer {
+		s.records = s.records[1:]
+		s.dropped++
+	}
+	s.records = append(s.records, record)
+	full := len(s.records) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		select {
+		case s.kick <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// errBatchRejected marks push failures that retrying the same batch cannot fix
+var errBatchRejected = errors.New("batch rejected")
+
+// flush pushes the buffered records in batches; the records of a failed push are kept for
+// the next attempt, unless the batch itself was rejected, which would block the sink for good
+func (s *pushSink) flush() error {
+	s.pushMu.Lock()
+	defer s.pushMu.Unlock()
+
+	for {
+		s.mu.Lock()
+		n := len(s.records)
+		if n > s.batchSize {
+			n = s.batchSize
+		}
+		batch := append([]pushRecord(nil), s.records[:n]...)
+		s.records = s.records[n:]
+		s.mu.Unlock()
+		if n == 0 {
+			return nil
+		}
+
+		err := s.push(batch)
+		if errors.Is(err, errBatchRejected) {
+			s.mu.Lock()
+			s.dropped += len(batch)
+			s.mu.Unlock()
+			fmt.Fprintf(os.Stderr, "logger: %s dropped %d entries: %v\n", s.name, len(batch), err)
+			continue
+		}
+		if err != nil {
+			s.mu.Lock()
+			s.records = append(batch, s.records...)
+			if excess := len(s.records) - s.maxBuffer; excess > 0 {
+				s.records = s.records[excess:]
+				s.dropped += excess
+			}
+			s.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// push sends one batch; marshal errors and client errors other than 429 wrap errBatchRejected
+func (s *pushSink) push(batch []pushRecord) error {
+	body, err := s.marshal(batch)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errBatchRejected, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.client.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: status %d", errBatchRejected, resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// pushCore is a zapcore.Core buffering entries in a push sink
+type pushCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	fields  []zapcore.Field
+	sink    *pushSink
+}
+
+// newPushCore creates a core writing to sink
+func newPushCore(encoder zapcore.Encoder, enabler zapcore.LevelEnabler, sink *pushSink) zapcore.Core {
+	return &pushCore{LevelEnabler: enabler, encoder: encoder, sink: sink}
+}
+
+// With returns a core adding fields to every entry
+func (c *pushCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return &pushCore{
+		LevelEnabler: c.LevelEnabler,
+		encoder:      encoder,
+		fields:       append(c.fields[:len(c.fields):len(c.fields)], fields...),
+		sink:         c.sink,
+	}
+}
+
+// Check adds the core to ce if the entry's level is enabled
+func (c *pushCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write buffers an entry
+func (c *pushCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	line := strings.TrimSuffix(buf.String(), "\n")
+	buf.Free()
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	if ent.LoggerName != "" {
+		enc.Fields["logger"] = ent.LoggerName
+	}
+	if ent.Caller.Defined {
+		enc.Fields["caller"] = ent.Caller.TrimmedPath()
+	}
+	if ent.Stack != "" {
+		enc.Fields["stacktrace"] = ent.Stack
+	}
+
+	c.sink.add(pushRecord{Time: ent.Time, Level: ent.Level, Message: ent.Message, Line: line, Fields: enc.Fields})
+	return nil
+}
+
+// Sync pushes the buffered entries
+func (c *pushCore) Sync() error {
+	return c.sink.flush()
+}
+
+// marshalLoki encodes records as a Loki push request, one stream per level
+func marshalLoki(labels map[string]string) func([]pushRecord) ([]byte, error) {
+	return func(records []pushRecord) ([]byte, error) {
+		type stream struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		}
+		var streams []*stream
+		byLevel := make(map[zapcore.Level]*stream)
+		for _, r := range records {
+			s, ok := byLevel[r.Level]
+			if !ok {
+				streamLabels := make(map[string]string, len(labels)+1)
+				for key, value := range labels {
+					streamLabels[key] = value
+				}
+				streamLabels["level"] = r.Level.String()
+				s = &stream{Stream: streamLabels}
+				byLevel[r.Level] = s
+				streams = append(streams, s)
+			}
+			s.Values = append(s.Values, [2]string{strconv.FormatInt(r.Time.UnixNano(), 10), r.Line})
+		}
+		return json.Marshal(map[string]interface{}{"streams": streams})
+	}
+}
+
+// marshalOTLP encodes records as an OTLP/HTTP JSON ExportLogsServiceRequest; labels become
+// resource attributes (e.g. service.name)
+func marshalOTLP(labels map[string]string) func([]pushRecord) ([]byte, error) {
+	resource := make(map[string]interface{}, len(labels))
+	for key, value := range labels {
+		resource[key] = value
+	}
+	resourceAttributes := otlpAttributes(resource)
+
+	return func(records []pushRecord) ([]byte, error) {
+		logRecords := make([]map[string]interface{}, len(records))
+		for i, r := range records {
+			logRecords[i] = map[string]interface{}{
+				"timeUnixNano":   strconv.FormatInt(r.Time.UnixNano(), 10),
+				"severityNumber": otlpSeverity(r.Level),
+				"severityText":   strings.ToUpper(r.Level.String()),
+				"body":           map[string]interface{}{"stringValue": r.Message},
+				"attributes":     otlpAttributes(r.Fields),
+			}
+		}
+		return json.Marshal(map[string]interface{}{
+			"resourceLogs": []interface{}{map[string]interface{}{
+				"resource": map[string]interface{}{"attributes": resourceAttributes},
+				"scopeLogs": []interface{}{map[string]interface{}{
+					"scope":      map[string]interface{}{"name": "github.com/kerimovok/go-pkg-utils/logger"},
+					"logRecords": logRecords,
+				}},
+			}},
+		})
+	}
+}
+
+// otlpSeverity maps a zap level to an OTLP severity number
+func otlpSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 5
+	case zapcore.InfoLevel:
+		return 9
+	case zapcore.WarnLevel:
+		return 13
+	case zapcore.ErrorLevel:
+		return 17
+	default:
+		return 21
+	}
+}
+
+// otlpAttributes converts fields to OTLP key-values sorted by key; values other than strings,
+// booleans and numbers are JSON-encoded
+func otlpAttributes(fields map[string]interface{}) []map[string]interface{} {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attributes := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		attributes = append(attributes, map[string]interface{}{"key": key, "value": otlpValue(fields[key])})
+	}
+	return attributes
+}
+
+// otlpValue converts a field value to an OTLP AnyValue
+func otlpValue(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case string:
+		return map[string]interface{}{"stringValue": v}
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return map[string]interface{}{"intValue": fmt.Sprint(v)}
+	case float32:
+		return otlpDouble(float64(v))
+	case float64:
+		return otlpDouble(v)
+	case time.Time:
+		return map[string]interface{}{"stringValue": v.Format(time.RFC3339Nano)}
+	case time.Duration:
+		return map[string]interface{}{"stringValue": v.String()}
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		return map[string]interface{}{"stringValue": string(data)}
+	}
+}
+
+// otlpDouble converts a float to an OTLP AnyValue; JSON has no NaN or infinities, so they
+// are sent as strings
+func otlpDouble(v float64) map[string]interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return map[string]interface{}{"stringValue": strconv.FormatFloat(v, 'g', -1, 64)}
+	}
+	return map[string]interface{}{"doubleValue": v}
+}
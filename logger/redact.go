@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+
+	"github.com/kerimovok/go-pkg-utils/text"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Redaction masks of Config.Redact
+const (
+	RedactEmail = "email" // text.MaskEmail
+	RedactPhone = "phone" // text.MaskPhone
+	RedactCard  = "card"  // text.MaskCard
+	RedactName  = "name"  // text.MaskName
+	RedactFull  = "full"  // Replaced by "[REDACTED]"
+)
+
+// redactMasks resolves the masks of config.Redact
+func redactMasks(config *Config) (map[string]func(string) string, error) {
+	if len(config.Redact) == 0 {
+		return nil, nil
+	}
+	masks := make(map[string]func(string) string, len(config.Redact))
+	for key, mask := range config.Redact {
+		switch mask {
+		case RedactEmail:
+			masks[key] = text.MaskEmail
+		case RedactPhone:
+			masks[key] = text.MaskPhone
+		case RedactCard:
+			masks[key] = text.MaskCard
+		case RedactName:
+			masks[key] = text.MaskName
+		case RedactFull, "":
+			masks[key] = func(string) string { return "[REDACTED]" }
+		default:
+			return nil, fmt.Errorf("unknown redaction mask %q for field %q", mask, key)
+		}
+	}
+	return masks, nil
+}
+
+// redactingCore masks the values of configured fields before they reach the wrapped core
+type redactingCore struct {
+	zapcore.Core
+	masks map[string]func(string) string
+}
+
+// NewRedactingCore wraps core so that fields whose key is in masks are written with their
+// value (as text) passed through the mask, e.g. {"email": text.MaskEmail} or
+// {"iban": func(s string) string { return text.MaskPattern(s, "####*", '*') }}. Wrap the
+// cores writing the entries rather than a tee of them: the wrapped core's own Check is
+// bypassed and its level is checked instead.
+func NewRedactingCore(core zapcore.Core, masks map[string]func(string) string) zapcore.Core {
+	if len(masks) == 0 {
+		return core
+	}
+	return &redactingCore{Core: core, masks: masks}
+}
+
+// With adds masked fields to the core
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(c.redact(fields)), masks: c.masks}
+}
+
+// Check adds the core to ce if the entry's level is enabled
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write writes the entry with masked fields
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, c.redact(fields))
+}
+
+// redact returns fields with the configured ones masked, copying only if one matches
+func (c *redactingCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var result []zapcore.Field
+	for i, field := range fields {
+		mask, ok := c.masks[field.Key]
+		if !ok {
+			continue
+		}
+		if result == nil {
+			result = append([]zapcore.Field(nil), fields...)
+		}
+		result[i] = zap.String(field.Key, mask(fieldText(field)))
+	}
+	if result == nil {
+		return fields
+	}
+	return result
+}
+
+// fieldText returns the value of a field as text
+func fieldText(field zapcore.Field) string {
+	if field.Type == zapcore.StringType {
+		return field.String
+	}
+	enc := zapcore.NewMapObjectEncoder()
+	field.AddTo(enc)
+	return fmt.Sprint(enc.Fields[field.Key])
+}
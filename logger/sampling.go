@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// wrapCore applies the sampling and rate limit of config to core
+func wrapCore(core zapcore.Core, config *Config) zapcore.Core {
+	if s := config.Sampling; s != nil {
+		initial, thereafter, tick := s.Initial, s.Thereafter, s.Tick
+		if initial <= 0 {
+			initial = 100
+		}
+		if thereafter <= 0 {
+			thereafter = 100
+		}
+		if tick <= 0 {
+			tick = time.Second
+		}
+		core = zapcore.NewSamplerWithOptions(core, tick, initial, thereafter)
+	}
+	if r := config.RateLimit; r != nil {
+		core = NewRateLimitedCore(core, r.Limit, r.Interval)
+	}
+	return core
+}
+
+// rateLimitedCore drops entries exceeding the limit of their level and message
+type rateLimitedCore struct {
+	zapcore.Core
+	limiter *messageLimiter
+}
+
+// NewRateLimitedCore wraps core so that at most limit entries with the same level and message
+// are written per interval (defaults 10 per minute). The next entry written after some were
+// dropped carries their number in a "suppressed" field. This keeps e.g. reconnect loops from
+// flooding log files.
+func NewRateLimitedCore(core zapcore.Core, limit int, interval time.Duration) zapcore.Core {
+	if limit <= 0 {
+		limit = 10
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &rateLimitedCore{
+		Core:    core,
+		limiter: &messageLimiter{limit: limit, interval: interval, windows: make(map[messageKey]*messageWindow)},
+	}
+}
+
+// With adds fields to the core, sharing the limiter
+func (c *rateLimitedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &rateLimitedCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+// Check adds the core to ce if the entry is enabled and within the limit
+func (c *rateLimitedCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+	allowed, suppressed := c.limiter.allow(ent, ent.Time)
+	if !allowed {
+		return ce
+	}
+	if suppressed > 0 {
+		return ce.AddCore(ent, c.Core.With([]zapcore.Field{zap.Int("suppressed", suppressed)}))
+	}
+	return ce.AddCore(ent, c.Core)
+}
+
+type messageKey struct {
+	level   zapcore.Level
+	message string
+}
+
+type messageWindow struct {
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+// messageLimiter counts entries per level and message in fixed windows
+type messageLimiter struct {
+	mu       sync.Mutex
+	limit    int
+	interval time.Duration
+	windows  map[messageKey]*messageWindow
+}
+
+// allow reports whether an entry may be written and how many were dropped since the last
+// written one
+func (l *messageLimiter) allow(ent zapcore.Entry, now time.Time) (bool, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := messageKey{level: ent.Level, message: ent.Message}
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.interval {
+		if !ok {
+			l.prune(now)
+			w = &messageWindow{}
+			l.windows[key] = w
+		}
+		w.start, w.count = now, 0
+	}
+
+	if w.count >= l.limit {
+		w.suppressed++
+		return false, 0
+	}
+	w.count++
+	suppressed := w.suppressed
+	w.suppressed = 0
+	return true, suppressed
+}
+
+// prune forgets windows that expired without suppressed entries, bounding memory for
+// messages with variable text
+func (l *messageLimiter) prune(now time.Time) {
+	if len(l.windows) < 1024 {
+		return
+	}
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.interval && w.suppressed == 0 {
+			delete(l.windows, key)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// newSinksCore builds a core writing to every sink of config and a function closing the
+// sinks (pending pushes, syslog connections, log files). If a sink fails, the sinks opened
+// before it are closed again. Sinks without their own level use level; masks redact the
+// fields of every sink.
+func newSinksCore(config *Config, level zap.AtomicLevel, masks map[string]func(string) string) (zapcore.Core, func() error, error) {
+	cores := make([]zapcore.Core, 0, len(config.Sinks))
+	var closers []io.Closer
+	closeSinks := func() error {
+		var firstErr error
+		for _, closer := range closers {
+			if err := closer.Close(); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+
+	for i, sink := range config.Sinks {
+		core, closer, err := newSinkCore(sink, config, level)
+		if err != nil {
+			closeSinks()
+			return nil, nil, fmt.Errorf("log sink %d (%s): %w", i, sink.Type, err)
+		}
+		cores = append(cores, NewRedactingCore(core, masks))
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+	return zapcore.NewTee(cores...), closeSinks, nil
+}
+
+// newSinkCore builds the core of one sink and, if it holds resources, its closer. A sink
+// with its own level keeps it; SetLevel only changes sinks following the logger's level.
+func newSinkCore(sink SinkConfig, config *Config, level zap.AtomicLevel) (zapcore.Core, io.Closer, error) {
+	var enabler zapcore.LevelEnabler = level
+	if sink.Level != "" {
+		l, err := zapcore.ParseLevel(sink.Level)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid level %q: %w", sink.Level, err)
+		}
+		enabler = l
+	}
+
+	encoder, err := sinkEncoder(sink.Encoding)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch sink.Type {
+	case SinkStdout:
+		return zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), enabler), nil, nil
+	case SinkStderr:
+		return zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), enabler), nil, nil
+	case SinkFile:
+		if sink.Path == "" {
+			return nil, nil, fmt.Errorf("path is required")
+		}
+		file := &lumberjack.Logger{
+			Filename:   sink.Path,
+			MaxSize:    int(config.MaxSize / (1024 * 1024)), // Convert bytes to MB
+			MaxBackups: config.MaxBackups,
+			MaxAge:     config.MaxAge,
+			Compress:   true,
+		}
+		return zapcore.NewCore(encoder, zapcore.AddSync(file), enabler), file, nil
+	case SinkSyslog:
+		return newSyslogCore(sink, encoder, enabler)
+	case SinkOTLP:
+		if sink.Endpoint == "" {
+			return nil, nil, fmt.Errorf("endpoint is required")
+		}
+		push := newPushSink(sink, marshalOTLP(sink.Labels))
+		return newPushCore(encoder, enabler, push), push, nil
+	case SinkLoki:
+		if sink.Endpoint == "" {
+			return nil, nil, fmt.Errorf("endpoint is required")
+		}
+		push := newPushSink(sink, marshalLoki(sink.Labels))
+		return newPushCore(encoder, enabler, push), push, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown sink type %q", sink.Type)
+	}
+}
+
+// sinkEncoder returns the encoder of a sink encoding
+func sinkEncoder(encoding string) (zapcore.Encoder, error) {
+	switch encoding {
+	case "", "json":
+		return zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), nil
+	case "console":
+		return zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()), nil
+	default:
+		return nil, fmt.Errorf("unknown encoding %q", encoding)
+	}
+}
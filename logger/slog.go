@@ -0,0 +1,274 @@
+package logger
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"runtime"
+	"sort"
+
+	"github.com/kerimovok/go-pkg-utils/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// slogHandler is a slog.Handler writing to a zap logger
+type slogHandler struct {
+	logger *zap.Logger
+}
+
+// NewSlogHandler returns a slog.Handler writing records to logger, so libraries using
+// log/slog log through the configured zap sinks: slog.New(logger.NewSlogHandler(log)).
+// Groups become nested objects.
+func NewSlogHandler(logger *zap.Logger) slog.Handler {
+	return &slogHandler{logger: logger}
+}
+
+// Enabled reports whether the logger writes records of level
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.Core().Enabled(zapLevel(level))
+}
+
+// Handle writes a record, keeping its time and caller
+func (h *slogHandler) Handle(_ context.Context, record slog.Record) error {
+	ce := h.logger.Check(zapLevel(record.Level), record.Message)
+	if ce == nil {
+		return nil
+	}
+	if !record.Time.IsZero() {
+		ce.Time = record.Time
+	}
+	if record.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{record.PC}).Next()
+		ce.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+		ce.Caller.Function = frame.Function
+	}
+
+	fields := make([]zap.Field, 0, record.NumAttrs())
+	record.Attrs(func(attr slog.Attr) bool {
+		if field, ok := zapField(attr); ok {
+			fields = append(fields, field)
+		}
+		return true
+	})
+	ce.Write(fields...)
+	return nil
+}
+
+// WithAttrs returns a handler adding attrs to every record
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make([]zap.Field, 0, len(attrs))
+	for _, attr := range attrs {
+		if field, ok := zapField(attr); ok {
+			fields = append(fields, field)
+		}
+	}
+	return &slogHandler{logger: h.logger.With(fields...)}
+}
+
+// WithGroup returns a handler nesting the following attributes under name
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{logger: h.logger.With(zap.Namespace(name))}
+}
+
+// slogGroup marshals the attributes of a slog group as a zap object
+type slogGroup []slog.Attr
+
+// MarshalLogObject adds the attributes to enc
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, attr := range g {
+		if field, ok := zapField(attr); ok {
+			field.AddTo(enc)
+		}
+	}
+	return nil
+}
+
+// zapField converts a slog attribute; empty attributes and groups are skipped
+func zapField(attr slog.Attr) (zap.Field, bool) {
+	attr.Value = attr.Value.Resolve()
+	if attr.Equal(slog.Attr{}) {
+		return zap.Skip(), false
+	}
+
+	value := attr.Value
+	switch value.Kind() {
+	case slog.KindBool:
+		return zap.Bool(attr.Key, value.Bool()), true
+	case slog.KindDuration:
+		return zap.Duration(attr.Key, value.Duration()), true
+	case slog.KindFloat64:
+		return zap.Float64(attr.Key, value.Float64()), true
+	case slog.KindInt64:
+		return zap.Int64(attr.Key, value.Int64()), true
+	case slog.KindString:
+		return zap.String(attr.Key, value.String()), true
+	case slog.KindTime:
+		return zap.Time(attr.Key, value.Time()), true
+	case slog.KindUint64:
+		return zap.Uint64(attr.Key, value.Uint64()), true
+	case slog.KindGroup:
+		group := value.Group()
+		if len(group) == 0 {
+			return zap.Skip(), false
+		}
+		if attr.Key == "" {
+			return zap.Inline(slogGroup(group)), true
+		}
+		return zap.Object(attr.Key, slogGroup(group)), true
+	default:
+		if err, ok := value.Any().(error); ok {
+			return zap.NamedError(attr.Key, err), true
+		}
+		return zap.Any(attr.Key, value.Any()), true
+	}
+}
+
+// zapLevel maps a slog level to the zap level at or below it
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// slogLevel maps a zap level to slog; levels above error map to error
+func slogLevel(level zapcore.Level) slog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// slogCore is a zapcore.Core writing to a slog.Handler
+type slogCore struct {
+	handler slog.Handler
+}
+
+// NewSlogCore returns a zap core writing entries to handler, the inverse of NewSlogHandler:
+// zap.New(logger.NewSlogCore(slog.Default().Handler())). Namespaces become groups.
+func NewSlogCore(handler slog.Handler) zapcore.Core {
+	return &slogCore{handler: handler}
+}
+
+// Enabled reports whether the handler handles level
+func (c *slogCore) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), slogLevel(level))
+}
+
+// With returns a core adding fields to every entry
+func (c *slogCore) With(fields []zapcore.Field) zapcore.Core {
+	handler := c.handler
+	for len(fields) > 0 {
+		i := namespaceIndex(fields)
+		if attrs := slogAttrs(fields[:i]); len(attrs) > 0 {
+			handler = handler.WithAttrs(attrs)
+		}
+		if i == len(fields) {
+			break
+		}
+		handler = handler.WithGroup(fields[i].Key)
+		fields = fields[i+1:]
+	}
+	return &slogCore{handler: handler}
+}
+
+// Check adds the core to ce if the entry's level is enabled
+func (c *slogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write converts the entry to a slog record and handles it
+func (c *slogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	var pc uintptr
+	if ent.Caller.Defined {
+		pc = ent.Caller.PC
+	}
+	record := slog.NewRecord(ent.Time, slogLevel(ent.Level), ent.Message, pc)
+	if ent.LoggerName != "" {
+		record.AddAttrs(slog.String("logger", ent.LoggerName))
+	}
+	record.AddAttrs(slogAttrs(fields)...)
+	if ent.Stack != "" {
+		record.AddAttrs(slog.String("stacktrace", ent.Stack))
+	}
+	return c.handler.Handle(context.Background(), record)
+}
+
+// Sync is a no-op; slog handlers have no buffers to flush
+func (c *slogCore) Sync() error {
+	return nil
+}
+
+// namespaceIndex returns the index of the first namespace field (len(fields) if none)
+func namespaceIndex(fields []zapcore.Field) int {
+	for i, field := range fields {
+		if field.Type == zapcore.NamespaceType {
+			return i
+		}
+	}
+	return len(fields)
+}
+
+// slogAttrs converts zap fields; fields after a namespace are nested in a group
+func slogAttrs(fields []zapcore.Field) []slog.Attr {
+	i := namespaceIndex(fields)
+	attrs := make([]slog.Attr, 0, i+1)
+	for _, field := range fields[:i] {
+		enc := zapcore.NewMapObjectEncoder()
+		field.AddTo(enc)
+		keys := make([]string, 0, len(enc.Fields))
+		for key := range enc.Fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attrs = append(attrs, slog.Any(key, enc.Fields[key]))
+		}
+	}
+	if i < len(fields) {
+		attrs = append(attrs, slog.Attr{Key: fields[i].Key, Value: slog.GroupValue(slogAttrs(fields[i+1:])...)})
+	}
+	return attrs
+}
+
+// SetGlobal routes the global loggers through logger: zap.L(), slog.Default(), the standard
+// log package (e.g. the queue package's log.Printf calls, written at info level) and the default
+// errors.ErrorHandler logger. The returned function restores the previous globals.
+func SetGlobal(logger *zap.Logger) func() {
+	previous, previousErrors := slog.Default(), errors.DefaultLogger()
+	flags, prefix, writer := log.Flags(), log.Prefix(), log.Writer()
+	restoreZap := zap.ReplaceGlobals(logger)
+	// slog.SetDefault also redirects the log package; RedirectStdLog then skips the slog hop
+	slog.SetDefault(slog.New(NewSlogHandler(logger)))
+	restoreLog := zap.RedirectStdLog(logger)
+	errors.SetDefaultLogger(ErrorLogger(logger))
+
+	return func() {
+		errors.SetDefaultLogger(previousErrors)
+		restoreLog()
+		slog.SetDefault(previous)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+		log.SetOutput(writer)
+		restoreZap()
+	}
+}
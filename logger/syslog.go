@@ -0,0 +1,72 @@
+//go:build !windows && !plan9
+
+package logger
+
+import (
+	"io"
+	"log/syslog"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslogCore writes encoded entries to syslog with the priority of their level
+type syslogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	writer  *syslog.Writer
+}
+
+// newSyslogCore connects to the syslog daemon of sink; the returned closer closes the connection
+func newSyslogCore(sink SinkConfig, encoder zapcore.Encoder, enabler zapcore.LevelEnabler) (zapcore.Core, io.Closer, error) {
+	writer, err := syslog.Dial(sink.Network, sink.Address, syslog.LOG_INFO|syslog.LOG_USER, sink.Tag)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &syslogCore{LevelEnabler: enabler, encoder: encoder, writer: writer}, writer, nil
+}
+
+// With returns a core adding fields to every entry
+func (c *syslogCore) With(fields []zapcore.Field) zapcore.Core {
+	encoder := c.encoder.Clone()
+	for _, field := range fields {
+		field.AddTo(encoder)
+	}
+	return &syslogCore{LevelEnabler: c.LevelEnabler, encoder: encoder, writer: c.writer}
+}
+
+// Check adds the core to ce if the entry's level is enabled
+func (c *syslogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write sends an entry with the syslog priority of its level
+func (c *syslogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	message := strings.TrimSuffix(buf.String(), "\n")
+	switch ent.Level {
+	case zapcore.DebugLevel:
+		return c.writer.Debug(message)
+	case zapcore.InfoLevel:
+		return c.writer.Info(message)
+	case zapcore.WarnLevel:
+		return c.writer.Warning(message)
+	case zapcore.ErrorLevel:
+		return c.writer.Err(message)
+	default:
+		return c.writer.Crit(message)
+	}
+}
+
+// Sync is a no-op; syslog writes are unbuffered
+func (c *syslogCore) Sync() error {
+	return nil
+}
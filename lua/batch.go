@@ -0,0 +1,68 @@
+package lua
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/errors"
+)
+
+// ExecuteBatch executes script once per payload with at most maxConcurrent executions at a
+// time (defaults to 10 if <= 0) and returns the results in payload order. Each execution
+// gets the executor's timeout; payloads not started before ctx is done fail as canceled.
+func (e *Executor) ExecuteBatch(ctx context.Context, script Script, payloads []map[string]interface{}, maxConcurrent int) []ExecutionResult {
+	pool := NewWorkerPool(maxConcurrent)
+	results := make([]ExecutionResult, len(payloads))
+
+	var wg sync.WaitGroup
+	for i, payload := range payloads {
+		if err := pool.AcquireContext(ctx); err != nil {
+			for j := i; j < len(payloads); j++ {
+				results[j] = canceledResult(script, err)
+			}
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer pool.Release()
+			results[i] = e.Execute(ctx, script, payload)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// BatchError combines the errors of failed results, labeled with their index, into one
+// error (nil if every execution succeeded)
+func BatchError(results []ExecutionResult) error {
+	var errs []error
+	for i, result := range results {
+		if result.Status == ExecutionStatusSuccess {
+			continue
+		}
+		message := string(result.Status)
+		if result.ErrorMessage != nil {
+			message = *result.ErrorMessage
+		}
+		errs = append(errs, fmt.Errorf("item %d: %s", i, message))
+	}
+	return errors.CombineErrors(errs...)
+}
+
+// canceledResult is the result of an execution that never started
+func canceledResult(script Script, err error) ExecutionResult {
+	message := fmt.Sprintf("execution canceled: %v", err)
+	return ExecutionResult{
+		ScriptID:      script.GetID(),
+		ScriptName:    script.GetName(),
+		ScriptVersion: script.GetVersion(),
+		Status:        ExecutionStatusFailure,
+		ErrorMessage:  &message,
+		ExecutedAt:    time.Now(),
+	}
+}
@@ -0,0 +1,59 @@
+package lua
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kerimovok/go-pkg-utils/collections"
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// compiledScript is a cached compilation of a script version
+type compiledScript struct {
+	code  string
+	proto *lua.FunctionProto
+}
+
+// CompiledScriptsCacheSize is the number of compiled script versions kept in memory
+const CompiledScriptsCacheSize = 1000
+
+// compiledScripts caches compilations by ID@version, evicting the least recently used ones
+var compiledScripts = collections.NewLRU[string, compiledScript](CompiledScriptsCacheSize)
+
+// CompileScript parses and compiles a script's code into a FunctionProto that any VM can run.
+// Results are cached by script ID and version; a changed code for the same version is recompiled.
+func CompileScript(script Script) (*lua.FunctionProto, error) {
+	key := script.GetID() + "@" + script.GetVersion()
+	code := script.GetCode()
+
+	if cached, ok := compiledScripts.Get(key); ok && cached.code == code {
+		return cached.proto, nil
+	}
+
+	proto, err := CompileString(code, script.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	compiledScripts.Set(key, compiledScript{code: code, proto: proto})
+	return proto, nil
+}
+
+// CompileString compiles Lua source code without caching
+func CompileString(code, name string) (*lua.FunctionProto, error) {
+	chunk, err := parse.Parse(strings.NewReader(code), name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse script: %w", err)
+	}
+	proto, err := lua.Compile(chunk, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile script: %w", err)
+	}
+	return proto, nil
+}
+
+// ClearCompiledScripts removes all cached compilations (e.g. after bulk script updates)
+func ClearCompiledScripts() {
+	compiledScripts.Purge()
+}
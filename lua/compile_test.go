@@ -0,0 +1,70 @@
+package lua
+
+import (
+	"context"
+	"testing"
+)
+
+// benchScript is a Script with fixed code for benchmarks
+type benchScript struct{ code string }
+
+func (s benchScript) GetID() string      { return "bench" }
+func (s benchScript) GetName() string    { return "bench.lua" }
+func (s benchScript) GetVersion() string { return "1" }
+func (s benchScript) GetCode() string    { return s.code }
+
+var benchHandle = benchScript{code: `
+function handle(payload)
+	local total = 0
+	for i = 1, payload.count do
+		total = total + i * payload.price
+	end
+	return { total = total, currency = "EUR" }
+end
+`}
+
+var benchPayload = map[string]interface{}{"count": 100, "price": 2.5}
+
+func benchmarkExecute(b *testing.B, config ExecutorConfig) {
+	executor := NewExecutor(config)
+	ctx := context.Background()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if result := executor.Execute(ctx, benchHandle, benchPayload); result.Status != ExecutionStatusSuccess {
+			b.Fatalf("execution failed: %v", *result.ErrorMessage)
+		}
+	}
+}
+
+func BenchmarkExecute_FreshVM(b *testing.B) {
+	benchmarkExecute(b, ExecutorConfig{})
+}
+
+func BenchmarkExecute_Pooled(b *testing.B) {
+	pool := NewVMPool(DefaultSandboxConfig(), 4)
+	defer pool.Close()
+	benchmarkExecute(b, ExecutorConfig{VMPool: pool})
+}
+
+func BenchmarkCompileScript_Cached(b *testing.B) {
+	if _, err := CompileScript(benchHandle); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := CompileScript(benchHandle); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompileString_Uncached(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := CompileString(benchHandle.code, benchHandle.GetName()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package lua
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// maxSafeInteger is the largest integer a float64 (LNumber) represents exactly
+const maxSafeInteger = 1 << 53
+
+// ConvertToLua converts a Go value (e.g. a script payload, JSON-decoded data or the result
+// of ConvertFromLua) to Lua:
+//   - nil -> nil, bool -> boolean, string -> string, any integer or float -> number
+//   - maps -> tables keyed by the keys' string form (set in sorted key order)
+//   - slices and arrays -> sequences
+//   - pointers are followed; anything else becomes its fmt.Sprint string
+func ConvertToLua(L *lua.LState, value interface{}) lua.LValue {
+	switch v := value.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(v)
+	case string:
+		return lua.LString(v)
+	case int:
+		return lua.LNumber(v)
+	case int64:
+		return lua.LNumber(v)
+	case float64:
+		return lua.LNumber(v)
+	case []interface{}:
+		table := L.CreateTable(len(v), 0)
+		for _, item := range v {
+			table.Append(ConvertToLua(L, item))
+		}
+		return table
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		table := L.CreateTable(0, len(v))
+		for _, k := range keys {
+			table.RawSetString(k, ConvertToLua(L, v[k]))
+		}
+		return table
+	default:
+		return reflectToLua(L, reflect.ValueOf(value))
+	}
+}
+
+// reflectToLua converts the typed values ConvertToLua has no fast path for
+func reflectToLua(L *lua.LState, rv reflect.Value) lua.LValue {
+	switch rv.Kind() {
+	case reflect.Bool:
+		return lua.LBool(rv.Bool())
+	case reflect.String:
+		return lua.LString(rv.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return lua.LNumber(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return lua.LNumber(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return lua.LNumber(rv.Float())
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			return lua.LNil
+		}
+		return ConvertToLua(L, rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return lua.LNil
+		}
+		table := L.CreateTable(rv.Len(), 0)
+		for i := 0; i < rv.Len(); i++ {
+			table.Append(ConvertToLua(L, rv.Index(i).Interface()))
+		}
+		return table
+	case reflect.Map:
+		if rv.IsNil() {
+			return lua.LNil
+		}
+		entries := make(map[string]reflect.Value, rv.Len())
+		keys := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			entries[key] = iter.Value()
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		table := L.CreateTable(0, len(keys))
+		for _, k := range keys {
+			table.RawSetString(k, ConvertToLua(L, entries[k].Interface()))
+		}
+		return table
+	default:
+		return lua.LString(fmt.Sprint(rv.Interface()))
+	}
+}
+
+// TableToMap converts a Lua table to a Go map, converting nested values with ConvertFromLua.
+// Non-string keys are converted to their string form (e.g. 1 -> "1").
+func TableToMap(L *lua.LState, table *lua.LTable) map[string]interface{} {
+	return tableToMap(table, make(map[*lua.LTable]bool))
+}
+
+// ConvertFromLua converts a Lua value to its Go equivalent:
+//   - nil -> nil, boolean -> bool, string -> string
+//   - number -> int64 when it is an integer within ±2^53, float64 otherwise
+//   - table with keys 1..n only -> []interface{}, other tables -> map[string]interface{}
+//     (an empty table converts to an empty map)
+//   - functions, userdata, threads and channels -> nil
+//
+// Recursive tables are cut off with nil where a table repeats.
+func ConvertFromLua(L *lua.LState, value lua.LValue) interface{} {
+	return convertFromLua(value, make(map[*lua.LTable]bool))
+}
+
+func convertFromLua(value lua.LValue, seen map[*lua.LTable]bool) interface{} {
+	switch v := value.(type) {
+	case lua.LBool:
+		return bool(v)
+	case lua.LNumber:
+		return convertNumber(float64(v))
+	case lua.LString:
+		return string(v)
+	case *lua.LTable:
+		if seen[v] {
+			return nil
+		}
+		if isArray(v) {
+			return tableToSlice(v, seen)
+		}
+		return tableToMap(v, seen)
+	default:
+		return nil
+	}
+}
+
+// convertNumber keeps integral numbers as int64 so IDs and counters keep their exact value
+func convertNumber(n float64) interface{} {
+	if n == math.Trunc(n) && math.Abs(n) <= maxSafeInteger {
+		return int64(n)
+	}
+	return n
+}
+
+// isArray reports whether a non-empty table has only the keys 1..n
+func isArray(table *lua.LTable) bool {
+	n := table.MaxN()
+	if n == 0 {
+		return false
+	}
+	count := 0
+	table.ForEach(func(_, _ lua.LValue) {
+		count++
+	})
+	return count == n
+}
+
+func tableToSlice(table *lua.LTable, seen map[*lua.LTable]bool) []interface{} {
+	seen[table] = true
+	defer delete(seen, table)
+
+	slice := make([]interface{}, table.MaxN())
+	for i := range slice {
+		slice[i] = convertFromLua(table.RawGetInt(i+1), seen)
+	}
+	return slice
+}
+
+func tableToMap(table *lua.LTable, seen map[*lua.LTable]bool) map[string]interface{} {
+	seen[table] = true
+	defer delete(seen, table)
+
+	m := make(map[string]interface{})
+	table.ForEach(func(key, val lua.LValue) {
+		m[key.String()] = convertFromLua(val, seen)
+	})
+	return m
+}
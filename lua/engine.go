@@ -0,0 +1,150 @@
+package lua
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ErrResourceExceeded can be wrapped by engines for scripts aborted by a resource limit;
+// the executor reports them with ExecutionStatusResourceExceeded
+var ErrResourceExceeded = errors.New("resource limit exceeded")
+
+// Engine runs scripts of one language for the Executor, which adds the timeout, logging,
+// metrics, tracing and recording around it. LuaEngine is the default; a JavaScript engine
+// lives in the lua/goja module (kept separate so this package doesn't depend on goja) and
+// other languages plug in by implementing Engine.
+type Engine interface {
+	// Run calls the script's handle function with payload. ctx carries the execution deadline
+	// and the engine must abort the script once it is done. The result's logs are kept on failure.
+	Run(ctx context.Context, script Script, payload map[string]interface{}) (EngineResult, error)
+}
+
+// ScriptValidator is implemented by engines supporting Executor.Validate
+type ScriptValidator interface {
+	Validate(script Script) ScriptIssues
+}
+
+// EngineResult is the outcome of one Engine.Run
+type EngineResult struct {
+	Output        map[string]interface{}
+	Logs          []LogEntry
+	LogsTruncated bool
+}
+
+// LuaEngineConfig holds configuration for the Lua engine
+type LuaEngineConfig struct {
+	Sandbox       *SandboxConfig // Optional: if nil, DefaultSandboxConfig() is used
+	VMPool        *VMPool        // Optional: reuses VMs across executions; if nil, each execution creates a fresh VM from Sandbox
+	HostFunctions HostFunctionRegistry
+	LogCapture    *LogCaptureConfig // Optional: captures print() and log(level, msg) into EngineResult.Logs
+}
+
+// LuaEngine runs Lua scripts in sandboxed gopher-lua VMs
+type LuaEngine struct {
+	config LuaEngineConfig
+}
+
+// NewLuaEngine creates a new Lua engine
+func NewLuaEngine(config LuaEngineConfig) *LuaEngine {
+	return &LuaEngine{config: config}
+}
+
+// Run executes the script's top-level code and calls handle with payload converted to a table
+func (e *LuaEngine) Run(ctx context.Context, script Script, payload map[string]interface{}) (result EngineResult, err error) {
+	L, release := e.acquireVM()
+	defer release()
+
+	// Register host functions if provided
+	if e.config.HostFunctions != nil {
+		e.config.HostFunctions.RegisterFunctions(L, script.GetID(), script.GetName(), script.GetVersion())
+	}
+
+	var logs *logCapture
+	if e.config.LogCapture != nil {
+		logs = newLogCapture(*e.config.LogCapture)
+		logs.register(L)
+		defer func() {
+			result.Logs, result.LogsTruncated = logs.result()
+		}()
+	}
+
+	// Set up timeout cancellation and the instruction limit
+	if maxInstructions := e.sandboxConfig().MaxInstructions; maxInstructions > 0 {
+		L.SetContext(withInstructionLimit(ctx, maxInstructions))
+	} else {
+		L.SetContext(ctx)
+	}
+
+	// Load the (cached) compiled script and execute its top-level code
+	proto, err := CompileScript(script)
+	if err == nil {
+		L.Push(L.NewFunctionFromProto(proto))
+		err = L.PCall(0, lua.MultRet, nil)
+	}
+	if err != nil {
+		return result, fmt.Errorf("failed to load script: %w", err)
+	}
+
+	handleFn := L.GetGlobal("handle")
+	if handleFn == lua.LNil {
+		return result, fmt.Errorf("script missing handle function")
+	}
+
+	// Call the handle function
+	if err = L.CallByParam(lua.P{
+		Fn:      handleFn,
+		NRet:    1,
+		Protect: true,
+	}, ConvertToLua(L, payload)); err != nil {
+		return result, err
+	}
+	result.Output = outputFromLua(L, L.Get(-1))
+	L.Pop(1)
+	return result, nil
+}
+
+// sandboxConfig returns the sandbox of the engine's VMs: the pool's, the provided one
+// or the strict default
+func (e *LuaEngine) sandboxConfig() SandboxConfig {
+	switch {
+	case e.config.VMPool != nil:
+		return e.config.VMPool.config
+	case e.config.Sandbox != nil:
+		return *e.config.Sandbox
+	default:
+		return DefaultSandboxConfig()
+	}
+}
+
+// acquireVM returns a VM for one execution and a function releasing it. Pooled VMs run the
+// execution in a fresh global environment.
+func (e *LuaEngine) acquireVM() (*lua.LState, func()) {
+	if e.config.VMPool == nil {
+		L := NewVM(e.sandboxConfig())
+		return L, L.Close
+	}
+
+	L := e.config.VMPool.Get()
+	env, restore := newScriptEnv(L)
+	L.Env = env
+	return L, func() {
+		restore()
+		L.Env = L.G.Global
+		e.config.VMPool.Put(L)
+	}
+}
+
+// outputFromLua converts the value returned by handle into ExecutionResult.Output
+func outputFromLua(L *lua.LState, value lua.LValue) map[string]interface{} {
+	if value == lua.LNil {
+		return nil
+	}
+	converted := ConvertFromLua(L, value)
+	if m, ok := converted.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{"value": converted}
+}
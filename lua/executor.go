@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"time"
 
-	converter "github.com/kerimovok/go-lua-converter"
 	lua "github.com/yuin/gopher-lua"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 // Script represents a script that can be executed.
@@ -25,6 +25,9 @@ type ExecutionResult struct {
 	ScriptVersion string
 	Status        ExecutionStatus
 	ErrorMessage  *string
+	Output        map[string]interface{} // Value returned by handle: tables as-is, other values under "value"
+	Logs          []LogEntry             // print() and log() output, if ExecutorConfig.LogCapture is set
+	LogsTruncated bool                   // Whether log entries were dropped or cut by the capture limits
 	DurationMs    int64
 	ExecutedAt    time.Time
 }
@@ -35,6 +38,8 @@ type ExecutionStatus string
 const (
 	ExecutionStatusSuccess ExecutionStatus = "success"
 	ExecutionStatusFailure ExecutionStatus = "failure"
+	// ExecutionStatusResourceExceeded marks scripts aborted by a sandbox resource limit
+	ExecutionStatusResourceExceeded ExecutionStatus = "resource_exceeded"
 )
 
 // HostFunctionRegistry allows registering custom host functions for Lua scripts.
@@ -53,10 +58,18 @@ type ExecutorConfig struct {
 	Logger        *zap.Logger
 	HostFunctions HostFunctionRegistry
 	Recorder      ExecutionRecorder
-	Sandbox       *SandboxConfig // Optional: if nil, DefaultSandboxConfig() is used
+	Sandbox       *SandboxConfig    // Optional: if nil, DefaultSandboxConfig() is used
+	WorkerPool    *WorkerPool       // Optional: bounds ExecuteStream concurrency; if nil, each stream uses NewWorkerPool(0)
+	VMPool        *VMPool           // Optional: reuses VMs across executions; if nil, each execution creates a fresh VM from Sandbox
+	Metrics       Metrics           // Optional: receives execution durations, statuses and timeouts
+	StartSpan     SpanStarter       // Optional: starts a tracing span per execution
+	LogCapture    *LogCaptureConfig // Optional: captures print() and log(level, msg) into ExecutionResult.Logs
+	Engine        Engine            // Optional: runs the scripts; if nil, a LuaEngine is built from Sandbox, VMPool, HostFunctions and LogCapture
+	// LogScriptOutput also writes the captured script logs (see LogCapture) to Logger at their level
+	LogScriptOutput bool
 }
 
-// Executor executes Lua scripts with timeout, error handling, and result recording.
+// Executor executes scripts with timeout, error handling, and result recording.
 type Executor struct {
 	config ExecutorConfig
 }
@@ -66,102 +79,58 @@ func NewExecutor(config ExecutorConfig) *Executor {
 	if config.Timeout <= 0 {
 		config.Timeout = 5 * time.Second
 	}
+	config.Metrics = metricsOrNop(config.Metrics)
+	if config.Engine == nil {
+		config.Engine = NewLuaEngine(LuaEngineConfig{
+			Sandbox:       config.Sandbox,
+			VMPool:        config.VMPool,
+			HostFunctions: config.HostFunctions,
+			LogCapture:    config.LogCapture,
+		})
+	}
 
 	return &Executor{
 		config: config,
 	}
 }
 
-// Execute executes a Lua script with the given payload.
-// The payload is converted to a Lua table and passed to the script's "handle" function.
+// Execute executes a script with the given payload.
+// The payload is passed to the script's "handle" function by the engine (for Lua, as a table).
 // Returns an ExecutionResult with the outcome.
 func (e *Executor) Execute(ctx context.Context, script Script, payload map[string]interface{}) ExecutionResult {
 	startTime := time.Now()
-	var execErr error
 	var errorMsg *string
 
-	// Create a fresh sandboxed VM for this execution
-	// Use provided sandbox config or default to strict sandboxing
-	sandboxConfig := DefaultSandboxConfig()
-	if e.config.Sandbox != nil {
-		sandboxConfig = *e.config.Sandbox
-	}
-	L := NewVM(sandboxConfig)
-	defer L.Close()
-
-	// Register host functions if provided
-	if e.config.HostFunctions != nil {
-		e.config.HostFunctions.RegisterFunctions(L, script.GetID(), script.GetName(), script.GetVersion())
+	endSpan := func(ExecutionResult) {}
+	if e.config.StartSpan != nil {
+		ctx, endSpan = e.config.StartSpan(ctx, script)
 	}
 
 	// Create context with timeout
 	execCtx, cancel := context.WithTimeout(ctx, e.config.Timeout)
 	defer cancel()
 
-	// Set up timeout cancellation
-	L.SetContext(execCtx)
-
-	// Load and execute the script code
-	if err := L.DoString(script.GetCode()); err != nil {
-		execErr = err
-		errStr := fmt.Sprintf("failed to load script: %v", err)
+	output, execErr := e.config.Engine.Run(execCtx, script, payload)
+	timedOut := execErr != nil && execCtx.Err() == context.DeadlineExceeded
+	if timedOut {
+		errStr := fmt.Sprintf("script execution timed out after %v", e.config.Timeout)
 		errorMsg = &errStr
 		if e.config.Logger != nil {
-			e.config.Logger.Error("Failed to load script",
+			e.config.Logger.Error("Script execution timed out",
 				zap.String("script_id", script.GetID()),
 				zap.String("script_name", script.GetName()),
 				zap.String("script_version", script.GetVersion()),
-				zap.Error(err))
+				zap.Duration("timeout", e.config.Timeout))
 		}
-	}
-
-	if execErr == nil {
-		// Get the handle function
-		handleFn := L.GetGlobal("handle")
-		if handleFn == lua.LNil {
-			execErr = fmt.Errorf("script missing handle function")
-			errStr := "script missing handle function"
-			errorMsg = &errStr
-			if e.config.Logger != nil {
-				e.config.Logger.Error("Script missing handle function",
-					zap.String("script_id", script.GetID()),
-					zap.String("script_name", script.GetName()),
-					zap.String("script_version", script.GetVersion()))
-			}
-		} else {
-			// Convert payload to Lua table
-			payloadTable := converter.MapToTable(L, payload)
-
-			// Call the handle function
-			if err := L.CallByParam(lua.P{
-				Fn:      handleFn,
-				NRet:    0,
-				Protect: true,
-			}, payloadTable); err != nil {
-				execErr = err
-				// Check if it was a timeout
-				if execCtx.Err() == context.DeadlineExceeded {
-					errStr := fmt.Sprintf("script execution timed out after %v", e.config.Timeout)
-					errorMsg = &errStr
-					if e.config.Logger != nil {
-						e.config.Logger.Error("Script execution timed out",
-							zap.String("script_id", script.GetID()),
-							zap.String("script_name", script.GetName()),
-							zap.String("script_version", script.GetVersion()),
-							zap.Duration("timeout", e.config.Timeout))
-					}
-				} else {
-					errStr := err.Error()
-					errorMsg = &errStr
-					if e.config.Logger != nil {
-						e.config.Logger.Error("Script execution failed",
-							zap.String("script_id", script.GetID()),
-							zap.String("script_name", script.GetName()),
-							zap.String("script_version", script.GetVersion()),
-							zap.Error(err))
-					}
-				}
-			}
+	} else if execErr != nil {
+		errStr := execErr.Error()
+		errorMsg = &errStr
+		if e.config.Logger != nil {
+			e.config.Logger.Error("Script execution failed",
+				zap.String("script_id", script.GetID()),
+				zap.String("script_name", script.GetName()),
+				zap.String("script_version", script.GetVersion()),
+				zap.Error(execErr))
 		}
 	}
 
@@ -171,7 +140,9 @@ func (e *Executor) Execute(ctx context.Context, script Script, payload map[strin
 
 	// Determine status
 	status := ExecutionStatusSuccess
-	if execErr != nil {
+	if isResourceExceeded(execErr) {
+		status = ExecutionStatusResourceExceeded
+	} else if execErr != nil {
 		status = ExecutionStatusFailure
 	}
 
@@ -185,16 +156,28 @@ func (e *Executor) Execute(ctx context.Context, script Script, payload map[strin
 			zap.Int64("duration_ms", durationMs))
 	}
 
+	e.config.Metrics.Executed(script.GetID(), script.GetVersion(), status, duration)
+	if timedOut {
+		e.config.Metrics.TimedOut(script.GetID(), script.GetVersion())
+	}
+
 	result := ExecutionResult{
 		ScriptID:      script.GetID(),
 		ScriptName:    script.GetName(),
 		ScriptVersion: script.GetVersion(),
 		Status:        status,
 		ErrorMessage:  errorMsg,
+		Output:        output.Output,
+		Logs:          output.Logs,
+		LogsTruncated: output.LogsTruncated,
 		DurationMs:    durationMs,
 		ExecutedAt:    startTime,
 	}
 
+	if e.config.LogScriptOutput && e.config.Logger != nil {
+		e.logScriptOutput(script, result.Logs)
+	}
+
 	// Record execution result if recorder is provided
 	if e.config.Recorder != nil {
 		recordCtx, recordCancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -209,5 +192,35 @@ func (e *Executor) Execute(ctx context.Context, script Script, payload map[strin
 		}
 	}
 
+	endSpan(result)
 	return result
 }
+
+// logScriptOutput writes the captured log entries of a script to the logger
+func (e *Executor) logScriptOutput(script Script, entries []LogEntry) {
+	for _, entry := range entries {
+		level := zapcore.InfoLevel
+		switch entry.Level {
+		case LogLevelDebug:
+			level = zapcore.DebugLevel
+		case LogLevelWarn:
+			level = zapcore.WarnLevel
+		case LogLevelError:
+			level = zapcore.ErrorLevel
+		}
+		e.config.Logger.Log(level, entry.Message,
+			zap.String("script_id", script.GetID()),
+			zap.String("script_name", script.GetName()),
+			zap.String("script_version", script.GetVersion()),
+			zap.Time("script_time", entry.Time))
+	}
+}
+
+// Validate checks a script without running it, if the engine supports validation
+// (see LuaEngine.Validate); otherwise it reports no issues
+func (e *Executor) Validate(script Script) ScriptIssues {
+	if validator, ok := e.config.Engine.(ScriptValidator); ok {
+		return validator.Validate(script)
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Package goja implements lua.Engine for JavaScript on goja, so the lua.Executor can run
+// JavaScript scripts with the same handle(payload) contract. It is a separate module so
+// users of the lua package don't pull in goja.
+package goja
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	js "github.com/dop251/goja"
+	"github.com/kerimovok/go-pkg-utils/collections"
+	"github.com/kerimovok/go-pkg-utils/lua"
+)
+
+// CompiledScriptsCacheSize is the number of compiled script versions kept in memory
+const CompiledScriptsCacheSize = 1000
+
+// Config holds configuration for the JavaScript engine
+type Config struct {
+	// MaxCallStackSize aborts scripts recursing deeper with lua.ErrResourceExceeded - default: 1000
+	MaxCallStackSize int
+	// LogCapture captures print(...), console.log(...) and log(level, msg) into EngineResult.Logs
+	LogCapture *lua.LogCaptureConfig
+	// Setup registers host functions and values on each runtime before the script runs (optional)
+	Setup func(vm *js.Runtime, script lua.Script) error
+}
+
+// Engine runs JavaScript scripts, each execution in a fresh goja runtime. Runtimes only
+// have the ECMAScript builtins: no require, file system or network access.
+type Engine struct {
+	config   Config
+	programs *collections.LRU[string, compiledProgram]
+}
+
+// compiledProgram is a cached compilation of a script version
+type compiledProgram struct {
+	code    string
+	program *js.Program
+}
+
+// NewEngine creates a new JavaScript engine
+func NewEngine(config Config) *Engine {
+	if config.MaxCallStackSize <= 0 {
+		config.MaxCallStackSize = 1000
+	}
+	return &Engine{
+		config:   config,
+		programs: collections.NewLRU[string, compiledProgram](CompiledScriptsCacheSize),
+	}
+}
+
+// Run executes the script's top-level code and calls handle with payload. The script is
+// interrupted once ctx is done. A returned object becomes the output itself; any other
+// value is stored under "value".
+func (e *Engine) Run(ctx context.Context, script lua.Script, payload map[string]interface{}) (result lua.EngineResult, err error) {
+	program, err := e.compile(script)
+	if err != nil {
+		return result, err
+	}
+
+	vm := js.New()
+	vm.SetMaxCallStackSize(e.config.MaxCallStackSize)
+	stop := context.AfterFunc(ctx, func() {
+		vm.Interrupt(ctx.Err())
+	})
+	defer stop()
+
+	if e.config.LogCapture != nil {
+		logs := lua.NewLogRecorder(*e.config.LogCapture)
+		if err := registerLogs(vm, logs); err != nil {
+			return result, err
+		}
+		defer func() {
+			result.Logs, result.LogsTruncated = logs.Result()
+		}()
+	}
+	if e.config.Setup != nil {
+		if err := e.config.Setup(vm, script); err != nil {
+			return result, fmt.Errorf("failed to set up runtime: %w", err)
+		}
+	}
+
+	if _, err := vm.RunProgram(program); err != nil {
+		return result, fmt.Errorf("failed to load script: %w", runError(err))
+	}
+
+	handle, ok := js.AssertFunction(vm.Get("handle"))
+	if !ok {
+		return result, fmt.Errorf("script missing handle function")
+	}
+	value, err := handle(js.Undefined(), vm.ToValue(payload))
+	if err != nil {
+		return result, runError(err)
+	}
+	result.Output = outputFromJS(value)
+	return result, nil
+}
+
+// Validate reports syntax errors of a script
+func (e *Engine) Validate(script lua.Script) lua.ScriptIssues {
+	if _, err := js.Compile(script.GetName(), script.GetCode(), false); err != nil {
+		return lua.ScriptIssues{{Code: lua.IssueSyntax, Message: err.Error()}}
+	}
+	return nil
+}
+
+// compile returns the cached compilation of the script version, recompiling changed code
+func (e *Engine) compile(script lua.Script) (*js.Program, error) {
+	key := script.GetID() + "@" + script.GetVersion()
+	code := script.GetCode()
+	if cached, ok := e.programs.Get(key); ok && cached.code == code {
+		return cached.program, nil
+	}
+
+	program, err := js.Compile(script.GetName(), code, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile script: %w", err)
+	}
+	e.programs.Set(key, compiledProgram{code: code, program: program})
+	return program, nil
+}
+
+// runError marks stack overflows as resource limit errors
+func runError(err error) error {
+	var overflow *js.StackOverflowError
+	if errors.As(err, &overflow) {
+		return fmt.Errorf("%w: maximum call stack size exceeded", lua.ErrResourceExceeded)
+	}
+	return err
+}
+
+// registerLogs defines print, console.log/info/warn/error/debug and log(level, msg)
+// so their output is captured
+func registerLogs(vm *js.Runtime, logs *lua.LogRecorder) error {
+	logAt := func(level string) func(call js.FunctionCall) js.Value {
+		return func(call js.FunctionCall) js.Value {
+			parts := make([]string, len(call.Arguments))
+			for i, arg := range call.Arguments {
+				parts[i] = arg.String()
+			}
+			logs.Add(level, strings.Join(parts, " "))
+			return js.Undefined()
+		}
+	}
+
+	console := vm.NewObject()
+	for name, level := range map[string]string{
+		"log":   lua.LogLevelInfo,
+		"info":  lua.LogLevelInfo,
+		"debug": lua.LogLevelDebug,
+		"warn":  lua.LogLevelWarn,
+		"error": lua.LogLevelError,
+	} {
+		if err := console.Set(name, logAt(level)); err != nil {
+			return err
+		}
+	}
+	if err := vm.Set("console", console); err != nil {
+		return err
+	}
+	if err := vm.Set("print", logAt(lua.LogLevelInfo)); err != nil {
+		return err
+	}
+	return vm.Set("log", func(call js.FunctionCall) js.Value {
+		level := call.Argument(0).String()
+		switch level {
+		case lua.LogLevelDebug, lua.LogLevelInfo, lua.LogLevelWarn, lua.LogLevelError:
+		default:
+			panic(vm.NewTypeError("log: level must be debug, info, warn or error"))
+		}
+		logs.Add(level, call.Argument(1).String())
+		return js.Undefined()
+	})
+}
+
+// outputFromJS converts the value returned by handle into ExecutionResult.Output
+func outputFromJS(value js.Value) map[string]interface{} {
+	if value == nil || js.IsUndefined(value) || js.IsNull(value) {
+		return nil
+	}
+	exported := value.Export()
+	if m, ok := exported.(map[string]interface{}); ok {
+		return m
+	}
+	return map[string]interface{}{"value": exported}
+}
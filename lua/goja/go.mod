@@ -0,0 +1,20 @@
+module github.com/kerimovok/go-pkg-utils/lua/goja
+
+go 1.24.5
+
+require (
+	github.com/dop251/goja v0.0.0-20240220182346-e401ed450204
+	github.com/kerimovok/go-pkg-utils v0.0.0
+)
+
+require (
+	github.com/dlclark/regexp2 v1.7.0 // indirect
+	github.com/go-sourcemap/sourcemap v2.1.3+incompatible // indirect
+	github.com/google/pprof v0.0.0-20230207041349-798e818bf904 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.27.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+)
+
+replace github.com/kerimovok/go-pkg-utils => ../..
@@ -0,0 +1,60 @@
+package lua
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrInstructionLimit aborts scripts that execute more than SandboxConfig.MaxInstructions
+var ErrInstructionLimit = errors.New("instruction limit exceeded")
+
+// closedChan is returned by instructionBudget.Done once the budget is spent
+var closedChan = func() chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}()
+
+// instructionBudget is a context counting VM instructions: with a context set, the VM
+// checks Done() before every instruction, so the budget is spent one call at a time
+type instructionBudget struct {
+	context.Context
+	remaining atomic.Int64
+}
+
+// withInstructionLimit returns a context that is done after max instructions
+func withInstructionLimit(ctx context.Context, max int64) context.Context {
+	budget := &instructionBudget{Context: ctx}
+	budget.remaining.Store(max)
+	return budget
+}
+
+func (b *instructionBudget) Done() <-chan struct{} {
+	if b.remaining.Add(-1) < 0 {
+		return closedChan
+	}
+	return b.Context.Done()
+}
+
+func (b *instructionBudget) Err() error {
+	if b.remaining.Load() < 0 {
+		return ErrInstructionLimit
+	}
+	return b.Context.Err()
+}
+
+// isResourceExceeded reports whether a script error was caused by a sandbox resource limit
+func isResourceExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrResourceExceeded) {
+		return true
+	}
+	msg := err.Error()
+	return strings.Contains(msg, ErrInstructionLimit.Error()) ||
+		strings.Contains(msg, "registry overflow") ||
+		strings.Contains(msg, "stack overflow")
+}
@@ -0,0 +1,117 @@
+package lua
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Log levels accepted by the log(level, msg) host function
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// LogEntry is a line written by a script with print() ("info" level) or log(level, msg)
+type LogEntry struct {
+	Level   string
+	Message string
+	Time    time.Time
+}
+
+// LogCaptureConfig bounds the logs captured per execution
+type LogCaptureConfig struct {
+	MaxEntries int // Maximum entries kept - default: 100
+	MaxBytes   int // Maximum total message bytes kept - default: 64 KiB
+}
+
+// logCapture collects the log entries of one execution, dropping entries past the limits
+type logCapture struct {
+	mu        sync.Mutex
+	config    LogCaptureConfig
+	entries   []LogEntry
+	bytes     int
+	truncated bool
+}
+
+func newLogCapture(config LogCaptureConfig) *logCapture {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = 100
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = 64 * 1024
+	}
+	return &logCapture{config: config}
+}
+
+// add appends an entry, truncating its message to the remaining byte budget
+func (c *logCapture) add(level, message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.config.MaxEntries || c.bytes >= c.config.MaxBytes {
+		c.truncated = true
+		return
+	}
+	if remaining := c.config.MaxBytes - c.bytes; len(message) > remaining {
+		message = message[:remaining]
+		c.truncated = true
+	}
+	c.bytes += len(message)
+	c.entries = append(c.entries, LogEntry{Level: level, Message: message, Time: time.Now()})
+}
+
+// result returns the captured entries and whether any were dropped or cut
+func (c *logCapture) result() ([]LogEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries, c.truncated
+}
+
+// LogRecorder collects the log entries of one execution within the LogCaptureConfig limits,
+// so other engines capture script output like LuaEngine does
+type LogRecorder struct {
+	capture *logCapture
+}
+
+// NewLogRecorder creates a recorder for one execution
+func NewLogRecorder(config LogCaptureConfig) *LogRecorder {
+	return &LogRecorder{capture: newLogCapture(config)}
+}
+
+// Add records an entry, dropping or cutting it once the limits are reached
+func (r *LogRecorder) Add(level, message string) {
+	r.capture.add(level, message)
+}
+
+// Result returns the recorded entries and whether any were dropped or cut
+func (r *LogRecorder) Result() ([]LogEntry, bool) {
+	return r.capture.result()
+}
+
+// register replaces print and defines log(level, msg) so their output is captured
+func (c *logCapture) register(L *lua.LState) {
+	L.SetGlobal("print", L.NewFunction(func(L *lua.LState) int {
+		parts := make([]string, L.GetTop())
+		for i := range parts {
+			parts[i] = L.ToStringMeta(L.Get(i + 1)).String()
+		}
+		c.add(LogLevelInfo, strings.Join(parts, "\t"))
+		return 0
+	}))
+
+	L.SetGlobal("log", L.NewFunction(func(L *lua.LState) int {
+		level := L.CheckString(1)
+		switch level {
+		case LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		default:
+			L.ArgError(1, "level must be debug, info, warn or error")
+		}
+		c.add(level, L.ToStringMeta(L.CheckAny(2)).String())
+		return 0
+	}))
+}
@@ -0,0 +1,36 @@
+package lua
+
+import (
+	"context"
+	"time"
+)
+
+// Metrics receives execution events, e.g. to update Prometheus histograms and counters
+// labeled by script. Implementations must be safe for concurrent use; embed NopMetrics
+// to implement only some of the methods.
+type Metrics interface {
+	// Executed is called after every execution with its status and duration
+	Executed(scriptID, scriptVersion string, status ExecutionStatus, duration time.Duration)
+	// TimedOut is called when an execution is aborted by the executor timeout
+	TimedOut(scriptID, scriptVersion string)
+}
+
+// NopMetrics is a Metrics implementation that ignores all events
+type NopMetrics struct{}
+
+func (NopMetrics) Executed(scriptID, scriptVersion string, status ExecutionStatus, duration time.Duration) {
+}
+func (NopMetrics) TimedOut(scriptID, scriptVersion string) {}
+
+// metricsOrNop returns m, or NopMetrics if m is nil
+func metricsOrNop(m Metrics) Metrics {
+	if m == nil {
+		return NopMetrics{}
+	}
+	return m
+}
+
+// SpanStarter starts a span for one execution (e.g. with script ID and version attributes)
+// and returns the span context, which host functions receive, and a function ending the
+// span with the execution result
+type SpanStarter func(ctx context.Context, script Script) (context.Context, func(result ExecutionResult))
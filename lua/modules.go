@@ -0,0 +1,96 @@
+package lua
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	lua "github.com/yuin/gopher-lua"
+)
+
+// ModuleRegistry is a set of shared Lua libraries scripts can load with require(name).
+// It is a HostFunctionRegistry providing a controlled require: only registered modules
+// resolve, nothing is read from the file system. Modules are loaded once per execution.
+type ModuleRegistry struct {
+	mu      sync.RWMutex
+	modules map[string]*lua.FunctionProto
+}
+
+// NewModuleRegistry creates an empty module registry
+func NewModuleRegistry() *ModuleRegistry {
+	return &ModuleRegistry{modules: make(map[string]*lua.FunctionProto)}
+}
+
+// Register compiles and registers a module; its code should return the module table
+func (r *ModuleRegistry) Register(name, code string) error {
+	proto, err := CompileString(code, name)
+	if err != nil {
+		return fmt.Errorf("module %s: %w", name, err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modules[name] = proto
+	return nil
+}
+
+// Unregister removes a module
+func (r *ModuleRegistry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.modules, name)
+}
+
+// Names returns the registered module names in sorted order
+func (r *ModuleRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.modules))
+	for name := range r.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegisterFunctions registers require for one execution
+func (r *ModuleRegistry) RegisterFunctions(L *lua.LState, scriptID, scriptName, scriptVersion string) {
+	loaded := make(map[string]lua.LValue)
+	loading := make(map[string]bool)
+
+	L.SetGlobal("require", L.NewFunction(func(L *lua.LState) int {
+		name := L.CheckString(1)
+		if value, ok := loaded[name]; ok {
+			L.Push(value)
+			return 1
+		}
+		if loading[name] {
+			L.RaiseError("circular require of module %q", name)
+		}
+
+		r.mu.RLock()
+		proto, ok := r.modules[name]
+		r.mu.RUnlock()
+		if !ok {
+			L.RaiseError("module %q not found", name)
+		}
+
+		loading[name] = true
+		defer delete(loading, name)
+
+		// Run the module in the script's global environment
+		fn := L.NewFunctionFromProto(proto)
+		fn.Env = L.G.Global
+		L.Push(fn)
+		L.Call(0, 1)
+
+		value := L.Get(-1)
+		L.Pop(1)
+		if value == lua.LNil {
+			value = lua.LTrue
+		}
+		loaded[name] = value
+		L.Push(value)
+		return 1
+	}))
+}
@@ -0,0 +1,46 @@
+package lua
+
+import (
+	"context"
+)
+
+// PipelineResult is the outcome of ExecutePipeline
+type PipelineResult struct {
+	Steps      []ExecutionResult      // Results of the executed steps, in order
+	Output     map[string]interface{} // Output of the last executed step
+	Status     ExecutionStatus        // Status of the last executed step
+	FailedStep int                    // Index of the failed step, -1 if all steps succeeded
+}
+
+// ExecutePipeline executes scripts in order, passing each script's output as the next
+// script's payload; a step returning nothing passes its own payload on unchanged. The
+// pipeline stops at the first failing step or when ctx is done.
+func (e *Executor) ExecutePipeline(ctx context.Context, scripts []Script, payload map[string]interface{}) PipelineResult {
+	result := PipelineResult{
+		Steps:      make([]ExecutionResult, 0, len(scripts)),
+		Output:     payload,
+		Status:     ExecutionStatusSuccess,
+		FailedStep: -1,
+	}
+
+	for i, script := range scripts {
+		var step ExecutionResult
+		if err := ctx.Err(); err != nil {
+			step = canceledResult(script, err)
+		} else {
+			step = e.Execute(ctx, script, result.Output)
+		}
+		result.Steps = append(result.Steps, step)
+		result.Status = step.Status
+
+		if step.Status != ExecutionStatusSuccess {
+			result.FailedStep = i
+			result.Output = nil
+			return result
+		}
+		if step.Output != nil {
+			result.Output = step.Output
+		}
+	}
+	return result
+}
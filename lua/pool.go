@@ -1,5 +1,7 @@
 package lua
 
+import "context"
+
 // WorkerPool manages concurrent execution with bounded concurrency.
 type WorkerPool struct {
 	slots chan struct{}
@@ -22,6 +24,21 @@ func (p *WorkerPool) Acquire() {
 	p.slots <- struct{}{}
 }
 
+// AcquireContext blocks until a worker slot is available or the context is done.
+func (p *WorkerPool) AcquireContext(ctx context.Context) error {
+	select {
+	case p.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Size returns the maximum number of concurrent workers.
+func (p *WorkerPool) Size() int {
+	return cap(p.slots)
+}
+
 // Release releases a worker slot.
 func (p *WorkerPool) Release() {
 	<-p.slots
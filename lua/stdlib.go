@@ -0,0 +1,379 @@
+package lua
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/jsonx"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// StdlibConfig selects the modules of the built-in host function library
+type StdlibConfig struct {
+	EnableJSON   bool        // json.encode / json.decode
+	EnableCrypto bool        // crypto.hash / crypto.hmac
+	HTTP         *HTTPConfig // http.fetch - disabled if nil
+	KV           KVStore     // kv.get / kv.set / kv.delete, scoped per script - disabled if nil
+}
+
+// HTTPConfig restricts http.fetch
+type HTTPConfig struct {
+	AllowedHosts     []string      // Exact hosts or "*.example.com" wildcards; nothing is allowed if empty
+	AllowedMethods   []string      // Defaults to GET and POST
+	Timeout          time.Duration // Per request - defaults to 5 seconds
+	MaxResponseBytes int64         // Larger responses fail - defaults to 1MB
+	Client           *http.Client  // Optional base client (its CheckRedirect is replaced)
+}
+
+// KVStore is the storage behind the kv module; keys are already scoped to the script
+type KVStore interface {
+	Get(ctx context.Context, key string) (string, bool, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Stdlib is a HostFunctionRegistry providing sandboxed json, crypto, http and kv modules
+type Stdlib struct {
+	config StdlibConfig
+	client *http.Client
+}
+
+// NewStdlib creates the built-in host function library
+func NewStdlib(config StdlibConfig) *Stdlib {
+	s := &Stdlib{config: config}
+	if config.HTTP != nil {
+		if len(config.HTTP.AllowedMethods) == 0 {
+			s.config.HTTP.AllowedMethods = []string{http.MethodGet, http.MethodPost}
+		}
+		if config.HTTP.Timeout <= 0 {
+			s.config.HTTP.Timeout = 5 * time.Second
+		}
+		if config.HTTP.MaxResponseBytes <= 0 {
+			s.config.HTTP.MaxResponseBytes = 1 << 20
+		}
+
+		client := &http.Client{}
+		if config.HTTP.Client != nil {
+			*client = *config.HTTP.Client
+		}
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return fmt.Errorf("too many redirects")
+			}
+			if !s.hostAllowed(req.URL.Hostname()) {
+				return fmt.Errorf("redirect to host %s is not allowed", req.URL.Hostname())
+			}
+			return nil
+		}
+		s.client = client
+	}
+	return s
+}
+
+// RegisterFunctions registers the enabled modules as globals
+func (s *Stdlib) RegisterFunctions(L *lua.LState, scriptID, scriptName, scriptVersion string) {
+	if s.config.EnableJSON {
+		L.SetGlobal("json", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+			"encode": luaJSONEncode,
+			"decode": luaJSONDecode,
+		}))
+	}
+	if s.config.EnableCrypto {
+		L.SetGlobal("crypto", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+			"hash": luaHash,
+			"hmac": luaHMAC,
+		}))
+	}
+	if s.config.HTTP != nil {
+		L.SetGlobal("http", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+			"fetch": s.luaFetch,
+		}))
+	}
+	if s.config.KV != nil {
+		prefix := scriptID + ":"
+		L.SetGlobal("kv", L.SetFuncs(L.NewTable(), map[string]lua.LGFunction{
+			"get":    s.luaKVGet(prefix),
+			"set":    s.luaKVSet(prefix),
+			"delete": s.luaKVDelete(prefix),
+		}))
+	}
+}
+
+// CombineHostFunctions registers the functions of several registries in order
+func CombineHostFunctions(registries ...HostFunctionRegistry) HostFunctionRegistry {
+	return combinedHostFunctions(registries)
+}
+
+type combinedHostFunctions []HostFunctionRegistry
+
+func (c combinedHostFunctions) RegisterFunctions(L *lua.LState, scriptID, scriptName, scriptVersion string) {
+	for _, registry := range c {
+		registry.RegisterFunctions(L, scriptID, scriptName, scriptVersion)
+	}
+}
+
+// luaJSONEncode implements json.encode(value) -> string | nil, err
+func luaJSONEncode(L *lua.LState) int {
+	data, err := jsonx.Marshal(ConvertFromLua(L, L.CheckAny(1)))
+	if err != nil {
+		return pushError(L, err)
+	}
+	L.Push(lua.LString(data))
+	return 1
+}
+
+// luaJSONDecode implements json.decode(string) -> value | nil, err
+func luaJSONDecode(L *lua.LState) int {
+	var value interface{}
+	if err := jsonx.UnmarshalFromString(L.CheckString(1), &value); err != nil {
+		return pushError(L, err)
+	}
+	L.Push(ConvertToLua(L, value))
+	return 1
+}
+
+// luaHash implements crypto.hash(algorithm, data) -> hex digest
+func luaHash(L *lua.LState) int {
+	newHash := hashFunc(L, 1)
+	h := newHash()
+	h.Write([]byte(L.CheckString(2)))
+	L.Push(lua.LString(hex.EncodeToString(h.Sum(nil))))
+	return 1
+}
+
+// luaHMAC implements crypto.hmac(algorithm, key, data) -> hex signature
+func luaHMAC(L *lua.LState) int {
+	newHash := hashFunc(L, 1)
+	mac := hmac.New(newHash, []byte(L.CheckString(2)))
+	mac.Write([]byte(L.CheckString(3)))
+	L.Push(lua.LString(hex.EncodeToString(mac.Sum(nil))))
+	return 1
+}
+
+// hashFunc resolves the hash algorithm argument at position n
+func hashFunc(L *lua.LState, n int) func() hash.Hash {
+	switch strings.ToLower(L.CheckString(n)) {
+	case "sha256":
+		return sha256.New
+	case "sha512":
+		return sha512.New
+	case "sha1":
+		return sha1.New
+	case "md5":
+		return md5.New
+	default:
+		L.ArgError(n, "unsupported algorithm (sha256, sha512, sha1, md5)")
+		return nil
+	}
+}
+
+// luaFetch implements http.fetch(url, {method=, headers=, body=}) -> {status=, headers=, body=} | nil, err
+func (s *Stdlib) luaFetch(L *lua.LState) int {
+	rawURL := L.CheckString(1)
+	options := L.OptTable(2, L.NewTable())
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return pushError(L, fmt.Errorf("invalid url %q", rawURL))
+	}
+	if !s.hostAllowed(u.Hostname()) {
+		return pushError(L, fmt.Errorf("host %s is not allowed", u.Hostname()))
+	}
+
+	method := strings.ToUpper(lua.LVAsString(options.RawGetString("method")))
+	if method == "" {
+		method = http.MethodGet
+	}
+	if !containsFold(s.config.HTTP.AllowedMethods, method) {
+		return pushError(L, fmt.Errorf("method %s is not allowed", method))
+	}
+
+	ctx := L.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.config.HTTP.Timeout)
+	defer cancel()
+
+	var body io.Reader
+	if b, ok := options.RawGetString("body").(lua.LString); ok {
+		body = strings.NewReader(string(b))
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return pushError(L, err)
+	}
+	if headers, ok := options.RawGetString("headers").(*lua.LTable); ok {
+		headers.ForEach(func(k, v lua.LValue) {
+			req.Header.Set(k.String(), v.String())
+		})
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return pushError(L, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.config.HTTP.MaxResponseBytes+1))
+	if err != nil {
+		return pushError(L, err)
+	}
+	if int64(len(data)) > s.config.HTTP.MaxResponseBytes {
+		return pushError(L, fmt.Errorf("response exceeds %d bytes", s.config.HTTP.MaxResponseBytes))
+	}
+
+	headers := L.NewTable()
+	for name := range resp.Header {
+		headers.RawSetString(strings.ToLower(name), lua.LString(resp.Header.Get(name)))
+	}
+	result := L.NewTable()
+	result.RawSetString("status", lua.LNumber(resp.StatusCode))
+	result.RawSetString("headers", headers)
+	result.RawSetString("body", lua.LString(data))
+	L.Push(result)
+	return 1
+}
+
+// hostAllowed matches a host against the allow-list ("*.example.com" matches subdomains)
+func (s *Stdlib) hostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range s.config.HTTP.AllowedHosts {
+		allowed = strings.ToLower(allowed)
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// luaKVGet implements kv.get(key) -> value | nil (nil, err on failure)
+func (s *Stdlib) luaKVGet(prefix string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		value, ok, err := s.config.KV.Get(luaContext(L), prefix+L.CheckString(1))
+		if err != nil {
+			return pushError(L, err)
+		}
+		if !ok {
+			L.Push(lua.LNil)
+			return 1
+		}
+		L.Push(lua.LString(value))
+		return 1
+	}
+}
+
+// luaKVSet implements kv.set(key, value[, ttl_seconds]) -> true | nil, err
+func (s *Stdlib) luaKVSet(prefix string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		ttl := time.Duration(L.OptNumber(3, 0) * lua.LNumber(time.Second))
+		if err := s.config.KV.Set(luaContext(L), prefix+L.CheckString(1), L.CheckString(2), ttl); err != nil {
+			return pushError(L, err)
+		}
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// luaKVDelete implements kv.delete(key) -> true | nil, err
+func (s *Stdlib) luaKVDelete(prefix string) lua.LGFunction {
+	return func(L *lua.LState) int {
+		if err := s.config.KV.Delete(luaContext(L), prefix+L.CheckString(1)); err != nil {
+			return pushError(L, err)
+		}
+		L.Push(lua.LTrue)
+		return 1
+	}
+}
+
+// luaContext returns the execution context of L (background if none is set)
+func luaContext(L *lua.LState) context.Context {
+	if ctx := L.Context(); ctx != nil {
+		return ctx
+	}
+	return context.Background()
+}
+
+// pushError pushes the Lua error convention (nil, message)
+func pushError(L *lua.LState, err error) int {
+	L.Push(lua.LNil)
+	L.Push(lua.LString(err.Error()))
+	return 2
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryKVStore is an in-process KVStore with optional expiry
+type MemoryKVStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryKVEntry
+}
+
+type memoryKVEntry struct {
+	value     string
+	expiresAt time.Time // Zero = no expiry
+}
+
+// NewMemoryKVStore creates an empty in-memory key-value store
+func NewMemoryKVStore() *MemoryKVStore {
+	return &MemoryKVStore{entries: make(map[string]memoryKVEntry)}
+}
+
+// Get returns the value of key if it exists and has not expired
+func (s *MemoryKVStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok {
+		return "", false, nil
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return "", false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores value under key; ttl <= 0 keeps it until deleted
+func (s *MemoryKVStore) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry := memoryKVEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	s.entries[key] = entry
+	return nil
+}
+
+// Delete removes key
+func (s *MemoryKVStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
@@ -0,0 +1,78 @@
+package lua
+
+import (
+	"context"
+	"sync"
+)
+
+// ExecuteStream executes scripts paired with payloads (one of each per run) through the
+// worker pool and emits results as they complete, in completion order.
+// The stream ends when either input channel is closed or ctx is done; the returned
+// channel is closed once all in-flight executions have finished.
+// Results of executions still running when ctx is done may be dropped if nobody is reading.
+func (e *Executor) ExecuteStream(ctx context.Context, scripts <-chan Script, payloads <-chan map[string]interface{}) <-chan ExecutionResult {
+	pool := e.config.WorkerPool
+	if pool == nil {
+		pool = NewWorkerPool(0)
+	}
+
+	results := make(chan ExecutionResult, pool.Size())
+
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		for {
+			script, payload, ok := receiveInput(ctx, scripts, payloads)
+			if !ok {
+				return
+			}
+
+			if err := pool.AcquireContext(ctx); err != nil {
+				return
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer pool.Release()
+
+				result := e.Execute(ctx, script, payload)
+				select {
+				case results <- result:
+				case <-ctx.Done():
+				}
+			}()
+		}
+	}()
+
+	return results
+}
+
+// receiveInput reads the next script and its payload, reporting false when
+// either channel is closed or the context is done
+func receiveInput(ctx context.Context, scripts <-chan Script, payloads <-chan map[string]interface{}) (Script, map[string]interface{}, bool) {
+	var script Script
+	select {
+	case s, ok := <-scripts:
+		if !ok {
+			return nil, nil, false
+		}
+		script = s
+	case <-ctx.Done():
+		return nil, nil, false
+	}
+
+	select {
+	case payload, ok := <-payloads:
+		if !ok {
+			return nil, nil, false
+		}
+		return script, payload, true
+	case <-ctx.Done():
+		return nil, nil, false
+	}
+}
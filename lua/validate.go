@@ -0,0 +1,200 @@
+package lua
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+// Script issue codes reported by Validate
+const (
+	IssueSyntax         = "syntax"
+	IssueMissingHandle  = "missing_handle"
+	IssueHandleArity    = "handle_arity"
+	IssueDisabledGlobal = "disabled_global"
+)
+
+// ScriptIssue is a problem found by Validate. Line and Column are 1-based; 0 means unknown.
+type ScriptIssue struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+}
+
+// Error implements the error interface
+func (i ScriptIssue) Error() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	}
+	return i.Message
+}
+
+// ScriptIssues represents multiple script issues
+type ScriptIssues []ScriptIssue
+
+// Error implements the error interface
+func (si ScriptIssues) Error() string {
+	if len(si) == 0 {
+		return "no script issues"
+	}
+
+	messages := make([]string, 0, len(si))
+	for _, issue := range si {
+		messages = append(messages, issue.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// HasErrors checks if there are any script issues
+func (si ScriptIssues) HasErrors() bool {
+	return len(si) > 0
+}
+
+// Validate checks a Lua script without running it: the code must compile, define a global handle
+// function taking at most one parameter (the payload), and not read globals the engine's
+// sandbox disables (e.g. os, io, load). The global check is static and best-effort: dynamic
+// lookups such as _G["lo".."ad"] are not detected.
+func (e *LuaEngine) Validate(script Script) ScriptIssues {
+	proto, err := CompileString(script.GetCode(), script.GetName())
+	if err != nil {
+		issue := ScriptIssue{Code: IssueSyntax, Message: err.Error()}
+		var parseErr *parse.Error
+		if errors.As(err, &parseErr) {
+			switch {
+			case parseErr.Pos.Line == parse.EOF:
+				issue.Message = parseErr.Message + " at end of script"
+			case parseErr.Token != "":
+				issue.Message = fmt.Sprintf("%s near '%s'", parseErr.Message, parseErr.Token)
+				issue.Line, issue.Column = parseErr.Pos.Line, parseErr.Pos.Column
+			default:
+				issue.Message = parseErr.Message
+				issue.Line, issue.Column = parseErr.Pos.Line, parseErr.Pos.Column
+			}
+		}
+		return ScriptIssues{issue}
+	}
+
+	var issues ScriptIssues
+	issues = append(issues, checkHandle(proto)...)
+	issues = append(issues, checkDisabledGlobals(proto, disabledGlobals(e.sandboxConfig()))...)
+	return issues
+}
+
+// checkHandle finds the top-level assignment of handle and checks the function's arity
+func checkHandle(proto *lua.FunctionProto) ScriptIssues {
+	for pc, inst := range proto.Code {
+		if opcode(inst) != lua.OP_SETGLOBAL || constantName(proto, argBx(inst)) != "handle" {
+			continue
+		}
+
+		// function handle(...) compiles to a closure immediately stored in the global
+		if pc == 0 || opcode(proto.Code[pc-1]) != lua.OP_CLOSURE || argA(proto.Code[pc-1]) != argA(inst) {
+			return nil
+		}
+		handle := proto.FunctionPrototypes[argBx(proto.Code[pc-1])]
+		if handle.NumParameters > 1 {
+			return ScriptIssues{{
+				Code:    IssueHandleArity,
+				Message: fmt.Sprintf("handle must take one parameter (payload), it takes %d", handle.NumParameters),
+				Line:    handle.LineDefined,
+			}}
+		}
+		return nil
+	}
+
+	return ScriptIssues{{Code: IssueMissingHandle, Message: "script missing handle function"}}
+}
+
+// disabledGlobals returns the globals the sandbox removes or never opens, with the reason
+func disabledGlobals(config SandboxConfig) map[string]string {
+	disabled := make(map[string]string)
+	for name, off := range map[string]bool{
+		"dofile":     config.DisableDofile,
+		"loadfile":   config.DisableLoadfile,
+		"load":       config.DisableLoad,
+		"loadstring": config.DisableLoadstring,
+	} {
+		if off {
+			disabled[name] = "is disabled in the sandbox"
+		}
+	}
+	for name, on := range map[string]bool{
+		"table":  config.EnableTable,
+		"string": config.EnableString,
+		"math":   config.EnableMath,
+		"os":     config.EnableOS,
+		"io":     config.EnableIO,
+		"debug":  config.EnableDebug,
+	} {
+		if !on {
+			disabled[name] = "library is not enabled in the sandbox"
+		}
+	}
+	return disabled
+}
+
+// checkDisabledGlobals reports reads of disabled globals the script doesn't define itself
+func checkDisabledGlobals(proto *lua.FunctionProto, disabled map[string]string) ScriptIssues {
+	defined := make(map[string]bool)
+	walkProtos(proto, func(p *lua.FunctionProto, pc int, inst uint32) {
+		if opcode(inst) == lua.OP_SETGLOBAL {
+			defined[constantName(p, argBx(inst))] = true
+		}
+	})
+
+	var issues ScriptIssues
+	seen := make(map[ScriptIssue]bool)
+	walkProtos(proto, func(p *lua.FunctionProto, pc int, inst uint32) {
+		if opcode(inst) != lua.OP_GETGLOBAL {
+			return
+		}
+		name := constantName(p, argBx(inst))
+		reason, ok := disabled[name]
+		if !ok || defined[name] {
+			return
+		}
+
+		issue := ScriptIssue{Code: IssueDisabledGlobal, Message: fmt.Sprintf("%s %s", name, reason)}
+		if pc < len(p.DbgSourcePositions) {
+			issue.Line = p.DbgSourcePositions[pc]
+		}
+		if !seen[issue] {
+			seen[issue] = true
+			issues = append(issues, issue)
+		}
+	})
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Line < issues[j].Line })
+	return issues
+}
+
+// walkProtos calls fn for every instruction of proto and its nested functions
+func walkProtos(proto *lua.FunctionProto, fn func(p *lua.FunctionProto, pc int, inst uint32)) {
+	for pc, inst := range proto.Code {
+		fn(proto, pc, inst)
+	}
+	for _, nested := range proto.FunctionPrototypes {
+		walkProtos(nested, fn)
+	}
+}
+
+// constantName returns the string constant at index, or "" if it isn't a string
+func constantName(proto *lua.FunctionProto, index int) string {
+	if index >= len(proto.Constants) {
+		return ""
+	}
+	if s, ok := proto.Constants[index].(lua.LString); ok {
+		return string(s)
+	}
+	return ""
+}
+
+// Instruction decoding, following gopher-lua's layout: opcode (6 bits) | A (8) | Bx (18)
+func opcode(inst uint32) int { return int(inst >> 26) }
+func argA(inst uint32) int   { return int(inst>>18) & 0xff }
+func argBx(inst uint32) int  { return int(inst & 0x3ffff) }
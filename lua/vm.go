@@ -23,6 +23,13 @@ type SandboxConfig struct {
 	DisableLoadfile   bool // Disable loadfile() - default: true
 	DisableLoad       bool // Disable load() - default: true
 	DisableLoadstring bool // Disable loadstring() - default: true
+
+	// Resource limits (0 = gopher-lua default / unlimited). Heap allocations are not
+	// tracked directly; MaxInstructions bounds how much a script can allocate in loops.
+	CallStackSize   int   // Maximum call depth (deep recursion) - default: lua.CallStackSize
+	RegistrySize    int   // Initial data stack size - default: lua.RegistrySize
+	RegistryMaxSize int   // Maximum data stack size the registry may grow to - default: no growth
+	MaxInstructions int64 // VM instructions per execution before aborting - default: unlimited
 }
 
 // DefaultSandboxConfig returns a default sandbox configuration with strict security.
@@ -55,7 +62,10 @@ func NewSandboxedVM() *lua.LState {
 // If config is nil, DefaultSandboxConfig() is used.
 func NewVM(config SandboxConfig) *lua.LState {
 	L := lua.NewState(lua.Options{
-		SkipOpenLibs: true, // Don't load any libs by default
+		SkipOpenLibs:    true, // Don't load any libs by default
+		CallStackSize:   config.CallStackSize,
+		RegistrySize:    config.RegistrySize,
+		RegistryMaxSize: config.RegistryMaxSize,
 	})
 
 	// Open libraries based on configuration
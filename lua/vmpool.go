@@ -0,0 +1,77 @@
+package lua
+
+import (
+	lua "github.com/yuin/gopher-lua"
+)
+
+// VMPool reuses sandboxed Lua VMs across executions to avoid creating a VM and opening its
+// libraries every time. Each execution runs with its own global environment, so globals set
+// by one script are not visible to the next; library tables (string, math, ...) are shared.
+type VMPool struct {
+	config SandboxConfig
+	vms    chan *lua.LState
+}
+
+// NewVMPool creates a pool keeping up to size idle VMs built with config.
+// If size <= 0, it defaults to 10.
+func NewVMPool(config SandboxConfig, size int) *VMPool {
+	if size <= 0 {
+		size = 10
+	}
+
+	return &VMPool{
+		config: config,
+		vms:    make(chan *lua.LState, size),
+	}
+}
+
+// Get returns an idle VM or creates a new one
+func (p *VMPool) Get() *lua.LState {
+	select {
+	case L := <-p.vms:
+		return L
+	default:
+		return NewVM(p.config)
+	}
+}
+
+// Put resets a VM and returns it to the pool, closing it if the pool is full
+func (p *VMPool) Put(L *lua.LState) {
+	L.RemoveContext()
+	L.SetTop(0)
+
+	select {
+	case p.vms <- L:
+	default:
+		L.Close()
+	}
+}
+
+// Close closes all idle VMs
+func (p *VMPool) Close() {
+	for {
+		select {
+		case L := <-p.vms:
+			L.Close()
+		default:
+			return
+		}
+	}
+}
+
+// newScriptEnv swaps in a fresh global table for one execution that falls back to the VM's
+// globals for reads, and returns a function restoring the original globals
+func newScriptEnv(L *lua.LState) (*lua.LTable, func()) {
+	globals := L.G.Global
+
+	env := L.NewTable()
+	meta := L.NewTable()
+	meta.RawSetString("__index", globals)
+	L.SetMetatable(env, meta)
+	env.RawSetString("_G", env)
+
+	L.G.Global = env
+	return env, func() {
+		L.G.Global = globals
+	}
+}
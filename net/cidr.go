@@ -0,0 +1,144 @@
+package netx
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+)
+
+// cloudMetadataIPs are the instance metadata endpoints of cloud providers, common SSRF targets
+var cloudMetadataIPs = []net.IP{
+	net.ParseIP("169.254.169.254"), // AWS, GCP, Azure, DigitalOcean, Oracle, OpenStack
+	net.ParseIP("169.254.170.2"),   // AWS ECS task metadata
+	net.ParseIP("fd00:ec2::254"),   // AWS IPv6
+	net.ParseIP("100.100.100.200"), // Alibaba Cloud
+}
+
+// sharedAddressSpace is the carrier-grade NAT range (RFC 6598)
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0).To4(), Mask: net.CIDRMask(10, 32)}
+
+// ParseCIDRList parses CIDRs (e.g. from a comma-separated allow-list setting); bare IPs are
+// single-address ranges and empty entries are skipped
+func ParseCIDRList(cidrs []string) ([]*net.IPNet, error) {
+	ranges := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", cidr)
+			}
+			bits := 128
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+			ranges = append(ranges, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		ranges = append(ranges, ipNet)
+	}
+	return ranges, nil
+}
+
+// IPInRanges reports whether ip is in any of ranges (false for invalid IPs)
+func IPInRanges(ip string, ranges []*net.IPNet) bool {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return false
+	}
+	for _, r := range ranges {
+		if r.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPrivateIP reports whether ip is a private address (RFC 1918, RFC 4193)
+func IsPrivateIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.IsPrivate()
+}
+
+// IsLoopbackIP reports whether ip is a loopback address (127.0.0.0/8, ::1)
+func IsLoopbackIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.IsLoopback()
+}
+
+// IsCloudMetadataIP reports whether ip is a cloud provider's instance metadata endpoint
+func IsCloudMetadataIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, metadata := range cloudMetadataIPs {
+		if metadata.Equal(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address: not private, loopback,
+// link-local (which includes most metadata endpoints), carrier-grade NAT, unspecified,
+// multicast or a cloud metadata endpoint. Use it to reject internal targets of user-supplied
+// URLs (SSRF) after resolving their host.
+func IsPublicIP(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || !parsed.IsGlobalUnicast() {
+		return false
+	}
+	return !parsed.IsPrivate() &&
+		!sharedAddressSpace.Contains(parsed) &&
+		!IsCloudMetadataIP(ip)
+}
+
+// IPv4ToInt converts an IPv4 address to its integer value
+func IPv4ToInt(ip string) (uint32, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.To4() == nil {
+		return 0, fmt.Errorf("invalid IPv4 address %q", ip)
+	}
+	return binary.BigEndian.Uint32(parsed.To4()), nil
+}
+
+// IntToIPv4 converts an integer to an IPv4 address
+func IntToIPv4(n uint32) string {
+	ip := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(ip, n)
+	return ip.String()
+}
+
+// IPToBigInt converts an IPv4 or IPv6 address to its integer value (IPv4 as a 32-bit value)
+func IPToBigInt(ip string) (*big.Int, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return nil, fmt.Errorf("invalid IP address %q", ip)
+	}
+	if ip4 := parsed.To4(); ip4 != nil {
+		parsed = ip4
+	}
+	return new(big.Int).SetBytes(parsed), nil
+}
+
+// BigIntToIP converts an integer to an IPv6 address, or an IPv4 address if ipv4 is set
+func BigIntToIP(n *big.Int, ipv4 bool) (string, error) {
+	size := net.IPv6len
+	if ipv4 {
+		size = net.IPv4len
+	}
+	if n.Sign() < 0 || n.BitLen() > size*8 {
+		return "", fmt.Errorf("integer %s out of range", n)
+	}
+	return net.IP(n.FillBytes(make([]byte, size))).String(), nil
+}
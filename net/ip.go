@@ -1,27 +1,155 @@
 package netx
 
 import (
+	"net"
+	"net/http"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+// Client IP headers understood by IPResolver
+const (
+	HeaderCFConnectingIP = "CF-Connecting-IP" // Cloudflare
+	HeaderTrueClientIP   = "True-Client-IP"   // Akamai, Cloudflare Enterprise
+	HeaderXForwardedFor  = "X-Forwarded-For"  // First (client) address of the list
+	HeaderXRealIP        = "X-Real-IP"        // nginx
+	HeaderForwarded      = "Forwarded"        // RFC 7239, first element's for= parameter
+)
+
+// IPResolver determines the client IP from proxy headers checked in order of precedence,
+// falling back to the connection's remote address. Only list headers set by proxies you
+// trust: clients can send any of them.
+type IPResolver struct {
+	Headers []string
+}
+
+// NewIPResolver creates a resolver checking headers in the given order
+func NewIPResolver(headers ...string) *IPResolver {
+	return &IPResolver{Headers: headers}
+}
+
+// DefaultIPResolver checks CF-Connecting-IP, X-Forwarded-For and X-Real-IP
+func DefaultIPResolver() *IPResolver {
+	return NewIPResolver(HeaderCFConnectingIP, HeaderXForwardedFor, HeaderXRealIP)
+}
+
+// GetUserIP returns the client IP of a Fiber request (CF, X-Forwarded-For, X-Real-IP)
 func GetUserIP(c *fiber.Ctx) string {
-	if ip := c.Get("CF-Connecting-IP"); ip != "" {
-		return ip
+	return GetUserIPWith(c, nil)
+}
+
+// GetUserIPWith returns the client IP of a Fiber request using resolver (nil = DefaultIPResolver)
+func GetUserIPWith(c *fiber.Ctx, resolver *IPResolver) string {
+	if resolver == nil {
+		resolver = DefaultIPResolver()
 	}
-	if ip := c.Get("X-Forwarded-For"); ip != "" {
-		if idx := strings.Index(ip, ","); idx != -1 {
-			return strings.TrimSpace(ip[:idx])
+	return resolver.resolve(func(key string) string { return c.Get(key) }, c.IP())
+}
+
+// GetUserIPFromRequest returns the client IP of a net/http request using resolver
+// (nil = DefaultIPResolver)
+func GetUserIPFromRequest(r *http.Request, resolver *IPResolver) string {
+	if resolver == nil {
+		resolver = DefaultIPResolver()
+	}
+	return resolver.resolve(r.Header.Get, r.RemoteAddr)
+}
+
+// resolve returns the IP of the first header set, or of remoteAddr
+func (r *IPResolver) resolve(get func(string) string, remoteAddr string) string {
+	for _, header := range r.Headers {
+		value := get(header)
+		if value == "" {
+			continue
+		}
+
+		switch http.CanonicalHeaderKey(header) {
+		case HeaderXForwardedFor:
+			if idx := strings.Index(value, ","); idx != -1 {
+				value = value[:idx]
+			}
+		case HeaderForwarded:
+			// Skip obfuscated identifiers such as "unknown" or "_hidden"
+			elements := ParseForwarded(value)
+			if len(elements) == 0 || net.ParseIP(stripPort(elements[0].For)) == nil {
+				continue
+			}
+			value = elements[0].For
+		}
+		if ip := stripPort(strings.TrimSpace(value)); ip != "" {
+			return ip
 		}
-		return strings.TrimSpace(ip)
 	}
-	if ip := c.Get("X-Real-IP"); ip != "" {
-		return strings.TrimSpace(ip)
+	return stripPort(strings.TrimSpace(remoteAddr))
+}
+
+// stripPort removes the port and IPv6 brackets of an address ("[::1]:80" -> "::1")
+func stripPort(addr string) string {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(addr, "["), "]")
+}
+
+// ForwardedElement is one proxy hop of a Forwarded header
+type ForwardedElement struct {
+	For   string // Client address, possibly with port (e.g. "[2001:db8::17]:4711"), or an obfuscated identifier
+	By    string
+	Host  string
+	Proto string
+}
+
+// ParseForwarded parses a Forwarded header (RFC 7239), e.g.
+// `for=192.0.2.60;proto=http;by=203.0.113.43, for="[2001:db8:cafe::17]:4711"`.
+// Elements are in hop order, the first one added by the proxy nearest to the client.
+func ParseForwarded(value string) []ForwardedElement {
+	var elements []ForwardedElement
+	for _, part := range splitQuoted(value, ',') {
+		var element ForwardedElement
+		for _, pair := range splitQuoted(part, ';') {
+			key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			val = strings.TrimSpace(val)
+			if len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"' {
+				val = strings.ReplaceAll(val[1:len(val)-1], `\"`, `"`)
+			}
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "for":
+				element.For = val
+			case "by":
+				element.By = val
+			case "host":
+				element.Host = val
+			case "proto":
+				element.Proto = val
+			}
+		}
+		elements = append(elements, element)
 	}
-	ip := c.IP()
-	if strings.Contains(ip, ":") {
-		ip = strings.Split(ip, ":")[0]
+	return elements
+}
+
+// splitQuoted splits s at sep outside of quoted strings
+func splitQuoted(s string, sep byte) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
 	}
-	return strings.TrimSpace(ip)
+	return append(parts, s[start:])
 }
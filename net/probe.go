@@ -0,0 +1,58 @@
+package netx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// IsPortOpen reports whether a TCP connection to host:port succeeds within timeout
+func IsPortOpen(host string, port int, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// WaitForTCP waits until a TCP connection to addr (host:port) succeeds, retrying with
+// exponential backoff (250ms up to 5s) until ctx is done - e.g. to wait for RabbitMQ before
+// creating consumers. Bound the wait with a context deadline.
+func WaitForTCP(ctx context.Context, addr string) error {
+	var dialer net.Dialer
+	delay := 250 * time.Millisecond
+	for {
+		attemptCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		conn, err := dialer.DialContext(attemptCtx, "tcp", addr)
+		cancel()
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for %s: %w (last error: %v)", addr, ctx.Err(), err)
+		case <-timer.C:
+		}
+		if delay *= 2; delay > 5*time.Second {
+			delay = 5 * time.Second
+		}
+	}
+}
+
+// FreePort returns a TCP port that is currently free on localhost, e.g. for test servers.
+// Another process may take it before it is used.
+func FreePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("failed to find a free port: %w", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,184 @@
+package netx
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// URLBuilder builds URLs with properly escaped path segments and query parameters instead
+// of string concatenation:
+//
+//	u, err := netx.NewURLBuilder("https://api.example.com/v1").
+//		Path("users", userID, "orders").
+//		Query("status", "open").
+//		Build()
+type URLBuilder struct {
+	url   *url.URL
+	query url.Values
+	err   error
+}
+
+// NewURLBuilder starts a URL from base (may be empty); parse errors are returned by Build
+func NewURLBuilder(base string) *URLBuilder {
+	u, err := url.Parse(base)
+	if err != nil {
+		return &URLBuilder{url: &url.URL{}, query: url.Values{}, err: fmt.Errorf("invalid base URL: %w", err)}
+	}
+	return &URLBuilder{url: u, query: u.Query()}
+}
+
+// Scheme sets the scheme (e.g. "https")
+func (b *URLBuilder) Scheme(scheme string) *URLBuilder {
+	b.url.Scheme = scheme
+	return b
+}
+
+// Host sets the host, optionally with a port
+func (b *URLBuilder) Host(host string) *URLBuilder {
+	b.url.Host = host
+	return b
+}
+
+// Path appends path segments, escaping each one ("a/b" stays a single segment)
+func (b *URLBuilder) Path(segments ...string) *URLBuilder {
+	raw := strings.TrimSuffix(b.url.EscapedPath(), "/")
+	for _, segment := range segments {
+		raw += "/" + url.PathEscape(segment)
+	}
+	b.setRawPath(raw)
+	return b
+}
+
+// RawPath appends an already escaped path (e.g. "/v1/users"), keeping its slashes
+func (b *URLBuilder) RawPath(p string) *URLBuilder {
+	if p == "" {
+		return b
+	}
+	b.setRawPath(strings.TrimSuffix(b.url.EscapedPath(), "/") + "/" + strings.TrimPrefix(p, "/"))
+	return b
+}
+
+// setRawPath sets the escaped path of the URL
+func (b *URLBuilder) setRawPath(raw string) {
+	decoded, err := url.PathUnescape(raw)
+	if err != nil {
+		if b.err == nil {
+			b.err = fmt.Errorf("invalid path %q: %w", raw, err)
+		}
+		return
+	}
+	b.url.Path, b.url.RawPath = decoded, raw
+}
+
+// Query adds a query parameter value
+func (b *URLBuilder) Query(key, value string) *URLBuilder {
+	b.query.Add(key, value)
+	return b
+}
+
+// SetQuery sets a query parameter, replacing its values
+func (b *URLBuilder) SetQuery(key, value string) *URLBuilder {
+	b.query.Set(key, value)
+	return b
+}
+
+// QueryValues adds all values of params
+func (b *URLBuilder) QueryValues(params url.Values) *URLBuilder {
+	for key, values := range params {
+		for _, value := range values {
+			b.query.Add(key, value)
+		}
+	}
+	return b
+}
+
+// Fragment sets the fragment (without "#")
+func (b *URLBuilder) Fragment(fragment string) *URLBuilder {
+	b.url.Fragment = fragment
+	return b
+}
+
+// URL returns the built URL
+func (b *URLBuilder) URL() (*url.URL, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	u := *b.url
+	u.RawQuery = b.query.Encode()
+	return &u, nil
+}
+
+// Build returns the built URL as a string; query parameters are sorted by key
+func (b *URLBuilder) Build() (string, error) {
+	u, err := b.URL()
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// String returns the built URL, or "" if it is invalid
+func (b *URLBuilder) String() string {
+	s, _ := b.Build()
+	return s
+}
+
+// MergeQuery sets params on the query of rawURL, replacing existing values of the same keys
+// and keeping the other parameters
+func MergeQuery(rawURL string, params url.Values) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = append([]string(nil), values...)
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// NormalizeURL returns a canonical form of rawURL for comparison and cache keys: lowercase
+// scheme and host, no default port (80 for http, 443 for https), resolved "." and ".."
+// segments, "/" for an empty path, sorted query parameters and no fragment
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid URL: %w", err)
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	if host, port, err := net.SplitHostPort(u.Host); err == nil {
+		if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+			u.Host = host
+			if strings.Contains(host, ":") {
+				u.Host = "[" + host + "]"
+			}
+		}
+	}
+
+	if u.Path == "" {
+		if u.Host != "" {
+			u.Path, u.RawPath = "/", ""
+		}
+	} else {
+		raw := u.EscapedPath()
+		cleaned := path.Clean(raw)
+		if strings.HasSuffix(raw, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		if decoded, err := url.PathUnescape(cleaned); err == nil {
+			u.Path, u.RawPath = decoded, cleaned
+		}
+	}
+
+	if u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+	u.Fragment, u.RawFragment = "", ""
+	return u.String(), nil
+}
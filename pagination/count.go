@@ -0,0 +1,177 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/collections"
+	"github.com/kerimovok/go-pkg-utils/httpx"
+	"gorm.io/gorm"
+)
+
+// CountStrategy selects how Query determines the total number of items
+type CountStrategy int
+
+const (
+	// CountExact runs SELECT COUNT(*) on every request (default)
+	CountExact CountStrategy = iota
+	// CountSkip runs no count: hasNext is detected by fetching one extra row, and the total
+	// and total pages are reported as -1 (unknown)
+	CountSkip
+	// CountEstimate uses the planner's row estimate (Postgres EXPLAIN), running an exact count
+	// only when the estimate is below QueryOptions.EstimateThreshold
+	CountEstimate
+	// CountCached runs an exact count and caches it in QueryOptions.Cache for CacheTTL
+	CountCached
+)
+
+// QueryOptions configures a paginated query
+type QueryOptions struct {
+	Count CountStrategy
+	// EstimateThreshold is the estimate below which CountEstimate counts exactly - default: 10000
+	EstimateThreshold int64
+	// Cache stores totals for CountCached - defaults to a shared MemoryCountCache of
+	// DefaultCountCacheSize totals
+	Cache CountCache
+	// CacheKey identifies the total in the cache - defaults to the count query's SQL
+	CacheKey string
+	// CacheTTL is how long cached totals are used - default: 1 minute
+	CacheTTL time.Duration
+}
+
+// CountCache stores query totals (e.g. in Redis) for CountCached
+type CountCache interface {
+	Get(ctx context.Context, key string) (int64, bool)
+	Set(ctx context.Context, key string, total int64, ttl time.Duration)
+}
+
+// DefaultCountCacheSize is the number of totals kept by the default MemoryCountCache
+const DefaultCountCacheSize = 1000
+
+// MemoryCountCache is an in-process CountCache that keeps at most a fixed number
+// of totals, evicting the least recently used ones
+type MemoryCountCache struct {
+	entries *collections.LRU[string, int64]
+}
+
+// NewMemoryCountCache creates an empty in-process count cache holding at most
+// size totals (size <= 0 = DefaultCountCacheSize)
+func NewMemoryCountCache(size int) *MemoryCountCache {
+	if size <= 0 {
+		size = DefaultCountCacheSize
+	}
+	return &MemoryCountCache{entries: collections.NewLRU[string, int64](size)}
+}
+
+// Get returns an unexpired cached total
+func (c *MemoryCountCache) Get(ctx context.Context, key string) (int64, bool) {
+	return c.entries.Get(key)
+}
+
+// Set caches a total for ttl
+func (c *MemoryCountCache) Set(ctx context.Context, key string, total int64, ttl time.Duration) {
+	c.entries.SetWithTTL(key, total, ttl)
+}
+
+// defaultCountCache backs CountCached when no cache is configured
+var defaultCountCache = NewMemoryCountCache(DefaultCountCacheSize)
+
+// countTotal returns the total number of items of query according to the count strategy
+func countTotal(ctx context.Context, query *gorm.DB, options QueryOptions) (int64, error) {
+	countQuery := query.Session(&gorm.Session{}).WithContext(ctx)
+
+	switch options.Count {
+	case CountEstimate:
+		threshold := options.EstimateThreshold
+		if threshold <= 0 {
+			threshold = 10000
+		}
+		estimate, err := estimateCount(countQuery)
+		if err != nil {
+			return 0, err
+		}
+		if estimate >= threshold {
+			return estimate, nil
+		}
+
+	case CountCached:
+		cache := options.Cache
+		if cache == nil {
+			cache = defaultCountCache
+		}
+		key := options.CacheKey
+		if key == "" {
+			key = countQuery.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var total int64
+				return tx.Count(&total)
+			})
+		}
+		if total, ok := cache.Get(ctx, key); ok {
+			return total, nil
+		}
+
+		var total int64
+		if err := countQuery.Count(&total).Error; err != nil {
+			return 0, err
+		}
+		ttl := options.CacheTTL
+		if ttl <= 0 {
+			ttl = time.Minute
+		}
+		cache.Set(ctx, key, total, ttl)
+		return total, nil
+	}
+
+	var total int64
+	if err := countQuery.Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+// estimateCount returns the Postgres planner's row estimate for the query
+func estimateCount(query *gorm.DB) (int64, error) {
+	var rows []map[string]interface{}
+	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&rows).Statement
+
+	var plan string
+	if err := query.Session(&gorm.Session{NewDB: true}).
+		Raw("EXPLAIN (FORMAT JSON) "+stmt.SQL.String(), stmt.Vars...).
+		Row().Scan(&plan); err != nil {
+		return 0, fmt.Errorf("failed to estimate count: %w", err)
+	}
+
+	var explain []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal([]byte(plan), &explain); err != nil {
+		return 0, fmt.Errorf("failed to parse query plan: %w", err)
+	}
+	if len(explain) == 0 {
+		return 0, fmt.Errorf("failed to parse query plan: empty plan")
+	}
+	return int64(explain[0].Plan.Rows), nil
+}
+
+// paginationWithoutTotal creates pagination metadata when the total is unknown
+func paginationWithoutTotal(page, perPage int, hasNext bool) *httpx.Pagination {
+	pagination := httpx.NewPagination(page, perPage, 0)
+	pagination.Total = -1
+	pagination.TotalPages = -1
+	setHasNext(pagination, hasNext)
+	return pagination
+}
+
+// setHasNext sets whether there is a next page, detected from an extra fetched row
+func setHasNext(pagination *httpx.Pagination, hasNext bool) {
+	pagination.HasNext = hasNext
+	pagination.NextPage = nil
+	if hasNext {
+		nextPage := pagination.Page + 1
+		pagination.NextPage = &nextPage
+	}
+}
@@ -0,0 +1,318 @@
+package pagination
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/kerimovok/go-pkg-utils/hmac"
+	"github.com/kerimovok/go-pkg-utils/httpx"
+	"github.com/kerimovok/go-pkg-utils/validator"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidCursor is returned for cursors that are malformed, forged or from another ordering
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorColumn is a column of a keyset ordering
+type CursorColumn struct {
+	Column string // Column in the query (e.g. "created_at" or "users.id"); its last segment names the model field
+	Desc   bool
+}
+
+// CursorParams represents cursor pagination query parameters
+type CursorParams struct {
+	Cursor  string `query:"cursor"`                    // Opaque cursor of the previous page's last item
+	PerPage int    `query:"per_page" validate:"min=1"` // Items per page, capped by Defaults.MaxPerPage
+}
+
+// CursorPagination contains cursor pagination metadata
+type CursorPagination struct {
+	PerPage    int    `json:"perPage"`
+	HasNext    bool   `json:"hasNext"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// CursorResponse represents a cursor-paginated API response
+type CursorResponse struct {
+	httpx.Response
+	Pagination *CursorPagination `json:"pagination,omitempty"`
+}
+
+// Cursor paginates a model by keyset: each page continues after the last item of the previous
+// one (WHERE (a, b) > (?, ?)) instead of skipping rows with OFFSET, so pages stay fast on large
+// tables and don't shift with concurrent inserts. The columns must be non-null and, together,
+// unique - end with a unique column such as the primary key. Cursors are opaque and signed
+// with Secret (if set) so clients cannot forge them.
+type Cursor[T any] struct {
+	Columns []CursorColumn
+	Secret  string
+
+	schemas *sync.Map
+}
+
+// NewCursor creates a keyset paginator ordering by columns
+func NewCursor[T any](secret string, columns ...CursorColumn) *Cursor[T] {
+	return &Cursor[T]{Columns: columns, Secret: secret, schemas: &sync.Map{}}
+}
+
+// ParseCursorParams parses and validates cursor pagination parameters from Fiber context
+func ParseCursorParams(c *fiber.Ctx, defaults Defaults) (*CursorParams, error) {
+	var params CursorParams
+	if err := c.QueryParser(&params); err != nil {
+		return nil, err
+	}
+
+	if params.PerPage <= 0 {
+		params.PerPage = defaults.PerPage
+	}
+
+	if err := validator.ValidateStruct(&params); err != nil {
+		return nil, err
+	}
+	if err := checkPerPage(params.PerPage, defaults); err != nil {
+		return nil, err
+	}
+
+	return &params, nil
+}
+
+// Query returns the page of query after the cursor (the first page if empty)
+func (cur *Cursor[T]) Query(
+	ctx context.Context,
+	query *gorm.DB,
+	params *CursorParams,
+	message string,
+) (*CursorResponse, error) {
+	if len(cur.Columns) == 0 {
+		return nil, fmt.Errorf("cursor pagination requires at least one column")
+	}
+
+	fields, err := cur.fields(query)
+	if err != nil {
+		return nil, err
+	}
+
+	results, next, err := cur.page(ctx, query, fields, params.Cursor, params.PerPage)
+	if err != nil {
+		return nil, err
+	}
+	pagination := &CursorPagination{PerPage: params.PerPage, HasNext: next != "", NextCursor: next}
+
+	return &CursorResponse{
+		Response: httpx.Response{
+			Success:   true,
+			Message:   message,
+			Data:      results,
+			Status:    fiber.StatusOK,
+			Timestamp: time.Now().UTC(),
+		},
+		Pagination: pagination,
+	}, nil
+}
+
+// HandleRequest is a convenience function that handles the full cursor pagination flow
+func (cur *Cursor[T]) HandleRequest(
+	c *fiber.Ctx,
+	query *gorm.DB,
+	defaults Defaults,
+	message string,
+) error {
+	params, err := ParseCursorParams(c, defaults)
+	if err != nil {
+		response := httpx.BadRequest("Invalid query parameters", err)
+		return httpx.SendResponse(c, response)
+	}
+
+	// Create context with timeout from request context
+	ctx, cancel := context.WithTimeout(c.Context(), 30*time.Second)
+	defer cancel()
+
+	response, err := cur.Query(ctx, query, params, message)
+	if errors.Is(err, ErrInvalidCursor) {
+		return httpx.SendResponse(c, httpx.BadRequest("Invalid cursor", err))
+	}
+	if err != nil {
+		// Error logging should be handled by the caller or middleware
+		response := httpx.InternalServerError("Failed to retrieve data", err)
+		return httpx.SendResponse(c, response)
+	}
+
+	if response.Pagination.HasNext {
+		c.Set(fiber.HeaderLink, httpx.FormatLinks([]httpx.Link{{
+			URL: httpx.RequestURLWith(c, map[string]string{"cursor": response.Pagination.NextCursor}),
+			Rel: "next",
+		}}))
+	}
+	return httpx.SendEnvelope(c, response)
+}
+
+// page fetches up to limit rows after cursor and returns them with the cursor of the next
+// page (empty on the last page)
+func (cur *Cursor[T]) page(
+	ctx context.Context,
+	query *gorm.DB,
+	fields []*schema.Field,
+	cursor string,
+	limit int,
+) ([]T, string, error) {
+	query = query.WithContext(ctx)
+	if cursor != "" {
+		values, err := cur.decode(cursor, fields)
+		if err != nil {
+			return nil, "", err
+		}
+		where, args := cur.keysetCondition(values)
+		query = query.Where(where, args...)
+	}
+	for _, column := range cur.Columns {
+		query = query.Order(column.orderClause())
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	var results []T
+	if err := query.Limit(limit + 1).Find(&results).Error; err != nil {
+		return nil, "", err
+	}
+	if len(results) <= limit {
+		return results, "", nil
+	}
+
+	results = results[:limit]
+	next, err := cur.encode(ctx, results[len(results)-1], fields)
+	if err != nil {
+		return nil, "", err
+	}
+	return results, next, nil
+}
+
+// orderClause returns the ORDER BY clause of the column
+func (c CursorColumn) orderClause() string {
+	if c.Desc {
+		return c.Column + " DESC"
+	}
+	return c.Column + " ASC"
+}
+
+// fields looks up the model fields of the cursor columns
+func (cur *Cursor[T]) fields(query *gorm.DB) ([]*schema.Field, error) {
+	if cur.schemas == nil {
+		cur.schemas = &sync.Map{}
+	}
+	s, err := schema.Parse(new(T), cur.schemas, query.NamingStrategy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse model: %w", err)
+	}
+
+	fields := make([]*schema.Field, len(cur.Columns))
+	for i, column := range cur.Columns {
+		name := column.Column[strings.LastIndex(column.Column, ".")+1:]
+		if fields[i] = s.LookUpField(name); fields[i] == nil {
+			return nil, fmt.Errorf("cursor column %s is not a field of %s", column.Column, s.Name)
+		}
+	}
+	return fields, nil
+}
+
+// keysetCondition builds the condition selecting rows after values in the ordering:
+// (a > ?) OR (a = ? AND b > ?) OR ..., with < for descending columns
+func (cur *Cursor[T]) keysetCondition(values []interface{}) (string, []interface{}) {
+	var clauses []string
+	var args []interface{}
+	for i, column := range cur.Columns {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, cur.Columns[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+		op := " > ?"
+		if column.Desc {
+			op = " < ?"
+		}
+		parts = append(parts, column.Column+op)
+		args = append(args, values[i])
+		clauses = append(clauses, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")", args
+}
+
+// cursorPayload is the encoded content of a cursor
+type cursorPayload struct {
+	Ordering string            `json:"o"` // Fingerprint of the columns and directions
+	Values   []json.RawMessage `json:"v"`
+}
+
+// ordering fingerprints the columns and directions, so a cursor cannot be replayed
+// against another ordering of the same model
+func (cur *Cursor[T]) ordering() string {
+	h := sha256.New()
+	for _, column := range cur.Columns {
+		h.Write([]byte(column.orderClause() + ","))
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// encode builds the signed cursor of an item: base64url(JSON ordering and values)[.signature]
+func (cur *Cursor[T]) encode(ctx context.Context, item T, fields []*schema.Field) (string, error) {
+	payload := cursorPayload{Ordering: cur.ordering(), Values: make([]json.RawMessage, len(fields))}
+	rv := reflect.Indirect(reflect.ValueOf(&item))
+	for i, field := range fields {
+		value, _ := field.ValueOf(ctx, rv)
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode cursor: %w", err)
+		}
+		payload.Values[i] = raw
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(data)
+	if cur.Secret != "" {
+		cursor += "." + hmac.Sign([]byte(cursor), cur.Secret)
+	}
+	return cursor, nil
+}
+
+// decode verifies a cursor, checks that it was issued for the same ordering and decodes
+// its values into the types of the fields
+func (cur *Cursor[T]) decode(cursor string, fields []*schema.Field) ([]interface{}, error) {
+	payload, signature, signed := strings.Cut(cursor, ".")
+	if cur.Secret != "" && (!signed || !hmac.Verify([]byte(payload), signature, cur.Secret)) {
+		return nil, ErrInvalidCursor
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var decoded cursorPayload
+	if err := json.Unmarshal(data, &decoded); err != nil || len(decoded.Values) != len(fields) {
+		return nil, ErrInvalidCursor
+	}
+	if decoded.Ordering != cur.ordering() {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		value := reflect.New(field.FieldType)
+		if err := json.Unmarshal(decoded.Values[i], value.Interface()); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		values[i] = value.Elem().Interface()
+	}
+	return values, nil
+}
@@ -0,0 +1,46 @@
+package pagination
+
+import (
+	"context"
+
+	"github.com/kerimovok/go-pkg-utils/httpx"
+)
+
+// MongoCollection is the subset of a MongoDB collection QueryMongo needs. The mongo-driver is
+// not a dependency of this package; wrap a *mongo.Collection in a small adapter (see README).
+type MongoCollection interface {
+	// CountDocuments returns the number of documents matching filter
+	CountDocuments(ctx context.Context, filter interface{}) (int64, error)
+	// FindPage decodes the documents matching filter, sorted by sortField (descending if desc),
+	// after skipping skip documents and up to limit documents, into results (a *[]T)
+	FindPage(ctx context.Context, filter interface{}, sortField string, desc bool, skip, limit int64, results interface{}) error
+}
+
+// QueryMongo applies pagination to a MongoDB collection, sorting by params.SortBy and
+// params.SortOrder (restrict SortBy to known fields before calling)
+func QueryMongo[T any](
+	ctx context.Context,
+	collection MongoCollection,
+	filter interface{},
+	params *Params,
+	message string,
+) (*httpx.PaginatedResponse, error) {
+	total, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []T{}
+	offset := int64(params.Page-1) * int64(params.PerPage)
+	if err := collection.FindPage(ctx, filter, params.SortBy, params.SortOrder == "desc", offset, int64(params.PerPage), &results); err != nil {
+		return nil, err
+	}
+
+	pagination := httpx.NewPagination(params.Page, params.PerPage, total)
+	response := httpx.Paginated(message, results, pagination)
+	if params.EchoQuery {
+		response.Query = params.appliedQuery()
+	}
+
+	return &response, nil
+}
@@ -32,27 +32,58 @@ func OrderClause(sortBy, sortOrder string, fieldToColumn map[string]string, defa
 // Params represents pagination query parameters
 type Params struct {
 	Page      int    `query:"page" validate:"min=1"`
-	PerPage   int    `query:"per_page" validate:"min=1,max=500"`              // Items per page
+	PerPage   int    `query:"per_page" validate:"min=1"`                      // Items per page, capped by Defaults.MaxPerPage
 	SortBy    string `query:"sort_by"`                                        // Sort field name
 	SortOrder string `query:"sort_order" validate:"omitempty,oneof=asc desc"` // Sort order: asc or desc
+	OrderBy   string `query:"-"`                                              // Explicit ORDER BY clause (e.g. filter.QuerySpec.OrderClause()); overrides SortBy/SortOrder
+
+	// EchoQuery adds the effective query to the response (set from Defaults.EchoQuery)
+	EchoQuery bool                  `query:"-"`
+	Sort      []httpx.AppliedSort   `query:"-"` // Echoed sort when OrderBy is set (e.g. filter.QuerySpec.AppliedSort())
+	Filters   []httpx.AppliedFilter `query:"-"` // Echoed filters (e.g. filter.QuerySpec.AppliedFilters())
+}
+
+// appliedQuery returns the echo of the effective query
+func (p *Params) appliedQuery() *httpx.AppliedQuery {
+	sort := p.Sort
+	if sort == nil && p.OrderBy == "" && p.SortBy != "" {
+		sort = []httpx.AppliedSort{{Field: p.SortBy, Order: p.SortOrder}}
+	}
+	return &httpx.AppliedQuery{
+		Page:    p.Page,
+		PerPage: p.PerPage,
+		Sort:    sort,
+		Filters: p.Filters,
+	}
+}
+
+// orderClause returns the ORDER BY clause for the query
+func (p *Params) orderClause() string {
+	if p.OrderBy != "" {
+		return p.OrderBy
+	}
+	return p.SortBy + " " + p.SortOrder
 }
 
 // Defaults holds default values for pagination
 type Defaults struct {
-	Page      int
-	PerPage   int
-	SortBy    string
-	SortOrder string
+	Page       int
+	PerPage    int
+	SortBy     string
+	SortOrder  string
+	MaxPerPage int  // Largest accepted per_page (0 = the global cap, DefaultMaxPerPage unless set)
+	EchoQuery  bool // Echo the effective page, per_page, sort and filters in responses
+	// SortFields names the validator whitelist sort_by must be part of (see
+	// validator.RegisterWhitelist); it must include SortBy. Empty = any sort_by is accepted.
+	SortFields string
 }
 
-// Default returns sensible defaults for pagination
+// Default returns the global pagination defaults (page 1, 20 per page, newest first unless
+// changed with SetGlobalDefaults)
 func Default() Defaults {
-	return Defaults{
-		Page:      1,
-		PerPage:   20,
-		SortBy:    "created_at",
-		SortOrder: "desc",
-	}
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return globalDefaults
 }
 
 // ParseParams parses and validates pagination parameters from Fiber context
@@ -75,11 +106,21 @@ func ParseParams(c *fiber.Ctx, defaults Defaults) (*Params, error) {
 	if params.SortOrder == "" {
 		params.SortOrder = defaults.SortOrder
 	}
+	params.SortOrder = strings.ToLower(params.SortOrder)
+	params.EchoQuery = defaults.EchoQuery
 
 	// Validate after defaults are applied
 	if err := validator.ValidateStruct(&params); err != nil {
 		return nil, err
 	}
+	if defaults.SortFields != "" {
+		if err := validator.ValidateValue("SortBy", params.SortBy, "sortfield="+defaults.SortFields); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkPerPage(params.PerPage, defaults); err != nil {
+		return nil, err
+	}
 
 	return &params, nil
 }
@@ -91,21 +132,36 @@ func Query[T any](
 	params *Params,
 	message string,
 ) (*httpx.PaginatedResponse, error) {
-	// Clone query for counting (before applying per_page/offset)
-	// Use Session to create a new query instance with the same conditions
-	countQuery := query.Session(&gorm.Session{}).WithContext(ctx)
+	return QueryWithOptions[T](ctx, query, params, message, QueryOptions{})
+}
 
-	// Get total count
+// QueryWithOptions is Query with a count strategy (see CountStrategy)
+func QueryWithOptions[T any](
+	ctx context.Context,
+	query *gorm.DB,
+	params *Params,
+	message string,
+	options QueryOptions,
+) (*httpx.PaginatedResponse, error) {
+	// Get total count (before applying per_page/offset)
 	var total int64
-	if err := countQuery.Count(&total).Error; err != nil {
-		return nil, err
+	if options.Count != CountSkip {
+		var err error
+		if total, err = countTotal(ctx, query, options); err != nil {
+			return nil, err
+		}
 	}
 
-	// Apply sorting and pagination
+	// Apply sorting and pagination; totals that aren't exact don't tell whether there is a
+	// next page, so fetch one extra row
+	limit := params.PerPage
+	if options.Count != CountExact {
+		limit++
+	}
 	offset := (params.Page - 1) * params.PerPage
-	query = query.WithContext(ctx).Order(params.SortBy + " " + params.SortOrder).
+	query = query.WithContext(ctx).Order(params.orderClause()).
 		Offset(offset).
-		Limit(params.PerPage)
+		Limit(limit)
 
 	// Execute query
 	var results []T
@@ -114,8 +170,25 @@ func Query[T any](
 	}
 
 	// Build paginated response
-	pagination := httpx.NewPagination(params.Page, params.PerPage, total)
+	var pagination *httpx.Pagination
+	if options.Count == CountExact {
+		pagination = httpx.NewPagination(params.Page, params.PerPage, total)
+	} else {
+		hasNext := len(results) > params.PerPage
+		if hasNext {
+			results = results[:params.PerPage]
+		}
+		if options.Count == CountSkip {
+			pagination = paginationWithoutTotal(params.Page, params.PerPage, hasNext)
+		} else {
+			pagination = httpx.NewPagination(params.Page, params.PerPage, total)
+			setHasNext(pagination, hasNext)
+		}
+	}
 	response := httpx.Paginated(message, results, pagination)
+	if params.EchoQuery {
+		response.Query = params.appliedQuery()
+	}
 
 	return &response, nil
 }
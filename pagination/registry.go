@@ -0,0 +1,87 @@
+package pagination
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DefaultMaxPerPage is the per_page cap when neither the defaults nor the global defaults set one
+const DefaultMaxPerPage = 500
+
+var (
+	defaultsMu sync.RWMutex
+
+	// globalDefaults are returned by Default and fill the unset fields of resource defaults
+	globalDefaults = Defaults{
+		Page:       1,
+		PerPage:    20,
+		SortBy:     "created_at",
+		SortOrder:  "desc",
+		MaxPerPage: DefaultMaxPerPage,
+	}
+	resourceDefaults = make(map[string]Defaults)
+)
+
+// SetGlobalDefaults replaces the defaults returned by Default; zero fields keep their current value
+func SetGlobalDefaults(defaults Defaults) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	globalDefaults = defaults.merge(globalDefaults)
+}
+
+// SetDefaults registers (or replaces) the defaults of a resource (e.g. "users"), returned by
+// DefaultsFor. Zero fields fall back to the global defaults.
+func SetDefaults(resource string, defaults Defaults) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	resourceDefaults[resource] = defaults
+}
+
+// DefaultsFor returns the defaults registered for resource, or the global defaults if there
+// are none
+func DefaultsFor(resource string) Defaults {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return resourceDefaults[resource].merge(globalDefaults)
+}
+
+// merge returns d with its zero fields taken from fallback
+func (d Defaults) merge(fallback Defaults) Defaults {
+	if d.Page <= 0 {
+		d.Page = fallback.Page
+	}
+	if d.PerPage <= 0 {
+		d.PerPage = fallback.PerPage
+	}
+	if d.SortBy == "" {
+		d.SortBy = fallback.SortBy
+	}
+	if d.SortOrder == "" {
+		d.SortOrder = fallback.SortOrder
+	}
+	if d.MaxPerPage <= 0 {
+		d.MaxPerPage = fallback.MaxPerPage
+	}
+	if d.SortFields == "" {
+		d.SortFields = fallback.SortFields
+	}
+	d.EchoQuery = d.EchoQuery || fallback.EchoQuery
+	return d
+}
+
+// checkPerPage enforces the per_page cap of defaults (the global cap if it sets none)
+func checkPerPage(perPage int, defaults Defaults) error {
+	limit := defaults.MaxPerPage
+	if limit <= 0 {
+		defaultsMu.RLock()
+		limit = globalDefaults.MaxPerPage
+		defaultsMu.RUnlock()
+	}
+	if limit <= 0 {
+		limit = DefaultMaxPerPage
+	}
+	if perPage > limit {
+		return fmt.Errorf("per_page must be at most %d", limit)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package pagination
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kerimovok/go-pkg-utils/httpx"
+)
+
+// SQLQueryer runs queries; *sql.DB, *sql.Tx and *sql.Conn implement it
+type SQLQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SQLQuery is a raw SQL query to paginate
+type SQLQuery[T any] struct {
+	Query      string        // SELECT without ORDER BY/LIMIT/OFFSET, which are appended from Params
+	CountQuery string        // Query returning the total (e.g. SELECT COUNT(*) ...); empty skips counting
+	Args       []interface{} // Arguments of both queries
+	Scan       func(rows *sql.Rows) (T, error)
+	// SortColumns maps the sort_by values clients may use to columns (e.g. "name" -> "users.name").
+	// params.SortBy must be one of them unless params.OrderBy is set.
+	SortColumns map[string]string
+}
+
+// orderClause returns the ORDER BY clause: params.OrderBy, or params.SortBy through the
+// SortColumns allow-list, so client input never reaches the SQL text
+func (q SQLQuery[T]) orderClause(params *Params) (string, error) {
+	if params.OrderBy != "" {
+		return params.OrderBy, nil
+	}
+	column, ok := q.SortColumns[params.SortBy]
+	if !ok {
+		return "", fmt.Errorf("sort field '%s' is not allowed", params.SortBy)
+	}
+	return OrderClause(params.SortBy, params.SortOrder, q.SortColumns, column), nil
+}
+
+// QuerySQL applies pagination to a raw SQL query for services using database/sql directly.
+// Sorting is params.OrderBy if set, otherwise params.SortBy mapped through query.SortColumns;
+// any other sort field is rejected. Without a CountQuery the total is unknown (-1) and
+// hasNext is detected by fetching one extra row.
+func QuerySQL[T any](
+	ctx context.Context,
+	db SQLQueryer,
+	query SQLQuery[T],
+	params *Params,
+	message string,
+) (*httpx.PaginatedResponse, error) {
+	orderBy, err := query.orderClause(params)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if query.CountQuery != "" {
+		if err := db.QueryRowContext(ctx, query.CountQuery, query.Args...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count rows: %w", err)
+		}
+	}
+
+	limit := params.PerPage
+	if query.CountQuery == "" {
+		limit++
+	}
+	offset := (params.Page - 1) * params.PerPage
+	rows, err := db.QueryContext(ctx,
+		fmt.Sprintf("%s ORDER BY %s LIMIT %d OFFSET %d", query.Query, orderBy, limit, offset),
+		query.Args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]T, 0, limit)
+	for rows.Next() {
+		item, err := query.Scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		results = append(results, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query rows: %w", err)
+	}
+
+	var pagination *httpx.Pagination
+	if query.CountQuery != "" {
+		pagination = httpx.NewPagination(params.Page, params.PerPage, total)
+	} else {
+		hasNext := len(results) > params.PerPage
+		if hasNext {
+			results = results[:params.PerPage]
+		}
+		pagination = paginationWithoutTotal(params.Page, params.PerPage, hasNext)
+	}
+	response := httpx.Paginated(message, results, pagination)
+	if params.EchoQuery {
+		response.Query = params.appliedQuery()
+	}
+
+	return &response, nil
+}
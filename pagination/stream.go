@@ -0,0 +1,71 @@
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// DefaultStreamBatchSize is the batch size used by StreamAll when none is given
+const DefaultStreamBatchSize = 1000
+
+// StreamAll iterates the whole result set of query in batches ordered by the model's primary
+// key, calling fn with each batch. Batches are fetched by keyset rather than OFFSET so exports
+// of large tables (CSV, Excel, ...) run in constant memory and time per batch. Iteration stops
+// at the first error of fn or the query, or when ctx is done.
+func StreamAll[T any](ctx context.Context, query *gorm.DB, batchSize int, fn func([]T) error) error {
+	s, err := schema.Parse(new(T), &sync.Map{}, query.NamingStrategy)
+	if err != nil {
+		return fmt.Errorf("failed to parse model: %w", err)
+	}
+	if s.PrioritizedPrimaryField == nil {
+		return fmt.Errorf("streaming %s requires a single primary key; use Cursor.Stream", s.Name)
+	}
+
+	table := s.Table
+	if query.Statement != nil && query.Statement.Table != "" {
+		table = query.Statement.Table
+	}
+	cur := NewCursor[T]("", CursorColumn{Column: table + "." + s.PrioritizedPrimaryField.DBName})
+	return cur.Stream(ctx, query, batchSize, fn)
+}
+
+// Stream iterates the whole result set of query in batches in the cursor's ordering, calling
+// fn with each batch (see StreamAll)
+func (cur *Cursor[T]) Stream(ctx context.Context, query *gorm.DB, batchSize int, fn func([]T) error) error {
+	if len(cur.Columns) == 0 {
+		return fmt.Errorf("cursor pagination requires at least one column")
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultStreamBatchSize
+	}
+
+	fields, err := cur.fields(query)
+	if err != nil {
+		return err
+	}
+
+	cursor := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		batch, next, err := cur.page(ctx, query, fields, cursor, batchSize)
+		if err != nil {
+			return err
+		}
+		if len(batch) > 0 {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,278 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Message is a broker-neutral message
+type Message struct {
+	Topic   string            // Routing key, subject or topic
+	Key     string            // Partition/ordering key (optional)
+	Body    []byte            // Payload, usually JSON
+	Headers map[string]string // Metadata such as trace context (optional)
+}
+
+// BrokerHandler processes a message; returning an error asks the broker to redeliver it
+type BrokerHandler func(ctx context.Context, msg Message) error
+
+// Publisher publishes messages to a broker
+type Publisher interface {
+	Publish(ctx context.Context, msg Message) error
+	Close() error
+}
+
+// Subscriber delivers messages of a topic to a handler until the subscriber is closed.
+// Topics support AMQP-style wildcards where the backend does ("*" = one word, "#" = any).
+type Subscriber interface {
+	Subscribe(ctx context.Context, topic string, handler BrokerHandler) error
+	Close() error
+}
+
+// Broker publishes and subscribes, so services are not tied to a specific message broker
+type Broker interface {
+	Publisher
+	Subscriber
+}
+
+// MemoryBroker is an in-process Broker for unit tests. Publish delivers synchronously to
+// every matching subscription, retrying failed handlers up to MaxRetries times; messages
+// that still fail are kept as dead letters.
+type MemoryBroker struct {
+	MaxRetries int
+
+	mu            sync.Mutex
+	subscriptions []memorySubscription
+	published     []Message
+	deadLetters   []Message
+	closed        bool
+}
+
+type memorySubscription struct {
+	topic   string
+	handler BrokerHandler
+}
+
+// NewMemoryBroker creates an in-memory broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Publish records the message and delivers it to matching subscriptions
+func (b *MemoryBroker) Publish(ctx context.Context, msg Message) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return fmt.Errorf("broker is closed")
+	}
+	b.published = append(b.published, msg)
+	var handlers []BrokerHandler
+	for _, sub := range b.subscriptions {
+		if MatchTopic(sub.topic, msg.Topic) {
+			handlers = append(handlers, sub.handler)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, handler := range handlers {
+		var err error
+		for attempt := 0; attempt <= b.MaxRetries; attempt++ {
+			if err = handler(ctx, msg); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			b.mu.Lock()
+			b.deadLetters = append(b.deadLetters, msg)
+			b.mu.Unlock()
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a handler for a topic pattern
+func (b *MemoryBroker) Subscribe(_ context.Context, topic string, handler BrokerHandler) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return fmt.Errorf("broker is closed")
+	}
+	b.subscriptions = append(b.subscriptions, memorySubscription{topic: topic, handler: handler})
+	return nil
+}
+
+// Published returns all published messages in order
+func (b *MemoryBroker) Published() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Message(nil), b.published...)
+}
+
+// DeadLetters returns messages whose handler failed after all retries
+func (b *MemoryBroker) DeadLetters() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Message(nil), b.deadLetters...)
+}
+
+// Reset clears published messages and dead letters, keeping subscriptions
+func (b *MemoryBroker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = nil
+	b.deadLetters = nil
+}
+
+// Close rejects further publishing and subscribing
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return nil
+}
+
+// MatchTopic matches a topic against an AMQP topic-exchange pattern:
+// words are separated by '.', '*' matches one word and '#' matches zero or more words
+func MatchTopic(pattern, topic string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(topic, "."))
+}
+
+func matchWords(pattern, topic []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case "#":
+			for i := 0; i <= len(topic); i++ {
+				if matchWords(pattern[1:], topic[i:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+			if len(topic) == 0 {
+				return false
+			}
+		default:
+			if len(topic) == 0 || pattern[0] != topic[0] {
+				return false
+			}
+		}
+		pattern, topic = pattern[1:], topic[1:]
+	}
+	return len(topic) == 0
+}
+
+// RabbitBroker adapts the RabbitMQ Producer and Consumer to the Broker interface.
+// Message topics are routing keys on the configured exchange; keys are sent as the
+// partition key header.
+type RabbitBroker struct {
+	connConfig  ConnectionConfig
+	config      *Config
+	retryConfig RetryConfig
+	options     ConsumerOptions
+	producer    *Producer
+
+	mu        sync.Mutex
+	consumers []*Consumer
+}
+
+// NewRabbitBroker creates a RabbitMQ-backed Broker publishing to config's exchange.
+// Each subscription consumes its own queue named after the topic, prefixed with
+// config.QueueName if set ("<QueueName>.<topic>").
+func NewRabbitBroker(connConfig ConnectionConfig, config *Config, retryConfig RetryConfig, options ConsumerOptions) (*RabbitBroker, error) {
+	producerConfig := *config
+	producerConfig.QueueName = "" // Subscriptions declare their own queues
+	producer, err := NewProducer(connConfig, &producerConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &RabbitBroker{
+		connConfig:  connConfig,
+		config:      config,
+		retryConfig: retryConfig,
+		options:     options,
+		producer:    producer,
+	}, nil
+}
+
+// Publish publishes msg with its topic as routing key
+func (b *RabbitBroker) Publish(ctx context.Context, msg Message) error {
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	if msg.Key != "" {
+		headers[HeaderPartitionKey] = msg.Key
+	}
+	return b.producer.PublishWithRoutingKey(ctx, msg.Body, headers, msg.Topic)
+}
+
+// Subscribe declares a queue bound to the topic and starts consuming it
+func (b *RabbitBroker) Subscribe(_ context.Context, topic string, handler BrokerHandler) error {
+	config := *b.config
+	config.RoutingKey = topic
+	config.QueueName = b.queueName(topic)
+
+	var consumer *Consumer
+	consumer, err := NewConsumerWithOptions(b.connConfig, &config, b.retryConfig, func(delivery amqp.Delivery) error {
+		ctx, end := consumer.messageContext(delivery)
+		err := handler(ctx, deliveryToMessage(delivery))
+		end(err)
+		return err
+	}, b.options)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	b.mu.Lock()
+	b.consumers = append(b.consumers, consumer)
+	b.mu.Unlock()
+	return consumer.StartConsuming()
+}
+
+// queueName returns the queue consumed by a subscription to topic, so subscriptions
+// to different topics never share (and steal messages from) one queue
+func (b *RabbitBroker) queueName(topic string) string {
+	if b.config.QueueName == "" {
+		return topic
+	}
+	return b.config.QueueName + "." + topic
+}
+
+// Close shuts down all subscriptions and the producer, returning the first error
+func (b *RabbitBroker) Close() error {
+	b.mu.Lock()
+	consumers := b.consumers
+	b.consumers = nil
+	b.mu.Unlock()
+
+	var firstErr error
+	for _, consumer := range consumers {
+		if err := consumer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := b.producer.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// deliveryToMessage converts an AMQP delivery to a broker-neutral message
+func deliveryToMessage(delivery amqp.Delivery) Message {
+	msg := Message{Topic: OriginalRoutingKey(delivery), Body: delivery.Body, Headers: make(map[string]string, len(delivery.Headers))}
+	for k, v := range delivery.Headers {
+		switch k {
+		case HeaderPartitionKey:
+			msg.Key = fmt.Sprint(v)
+		case HeaderOriginalRoutingKey:
+			// Restored as Topic
+		default:
+			msg.Headers[k] = fmt.Sprint(v)
+		}
+	}
+	return msg
+}
@@ -1,18 +1,31 @@
 package queue
 
 import (
+	"time"
+
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 // Config holds the configuration for RabbitMQ queues and exchanges
 type Config struct {
 	ExchangeName    string
-	ExchangeType    string // "direct", "topic", "fanout", "headers" - defaults to "direct"
+	ExchangeType    string     // "direct", "topic", "fanout", "headers", "x-consistent-hash" - defaults to "direct"
+	ExchangeArgs    amqp.Table // Optional exchange arguments (e.g. "hash-header" for consistent-hash exchanges)
 	QueueName       string
 	RoutingKey      string
+	BindingKeys     []string // Additional routing keys QueueName is bound to (optional)
 	DLXExchangeName string
 	DLQName         string
 	DLQRoutingKey   string
+
+	// RetryDelays declares one TTL retry queue per delay; failed messages wait there and are
+	// dead-lettered back to QueueName. Retry attempt n uses RetryDelays[min(n, len-1)], so
+	// RetryConfig.Jitter and the x-retry-delay-base header have no effect.
+	// See RetryDelaysFor to derive the delays from a RetryConfig.
+	RetryDelays []time.Duration
+	// DelayedExchangeName declares an x-delayed-message exchange (rabbitmq_delayed_message_exchange
+	// plugin) bound to QueueName and used for retries when RetryDelays is empty
+	DelayedExchangeName string
 }
 
 // getExchangeType returns the exchange type, defaulting to "direct" if not set
@@ -43,7 +56,7 @@ func (qc *Config) SetupExchange(ch *amqp.Channel) error {
 		false,                // auto-deleted
 		false,                // internal
 		false,                // no-wait
-		nil,                  // arguments
+		qc.ExchangeArgs,      // arguments
 	)
 }
 
@@ -99,13 +112,18 @@ func (qc *Config) SetupMainQueue(ch *amqp.Channel) error {
 	}
 
 	// Bind queue to exchange
-	return ch.QueueBind(
-		qc.QueueName,    // queue name
-		qc.RoutingKey,   // routing key
-		qc.ExchangeName, // exchange
-		false,
-		nil,
-	)
+	for _, key := range append([]string{qc.RoutingKey}, qc.BindingKeys...) {
+		if err := ch.QueueBind(
+			qc.QueueName,    // queue name
+			key,             // routing key
+			qc.ExchangeName, // exchange
+			false,
+			nil,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SetupAllQueues sets up all exchanges and queues
@@ -129,8 +147,21 @@ func (qc *Config) SetupAllQueues(ch *amqp.Channel) error {
 	}
 
 	// Setup main queue (skip if not configured - producer-only mode)
-	if qc.QueueName != "" {
-		return qc.SetupMainQueue(ch)
+	if qc.QueueName == "" {
+		return nil
+	}
+	if err := qc.SetupMainQueue(ch); err != nil {
+		return err
+	}
+
+	// Setup delayed retry topology (if configured)
+	if len(qc.RetryDelays) > 0 {
+		if err := qc.SetupRetryQueues(ch); err != nil {
+			return err
+		}
+	}
+	if qc.DelayedExchangeName != "" {
+		return qc.SetupDelayedExchange(ch)
 	}
 
 	return nil
@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Authentication mechanisms supported by ConnectionConfig
+const (
+	AuthPlain    = "PLAIN"
+	AuthExternal = "EXTERNAL" // Client certificate authentication (rabbitmq_auth_mechanism_ssl)
+)
+
+// Default connection settings
+const (
+	DefaultHeartbeat         = 10 * time.Second
+	DefaultConnectionTimeout = 30 * time.Second
+)
+
+// TLSConfig holds TLS settings for amqps connections
+type TLSConfig struct {
+	Enabled            bool
+	CAFile             string // PEM CA bundle used to verify the server (system roots if empty)
+	CertFile           string // PEM client certificate for mutual TLS / EXTERNAL auth
+	KeyFile            string // PEM client key
+	ServerName         string // Name to verify the server certificate SANs against (defaults to Host)
+	InsecureSkipVerify bool   // Disable server verification - development only
+}
+
+// URL returns the AMQP URL for the connection (amqps when TLS is enabled)
+func (c ConnectionConfig) URL() string {
+	scheme := "amqp"
+	if c.TLS.Enabled {
+		scheme = "amqps"
+	}
+	u := url.URL{
+		Scheme: scheme,
+		Host:   c.Host + ":" + c.Port,
+		Path:   "/" + c.VHost,
+	}
+	if c.AuthMechanism != AuthExternal {
+		u.User = url.UserPassword(c.Username, c.Password)
+	}
+	return u.String()
+}
+
+// Dial connects to RabbitMQ using the configured TLS, authentication, heartbeat and timeout
+func (c ConnectionConfig) Dial() (*amqp.Connection, error) {
+	config := amqp.Config{
+		Heartbeat: c.Heartbeat,
+		Locale:    "en_US",
+		Dial:      amqp.DefaultDial(c.ConnectionTimeout),
+	}
+	if config.Heartbeat == 0 {
+		config.Heartbeat = DefaultHeartbeat
+	}
+	if c.ConnectionTimeout == 0 {
+		config.Dial = amqp.DefaultDial(DefaultConnectionTimeout)
+	}
+
+	switch c.AuthMechanism {
+	case "", AuthPlain:
+		config.SASL = []amqp.Authentication{&amqp.PlainAuth{Username: c.Username, Password: c.Password}}
+	case AuthExternal:
+		if !c.TLS.Enabled || c.TLS.CertFile == "" {
+			return nil, fmt.Errorf("EXTERNAL auth requires TLS with a client certificate")
+		}
+		config.SASL = []amqp.Authentication{&amqp.ExternalAuth{}}
+	default:
+		return nil, fmt.Errorf("unsupported auth mechanism: %s", c.AuthMechanism)
+	}
+
+	if c.TLS.Enabled {
+		tlsConfig, err := c.TLS.Build()
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = c.Host
+		}
+		config.TLSClientConfig = tlsConfig
+	}
+
+	conn, err := amqp.DialConfig(c.URL(), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	return conn, nil
+}
+
+// Build creates a *tls.Config from the CA and client certificate files
+func (t TLSConfig) Build() (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.InsecureSkipVerify,
+	}
+
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", t.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if t.CertFile != "" || t.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
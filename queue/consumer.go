@@ -1,11 +1,12 @@
 package queue
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
@@ -16,12 +17,40 @@ type ConnectionConfig struct {
 	Username string
 	Password string
 	VHost    string
+
+	TLS               TLSConfig     // amqps settings (optional)
+	AuthMechanism     string        // AuthPlain (default) or AuthExternal
+	Heartbeat         time.Duration // Defaults to DefaultHeartbeat
+	ConnectionTimeout time.Duration // Dial timeout - defaults to DefaultConnectionTimeout
 }
 
 // MessageHandler is a function that processes a message
 // It should return an error if processing failed and retry is needed
 type MessageHandler func(msg amqp.Delivery) error
 
+// ConsumerOptions controls consumer throughput, memory use and instrumentation
+type ConsumerOptions struct {
+	Concurrency   int     // Number of messages processed in parallel - defaults to 1
+	PrefetchCount int     // Unacknowledged messages the broker may push ahead (QoS) - defaults to Concurrency
+	Metrics       Metrics // Receives consume, ack, retry and reconnect events (optional)
+	// Propagator extracts trace context from headers into typed handler contexts (nil = W3CPropagator)
+	Propagator Propagator
+	// StartSpan optionally starts a span around each typed handler call
+	StartSpan SpanStarter
+}
+
+// withDefaults returns the options with defaults applied
+func (o ConsumerOptions) withDefaults() ConsumerOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = 1
+	}
+	if o.PrefetchCount <= 0 {
+		o.PrefetchCount = o.Concurrency
+	}
+	o.Metrics = metricsOrNop(o.Metrics)
+	return o
+}
+
 // Consumer is a RabbitMQ consumer with automatic reconnection
 type Consumer struct {
 	conn        *amqp.Connection
@@ -31,24 +60,39 @@ type Consumer struct {
 	connConfig  ConnectionConfig
 	retryConfig RetryConfig
 	handler     MessageHandler
+	options     ConsumerOptions
 	consuming   bool
+	ctx         context.Context // Canceled when the consumer is closed
+	cancel      context.CancelFunc
 	stopChan    chan struct{}
 	stopOnce    sync.Once
+	consumerTag string         // Tag of the active consumer registration, used to cancel it
+	workers     sync.WaitGroup // Workers processing deliveries
 }
 
-// NewConsumer creates a new RabbitMQ consumer with automatic reconnection
+// NewConsumer creates a new RabbitMQ consumer with automatic reconnection that
+// processes one message at a time
 func NewConsumer(connConfig ConnectionConfig, queueConfig *Config, retryConfig RetryConfig, handler MessageHandler) (*Consumer, error) {
-	url := fmt.Sprintf("amqp://%s:%s@%s:%s/%s",
-		connConfig.Username,
-		connConfig.Password,
-		connConfig.Host,
-		connConfig.Port,
-		connConfig.VHost,
-	)
-
-	conn, err := amqp.Dial(url)
+	return NewConsumerWithOptions(connConfig, queueConfig, retryConfig, handler, ConsumerOptions{})
+}
+
+// NewConsumerWithOptions creates a new RabbitMQ consumer whose messages are processed
+// by a bounded pool of options.Concurrency workers. Retries are always delayed by the
+// broker: without RetryDelays or DelayedExchangeName, TTL retry queues are declared
+// for RetryDelaysFor(retryConfig).
+func NewConsumerWithOptions(connConfig ConnectionConfig, queueConfig *Config, retryConfig RetryConfig, handler MessageHandler, options ConsumerOptions) (*Consumer, error) {
+	if retryConfig.MaxRetries > 0 && !queueConfig.hasRetryTopology() {
+		config := *queueConfig
+		config.RetryDelays = RetryDelaysFor(retryConfig)
+		queueConfig = &config
+	}
+	if retryConfig.Jitter != JitterNone && len(queueConfig.RetryDelays) > 0 {
+		Logf("Retry jitter %q has no effect with TTL retry queues, which have fixed delays; use a delayed exchange for jittered retries", retryConfig.Jitter)
+	}
+
+	conn, err := connConfig.Dial()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
+		return nil, err
 	}
 
 	ch, err := conn.Channel()
@@ -71,9 +115,11 @@ func NewConsumer(connConfig ConnectionConfig, queueConfig *Config, retryConfig R
 		connConfig:  connConfig,
 		retryConfig: retryConfig,
 		handler:     handler,
+		options:     options.withDefaults(),
 		consuming:   false,
 		stopChan:    make(chan struct{}),
 	}
+	consumer.ctx, consumer.cancel = context.WithCancel(context.Background())
 
 	consumer.setupConnectionRecovery()
 
@@ -90,16 +136,31 @@ func (c *Consumer) StartConsuming() error {
 	c.consuming = true
 	c.mu.Unlock()
 
-	go c.consumeLoop()
+	// Workers live as long as the consume loop; it closes the work channel when it stops
+	work := make(chan amqp.Delivery)
+	for i := 0; i < c.options.Concurrency; i++ {
+		c.workers.Add(1)
+		go func() {
+			defer c.workers.Done()
+			for msg := range work {
+				c.processMessage(msg)
+			}
+		}()
+	}
+
+	go c.consumeLoop(work)
 	return nil
 }
 
-// consumeLoop handles the actual message consumption loop
-func (c *Consumer) consumeLoop() {
+// consumeLoop handles the actual message consumption loop, (re)registering the
+// consumer whenever the channel is replaced and handing deliveries to the workers
+func (c *Consumer) consumeLoop(work chan<- amqp.Delivery) {
+	defer close(work)
+
 	for {
 		select {
 		case <-c.stopChan:
-			log.Println("Stopping message consumption...")
+			Logf("Stopping message consumption...")
 			return
 		default:
 		}
@@ -107,24 +168,29 @@ func (c *Consumer) consumeLoop() {
 		c.mu.RLock()
 		if c.conn == nil || c.conn.IsClosed() || c.channel == nil || c.channel.IsClosed() {
 			c.mu.RUnlock()
-			log.Println("RabbitMQ connection is not available, waiting...")
-			time.Sleep(5 * time.Second)
+			Logf("RabbitMQ connection is not available, waiting...")
+			if !c.wait(5 * time.Second) {
+				return
+			}
 			continue
 		}
 		channel := c.channel
 		c.mu.RUnlock()
 
 		// Set QoS
-		err := channel.Qos(1, 0, false)
+		err := channel.Qos(c.options.PrefetchCount, 0, false)
 		if err != nil {
-			log.Printf("Failed to set QoS: %v, retrying...", err)
-			time.Sleep(5 * time.Second)
+			Logf("Failed to set QoS: %v, retrying...", err)
+			if !c.wait(5 * time.Second) {
+				return
+			}
 			continue
 		}
 
+		tag := fmt.Sprintf("%s.%s", c.config.QueueName, uuid.NewString())
 		msgs, err := channel.Consume(
 			c.config.QueueName,
-			"",
+			tag,
 			false, // auto-ack
 			false, // exclusive
 			false, // no-local
@@ -132,32 +198,69 @@ func (c *Consumer) consumeLoop() {
 			nil,
 		)
 		if err != nil {
-			log.Printf("Failed to register a consumer: %v, retrying...", err)
-			time.Sleep(5 * time.Second)
+			Logf("Failed to register a consumer: %v, retrying...", err)
+			if !c.wait(5 * time.Second) {
+				return
+			}
 			continue
 		}
 
-		log.Printf("Starting to consume messages from queue: %s", c.config.QueueName)
+		c.mu.Lock()
+		c.consumerTag = tag
+		c.mu.Unlock()
+
+		Logf("Starting to consume messages from queue: %s", c.config.QueueName)
+
+		if !c.dispatch(msgs, work) {
+			Logf("Stopping message consumption...")
+			return
+		}
+
+		Logf("Message channel closed, will retry consumption...")
+		if !c.wait(2 * time.Second) {
+			return
+		}
+	}
+}
 
-		for {
+// dispatch hands deliveries to the workers until the delivery channel closes (true)
+// or the consumer is stopped (false). Blocking on busy workers applies backpressure.
+func (c *Consumer) dispatch(msgs <-chan amqp.Delivery, work chan<- amqp.Delivery) bool {
+	for {
+		select {
+		case <-c.stopChan:
+			return false
+		case msg, ok := <-msgs:
+			if !ok {
+				return true
+			}
 			select {
+			case work <- msg:
 			case <-c.stopChan:
-				log.Println("Stopping message consumption...")
-				return
-			case msg, ok := <-msgs:
-				if !ok {
-					log.Println("Message channel closed, will retry consumption...")
-					time.Sleep(2 * time.Second)
-					break // Break inner loop to retry
-				}
-				go c.processMessage(msg)
+				return false
 			}
 		}
 	}
 }
 
+// wait sleeps for d, returning false if the consumer is stopped meanwhile
+func (c *Consumer) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-c.stopChan:
+		return false
+	}
+}
+
 // processMessage processes a single message with retry logic
 func (c *Consumer) processMessage(msg amqp.Delivery) {
+	queueName := c.config.QueueName
+	metrics := c.options.Metrics
+	metrics.Consumed(queueName)
+
 	// Ensure message is always acked or rejected (e.g. on handler panic)
 	var ackedOrRejected bool
 	defer func() {
@@ -165,58 +268,92 @@ func (c *Consumer) processMessage(msg amqp.Delivery) {
 			return
 		}
 		if r := recover(); r != nil {
-			log.Printf("Handler panicked, rejecting message: %v", r)
-			if err := msg.Reject(false); err != nil {
-				log.Printf("Failed to reject message after panic: %v", err)
-			}
+			Logf("Handler panicked, rejecting message: %v", r)
+			c.reject(msg, DeadLetterPanic)
 		}
 	}()
 
 	retryCount := GetRetryCount(msg)
+	retryConfig := c.retryConfig.ForMessage(msg)
 
-	if retryCount >= c.retryConfig.MaxRetries {
-		log.Printf("Max retries exceeded for message, sending to DLQ")
-		if err := msg.Reject(false); err != nil {
-			log.Printf("Failed to reject message after max retries: %v", err)
-		}
+	if retryCount >= retryConfig.MaxRetries {
+		Logf("Max retries exceeded for message, sending to DLQ")
+		c.reject(msg, DeadLetterMaxRetries)
 		ackedOrRejected = true
 		return
 	}
 
-	// Process the message using the handler
-	err := c.handler(msg)
+	// Process the message using the handler (wrapped by middlewares)
+	c.mu.RLock()
+	handler := c.handler
+	c.mu.RUnlock()
+	start := time.Now()
+	err := handler(msg)
+	metrics.HandlerDuration(queueName, time.Since(start), err)
+
+	if err != nil && !retryConfig.Retryable(err) {
+		Logf("Failed to process message permanently, sending to DLQ: %v", err)
+		c.reject(msg, DeadLetterPermanent)
+		ackedOrRejected = true
+		return
+	}
 	if err != nil {
-		log.Printf("Failed to process message (attempt %d/%d): %v", retryCount+1, c.retryConfig.MaxRetries, err)
+		Logf("Failed to process message (attempt %d/%d): %v", retryCount+1, retryConfig.MaxRetries, err)
 
-		// Prepare retry headers
+		// Prepare retry headers, keeping the original ones (e.g. partition key for sharded queues)
 		newHeaders := amqp.Table{}
-		if msg.Headers == nil {
-			msg.Headers = amqp.Table{}
+		for k, v := range msg.Headers {
+			newHeaders[k] = v
 		}
+		newHeaders[HeaderOriginalRoutingKey] = OriginalRoutingKey(msg)
 		newHeaders["x-retry-count"] = retryCount + 1
 		newHeaders["x-last-error"] = err.Error()
 		newHeaders["x-last-retry"] = time.Now().Unix()
+		delay := CalculateRetryDelay(retryCount, retryConfig)
+		if _, ok := msg.Headers[HeaderRetryDelayBase]; ok && len(c.config.RetryDelays) > 0 {
+			Logf("Ignoring %s header: TTL retry queues have fixed delays", HeaderRetryDelayBase)
+		}
 
-		// Reject message first so it is not redelivered before we publish retry
-		if err := msg.Reject(false); err != nil {
-			log.Printf("Failed to reject message for retry: %v", err)
+		// Hand the retry to the broker before acking, so a crash cannot lose the message
+		if err := c.config.PublishRetry(c.getChannel(), msg.Body, newHeaders, retryCount, delay); err != nil {
+			Logf("Failed to publish retry, requeueing message: %v", err)
+			if err := msg.Nack(false, true); err != nil {
+				Logf("Failed to requeue message: %v", err)
+			}
+			metrics.Nacked(queueName, true)
+		} else {
+			metrics.Retried(queueName, retryCount+1)
+			c.ack(msg)
 		}
 		ackedOrRejected = true
-
-		// Schedule retry using current channel at publish time (survives reconnect)
-		delay := CalculateRetryDelay(retryCount, c.retryConfig)
-		ScheduleRetry(c.getChannel, c.config, msg.Body, newHeaders, delay)
 		return
 	}
 
 	// Success - acknowledge message
+	c.ack(msg)
+	ackedOrRejected = true
+}
+
+// ack acknowledges a delivery
+func (c *Consumer) ack(msg amqp.Delivery) {
 	if err := msg.Ack(false); err != nil {
-		log.Printf("Failed to acknowledge message: %v", err)
+		Logf("Failed to acknowledge message: %v", err)
+		return
 	}
-	ackedOrRejected = true
+	c.options.Metrics.Acked(c.config.QueueName)
+}
+
+// reject rejects a delivery without requeueing, dead-lettering it if a DLX is configured
+func (c *Consumer) reject(msg amqp.Delivery, reason string) {
+	if err := msg.Reject(false); err != nil {
+		Logf("Failed to reject message (%s): %v", reason, err)
+		return
+	}
+	c.options.Metrics.Nacked(c.config.QueueName, false)
+	c.options.Metrics.DeadLettered(c.config.QueueName, reason)
 }
 
-// getChannel returns the current channel (for use by ScheduleRetry after reconnect)
+// getChannel returns the current channel (for publishing retries after reconnect)
 func (c *Consumer) getChannel() *amqp.Channel {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -230,38 +367,87 @@ func (c *Consumer) IsConnected() bool {
 	return c.conn != nil && !c.conn.IsClosed() && c.channel != nil && !c.channel.IsClosed()
 }
 
-// Close closes the consumer and its connections
-func (c *Consumer) Close() error {
+// Shutdown gracefully stops the consumer: it cancels the consumer registration so the
+// broker stops delivering, waits for in-flight handlers to finish and then closes the
+// channel and connection. Unacknowledged prefetched messages are requeued by the broker.
+// If ctx ends first, handler contexts are canceled, the connection is closed anyway and
+// ctx.Err() is returned.
+func (c *Consumer) Shutdown(ctx context.Context) error {
 	c.mu.Lock()
 	c.consuming = false
+	channel, tag := c.channel, c.consumerTag
 	c.mu.Unlock()
 
+	if channel != nil && !channel.IsClosed() && tag != "" {
+		if err := channel.Cancel(tag, false); err != nil {
+			Logf("Failed to cancel consumer %s: %v", tag, err)
+		}
+	}
+
 	c.stopOnce.Do(func() {
 		close(c.stopChan)
 	})
 
-	time.Sleep(100 * time.Millisecond)
+	drained := make(chan struct{})
+	go func() {
+		c.workers.Wait()
+		close(drained)
+	}()
+
+	var waitErr error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		waitErr = ctx.Err()
+	}
+	c.cancel()
+
+	if err := c.closeConnection(); err != nil {
+		return err
+	}
+	return waitErr
+}
 
+// Close closes the consumer and its connections, giving in-flight handlers
+// up to 5 seconds to finish (see Shutdown)
+func (c *Consumer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return c.Shutdown(ctx)
+}
+
+// closeConnection closes the channel and connection
+func (c *Consumer) closeConnection() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.channel != nil {
+	if c.channel != nil && !c.channel.IsClosed() {
 		if err := c.channel.Close(); err != nil {
 			return err
 		}
 	}
-	if c.conn != nil {
+	if c.conn != nil && !c.conn.IsClosed() {
 		return c.conn.Close()
 	}
 	return nil
 }
 
+// stopped checks if the consumer is shutting down
+func (c *Consumer) stopped() bool {
+	select {
+	case <-c.stopChan:
+		return true
+	default:
+		return false
+	}
+}
+
 // setupConnectionRecovery sets up automatic reconnection
 func (c *Consumer) setupConnectionRecovery() {
 	go func() {
 		for err := range c.conn.NotifyClose(make(chan *amqp.Error)) {
 			if err != nil {
-				log.Printf("RabbitMQ connection lost: %v, attempting to reconnect...", err)
+				Logf("RabbitMQ connection lost: %v, attempting to reconnect...", err)
 				c.reconnect()
 			}
 		}
@@ -270,7 +456,7 @@ func (c *Consumer) setupConnectionRecovery() {
 	go func() {
 		for err := range c.channel.NotifyClose(make(chan *amqp.Error)) {
 			if err != nil {
-				log.Printf("RabbitMQ channel lost: %v, attempting to reconnect...", err)
+				Logf("RabbitMQ channel lost: %v, attempting to reconnect...", err)
 				c.reconnect()
 			}
 		}
@@ -280,7 +466,10 @@ func (c *Consumer) setupConnectionRecovery() {
 // reconnect attempts to reconnect to RabbitMQ
 func (c *Consumer) reconnect() {
 	for {
-		log.Println("Attempting to reconnect to RabbitMQ...")
+		if c.stopped() {
+			return
+		}
+		Logf("Attempting to reconnect to RabbitMQ...")
 
 		c.mu.Lock()
 		if c.channel != nil {
@@ -291,31 +480,25 @@ func (c *Consumer) reconnect() {
 		}
 		c.mu.Unlock()
 
-		time.Sleep(5 * time.Second)
-
-		url := fmt.Sprintf("amqp://%s:%s@%s:%s/%s",
-			c.connConfig.Username,
-			c.connConfig.Password,
-			c.connConfig.Host,
-			c.connConfig.Port,
-			c.connConfig.VHost,
-		)
+		if !c.wait(5 * time.Second) {
+			return
+		}
 
-		conn, err := amqp.Dial(url)
+		conn, err := c.connConfig.Dial()
 		if err != nil {
-			log.Printf("Failed to reconnect: %v, retrying in 5 seconds...", err)
+			Logf("Failed to reconnect: %v, retrying in 5 seconds...", err)
 			continue
 		}
 
 		ch, err := conn.Channel()
 		if err != nil {
-			log.Printf("Failed to create channel: %v, retrying in 5 seconds...", err)
+			Logf("Failed to create channel: %v, retrying in 5 seconds...", err)
 			conn.Close()
 			continue
 		}
 
 		if err := c.config.SetupAllQueues(ch); err != nil {
-			log.Printf("Failed to setup queues: %v, retrying in 5 seconds...", err)
+			Logf("Failed to setup queues: %v, retrying in 5 seconds...", err)
 			ch.Close()
 			conn.Close()
 			continue
@@ -324,20 +507,11 @@ func (c *Consumer) reconnect() {
 		c.mu.Lock()
 		c.conn = conn
 		c.channel = ch
-		wasConsuming := c.consuming
 		c.mu.Unlock()
 
-		log.Println("Successfully reconnected to RabbitMQ")
-
-		if wasConsuming {
-			log.Println("Restarting message consumption after reconnection...")
-			go func() {
-				if err := c.StartConsuming(); err != nil {
-					log.Printf("Failed to restart consumption after reconnection: %v", err)
-				}
-			}()
-		}
-
+		// A running consume loop picks up the new channel and re-registers the consumer
+		Logf("Successfully reconnected to RabbitMQ")
+		c.options.Metrics.Reconnected("consumer")
 		break
 	}
 }
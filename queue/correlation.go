@@ -0,0 +1,74 @@
+package queue
+
+import (
+	"context"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Correlation headers. The correlation ID identifies a whole workflow and is copied to
+// every message it produces; the causation ID is the ID of the message that caused this one.
+const (
+	HeaderCorrelationID = "x-correlation-id"
+	HeaderCausationID   = "x-causation-id"
+)
+
+type correlationIDKey struct{}
+type causationIDKey struct{}
+
+// ContextWithCorrelationID returns a context whose published messages carry correlationID
+// (e.g. the request ID of the HTTP request starting a workflow)
+func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx ("" if none)
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// ContextWithCausationID returns a context whose published messages carry causationID
+func ContextWithCausationID(ctx context.Context, causationID string) context.Context {
+	return context.WithValue(ctx, causationIDKey{}, causationID)
+}
+
+// CausationIDFromContext returns the causation ID stored in ctx ("" if none)
+func CausationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(causationIDKey{}).(string)
+	return id
+}
+
+// ContextFromDelivery returns a context for handling delivery: messages published from it
+// keep the delivery's correlation ID (its own ID for workflow roots) and are caused by it
+func ContextFromDelivery(ctx context.Context, delivery amqp.Delivery) context.Context {
+	messageID := MessageID(delivery)
+	correlationID, _ := delivery.Headers[HeaderCorrelationID].(string)
+	if correlationID == "" {
+		correlationID = messageID
+	}
+	if correlationID != "" {
+		ctx = ContextWithCorrelationID(ctx, correlationID)
+	}
+	if messageID != "" {
+		ctx = ContextWithCausationID(ctx, messageID)
+	}
+	return ctx
+}
+
+// setCorrelationHeaders sets the correlation and causation headers from ctx unless the
+// caller already set them; messages published outside a workflow start a new one
+func setCorrelationHeaders(ctx context.Context, headers amqp.Table, messageID string) {
+	if _, ok := headers[HeaderCorrelationID]; !ok {
+		correlationID := CorrelationIDFromContext(ctx)
+		if correlationID == "" {
+			correlationID = messageID
+		}
+		headers[HeaderCorrelationID] = correlationID
+	}
+	if _, ok := headers[HeaderCausationID]; !ok {
+		if causationID := CausationIDFromContext(ctx); causationID != "" {
+			headers[HeaderCausationID] = causationID
+		}
+	}
+}
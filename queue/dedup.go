@@ -0,0 +1,174 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// HeaderMessageID carries the message ID in the headers, which (unlike the MessageId
+// property) are preserved when messages are retried
+const HeaderMessageID = "x-message-id"
+
+// DefaultProcessingTTL is how long a claim blocks redeliveries while its handler runs
+const DefaultProcessingTTL = 5 * time.Minute
+
+// Deduplicator remembers processed message IDs so redelivered messages can be skipped
+type Deduplicator interface {
+	// Claim marks id as being processed for ttl; it returns false if id is already
+	// being processed or was processed
+	Claim(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	// Commit marks a claimed id as processed, remembering it for the deduplicator's TTL
+	Commit(ctx context.Context, id string) error
+	// Release forgets id so the message can be processed again (e.g. after a handler error)
+	Release(ctx context.Context, id string) error
+}
+
+// MessageID returns the ID of a delivery: the MessageId property, else the x-message-id header
+func MessageID(msg amqp.Delivery) string {
+	if msg.MessageId != "" {
+		return msg.MessageId
+	}
+	if id, ok := msg.Headers[HeaderMessageID].(string); ok {
+		return id
+	}
+	return ""
+}
+
+// DeduplicationMiddleware skips (and acks) messages whose ID was already processed.
+// A message is claimed for processingTTL (<= 0 = DefaultProcessingTTL) while its handler
+// runs and only remembered for the deduplicator's TTL once the handler succeeded, so a
+// consumer crashing mid-message doesn't get the message skipped for good. Failed messages
+// are released for retry and messages without an ID are always handled. ctx (e.g. the
+// service's shutdown context) bounds the deduplicator calls.
+func DeduplicationMiddleware(ctx context.Context, dedup Deduplicator, processingTTL time.Duration) Middleware {
+	if processingTTL <= 0 {
+		processingTTL = DefaultProcessingTTL
+	}
+	return func(next MessageHandler) MessageHandler {
+		return func(msg amqp.Delivery) error {
+			id := MessageID(msg)
+			if id == "" {
+				return next(msg)
+			}
+
+			claimed, err := dedup.Claim(ctx, id, processingTTL)
+			if err != nil {
+				return fmt.Errorf("failed to check message %s for duplicates: %w", id, err)
+			}
+			if !claimed {
+				Logf("Skipping duplicate message %s", id)
+				return nil
+			}
+
+			if err := next(msg); err != nil {
+				if releaseErr := dedup.Release(ctx, id); releaseErr != nil {
+					Logf("Failed to release message %s: %v", id, releaseErr)
+				}
+				return err
+			}
+			if err := dedup.Commit(ctx, id); err != nil {
+				// The claim still expires after processingTTL; a redelivery before that is skipped
+				Logf("Failed to commit message %s: %v", id, err)
+			}
+			return nil
+		}
+	}
+}
+
+// MemoryDeduplicator is an in-process Deduplicator whose claims expire after a TTL
+type MemoryDeduplicator struct {
+	ttl    time.Duration
+	mu     sync.Mutex
+	claims map[string]time.Time // id -> expiry
+}
+
+// NewMemoryDeduplicator creates an in-memory deduplicator remembering processed IDs for ttl
+func NewMemoryDeduplicator(ttl time.Duration) *MemoryDeduplicator {
+	return &MemoryDeduplicator{ttl: ttl, claims: make(map[string]time.Time)}
+}
+
+// Claim marks id as being processed for ttl unless an unexpired claim exists
+func (d *MemoryDeduplicator) Claim(_ context.Context, id string, ttl time.Duration) (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	if expiry, ok := d.claims[id]; ok && now.Before(expiry) {
+		return false, nil
+	}
+	d.claims[id] = now.Add(ttl)
+
+	// Sweep expired claims occasionally to bound memory
+	if len(d.claims)%1024 == 0 {
+		for k, expiry := range d.claims {
+			if !now.Before(expiry) {
+				delete(d.claims, k)
+			}
+		}
+	}
+	return true, nil
+}
+
+// Commit remembers id as processed for the deduplicator's TTL
+func (d *MemoryDeduplicator) Commit(_ context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.claims[id] = time.Now().Add(d.ttl)
+	return nil
+}
+
+// Release removes the claim for id
+func (d *MemoryDeduplicator) Release(_ context.Context, id string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.claims, id)
+	return nil
+}
+
+// RedisClient is the subset of a Redis client used by RedisDeduplicator, so this package
+// does not depend on a specific driver. With go-redis:
+//
+//	type redisAdapter struct{ rdb *redis.Client }
+//	func (a redisAdapter) SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+//		return a.rdb.SetNX(ctx, key, 1, ttl).Result()
+//	}
+//	func (a redisAdapter) Set(ctx context.Context, key string, ttl time.Duration) error {
+//		return a.rdb.Set(ctx, key, 1, ttl).Err()
+//	}
+//	func (a redisAdapter) Del(ctx context.Context, key string) error { return a.rdb.Del(ctx, key).Err() }
+type RedisClient interface {
+	SetNX(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Set(ctx context.Context, key string, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// RedisDeduplicator is a Deduplicator shared by all consumer instances, backed by Redis SET NX
+type RedisDeduplicator struct {
+	client RedisClient
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedisDeduplicator creates a Redis deduplicator storing processed IDs as prefix+id for ttl
+func NewRedisDeduplicator(client RedisClient, prefix string, ttl time.Duration) *RedisDeduplicator {
+	return &RedisDeduplicator{client: client, prefix: prefix, ttl: ttl}
+}
+
+// Claim sets the ID key for ttl if it does not exist yet
+func (d *RedisDeduplicator) Claim(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	return d.client.SetNX(ctx, d.prefix+id, ttl)
+}
+
+// Commit extends the ID key to the deduplicator's TTL
+func (d *RedisDeduplicator) Commit(ctx context.Context, id string) error {
+	return d.client.Set(ctx, d.prefix+id, d.ttl)
+}
+
+// Release deletes the ID key
+func (d *RedisDeduplicator) Release(ctx context.Context, id string) error {
+	return d.client.Del(ctx, d.prefix+id)
+}
@@ -0,0 +1,240 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// XDeath is one entry of the x-death header RabbitMQ adds when dead-lettering a message
+type XDeath struct {
+	Queue       string
+	Reason      string // "rejected", "expired", "maxlen" or "delivery_limit"
+	Exchange    string
+	RoutingKeys []string
+	Count       int64
+	Time        time.Time
+}
+
+// DeadLetter is a message in a dead letter queue
+type DeadLetter struct {
+	Delivery   amqp.Delivery
+	Deaths     []XDeath // Most recent first
+	LastError  string   // x-last-error set by the consumer on the last failed attempt
+	RetryCount int
+}
+
+// DLQFilter selects dead letters for redrive
+type DLQFilter func(msg DeadLetter) bool
+
+// DLQBrowser inspects and redrives the dead letter queue of a Config
+type DLQBrowser struct {
+	conn   *amqp.Connection
+	config *Config
+}
+
+// NewDLQBrowser connects to RabbitMQ for inspecting config.DLQName
+func NewDLQBrowser(connConfig ConnectionConfig, config *Config) (*DLQBrowser, error) {
+	if config.DLQName == "" {
+		return nil, fmt.Errorf("config has no dead letter queue")
+	}
+	conn, err := connConfig.Dial()
+	if err != nil {
+		return nil, err
+	}
+	return &DLQBrowser{conn: conn, config: config}, nil
+}
+
+// Count returns the number of messages in the dead letter queue
+func (b *DLQBrowser) Count() (int, error) {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	q, err := ch.QueueDeclarePassive(b.config.DLQName, true, false, false, false, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to inspect dead letter queue: %w", err)
+	}
+	return q.Messages, nil
+}
+
+// Peek returns up to n dead letters without removing them from the queue
+func (b *DLQBrowser) Peek(n int) ([]DeadLetter, error) {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	var letters []DeadLetter
+	var last uint64
+	for len(letters) < n {
+		msg, ok, err := ch.Get(b.config.DLQName, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letter queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+		letters = append(letters, ParseDeadLetter(msg))
+		last = msg.DeliveryTag
+	}
+
+	// Put everything back in its original order
+	if last > 0 {
+		if err := ch.Nack(last, true, true); err != nil {
+			return nil, fmt.Errorf("failed to requeue peeked messages: %w", err)
+		}
+	}
+	return letters, nil
+}
+
+// Redrive republishes up to n dead letters matching filter (nil = all, n <= 0 = no limit)
+// to the main exchange and removes them from the dead letter queue. Retry headers are
+// reset so redriven messages get a fresh set of attempts. Non-matching messages stay in
+// the dead letter queue. Returns the number of redriven messages.
+func (b *DLQBrowser) Redrive(ctx context.Context, n int, filter DLQFilter) (int, error) {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close() // Requeues skipped messages
+
+	if err := ch.Confirm(false); err != nil {
+		return 0, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	redriven := 0
+	for n <= 0 || redriven < n {
+		if err := ctx.Err(); err != nil {
+			return redriven, err
+		}
+
+		msg, ok, err := ch.Get(b.config.DLQName, false)
+		if err != nil {
+			return redriven, fmt.Errorf("failed to read dead letter queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		letter := ParseDeadLetter(msg)
+		if filter != nil && !filter(letter) {
+			continue // Held unacked until the channel closes, so it is not fetched again
+		}
+
+		if err := b.republish(ctx, ch, letter); err != nil {
+			return redriven, err
+		}
+		if err := msg.Ack(false); err != nil {
+			return redriven, fmt.Errorf("failed to remove redriven message: %w", err)
+		}
+		redriven++
+	}
+	return redriven, nil
+}
+
+// Purge deletes all messages in the dead letter queue and returns how many were removed
+func (b *DLQBrowser) Purge() (int, error) {
+	ch, err := b.conn.Channel()
+	if err != nil {
+		return 0, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	count, err := ch.QueuePurge(b.config.DLQName, false)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge dead letter queue: %w", err)
+	}
+	return count, nil
+}
+
+// Close closes the browser's connection
+func (b *DLQBrowser) Close() error {
+	return b.conn.Close()
+}
+
+// republish publishes a dead letter back to the main queue and waits for the broker confirm
+func (b *DLQBrowser) republish(ctx context.Context, ch *amqp.Channel, letter DeadLetter) error {
+	msg := letter.Delivery
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		switch k {
+		case "x-death", "x-first-death-exchange", "x-first-death-queue", "x-first-death-reason",
+			"x-retry-count", "x-last-error", "x-last-retry", HeaderOriginalRoutingKey:
+		default:
+			headers[k] = v
+		}
+	}
+
+	routingKey := b.config.RoutingKey
+	for _, death := range letter.Deaths {
+		if death.Queue == b.config.QueueName && len(death.RoutingKeys) > 0 {
+			routingKey = death.RoutingKeys[0]
+			break
+		}
+	}
+	if key, ok := msg.Headers[HeaderOriginalRoutingKey].(string); ok {
+		routingKey = key // Retried messages reach the DLQ routed by queue name
+	}
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(ctx,
+		b.config.ExchangeName, // exchange
+		routingKey,            // routing key
+		false,                 // mandatory
+		false,                 // immediate
+		amqp.Publishing{
+			ContentType:  msg.ContentType,
+			Body:         msg.Body,
+			Headers:      headers,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    msg.MessageId,
+			Timestamp:    msg.Timestamp,
+			Priority:     msg.Priority,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to redrive message: %w", err)
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm redriven message: %w", err)
+	}
+	if !acked {
+		return fmt.Errorf("redriven message was not acknowledged by the broker")
+	}
+	return nil
+}
+
+// ParseDeadLetter extracts the x-death, x-last-error and x-retry-count headers of a delivery
+func ParseDeadLetter(msg amqp.Delivery) DeadLetter {
+	letter := DeadLetter{Delivery: msg, RetryCount: GetRetryCount(msg)}
+	if lastError, ok := msg.Headers["x-last-error"].(string); ok {
+		letter.LastError = lastError
+	}
+
+	deaths, _ := msg.Headers["x-death"].([]interface{})
+	for _, d := range deaths {
+		table, ok := d.(amqp.Table)
+		if !ok {
+			continue
+		}
+		death := XDeath{}
+		death.Queue, _ = table["queue"].(string)
+		death.Reason, _ = table["reason"].(string)
+		death.Exchange, _ = table["exchange"].(string)
+		death.Count, _ = table["count"].(int64)
+		death.Time, _ = table["time"].(time.Time)
+		keys, _ := table["routing-keys"].([]interface{})
+		for _, key := range keys {
+			if s, ok := key.(string); ok {
+				death.RoutingKeys = append(death.RoutingKeys, s)
+			}
+		}
+		letter.Deaths = append(letter.Deaths, death)
+	}
+	return letter
+}
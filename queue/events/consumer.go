@@ -0,0 +1,186 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kerimovok/go-pkg-utils/queue"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Handler processes an event of a registered type
+type Handler func(ctx context.Context, event Event) error
+
+// Default retry configuration for event consumers
+var defaultRetryConfig = queue.RetryConfig{
+	MaxRetries:     3,
+	RetryDelayBase: 1,  // seconds
+	MaxRetryDelay:  60, // seconds
+}
+
+// ConsumerConfig holds configuration for the event consumer
+type ConsumerConfig struct {
+	// QueueName is the queue of the consuming service (e.g. "billing.events")
+	QueueName string
+
+	// RouteByType binds the queue to the routing key of every registered event type,
+	// matching producers with RouteByType. Otherwise the queue receives all events and
+	// events without a handler are skipped.
+	RouteByType bool
+
+	// QueueConfig allows overriding the default queue configuration (optional).
+	// By default failed events go to the "<QueueName>.failed" dead letter queue.
+	QueueConfig *queue.Config
+
+	// RetryConfig allows overriding the default retry configuration (optional)
+	RetryConfig *queue.RetryConfig
+
+	// Options sets concurrency, metrics and tracing (optional)
+	Options queue.ConsumerOptions
+
+	// Schemas rejects events with invalid payloads or unsupported versions to the DLQ (optional)
+	Schemas *queue.SchemaRegistry
+}
+
+// Consumer dispatches events from a queue to handlers registered per event type
+type Consumer struct {
+	connConfig  queue.ConnectionConfig
+	config      ConsumerConfig
+	mu          sync.RWMutex
+	handlers    map[string]Handler
+	middlewares []queue.Middleware
+	consumer    *queue.Consumer
+}
+
+// NewConsumer creates a new event consumer; register handlers with On, then call Start
+func NewConsumer(connConfig queue.ConnectionConfig, config ConsumerConfig) (*Consumer, error) {
+	if config.QueueName == "" && (config.QueueConfig == nil || config.QueueConfig.QueueName == "") {
+		return nil, fmt.Errorf("queue name is required")
+	}
+
+	return &Consumer{
+		connConfig: connConfig,
+		config:     config,
+		handlers:   make(map[string]Handler),
+	}, nil
+}
+
+// On registers the handler for an event type, replacing any previous one
+func (c *Consumer) On(eventType string, handler Handler) *Consumer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers[eventType] = handler
+	return c
+}
+
+// Use wraps event handling with middlewares (e.g. recovery, logging)
+func (c *Consumer) Use(middlewares ...queue.Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+// Start declares the queue, its bindings and dead letter queue and starts consuming
+func (c *Consumer) Start() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.consumer != nil {
+		return fmt.Errorf("consumer already started")
+	}
+	if len(c.handlers) == 0 {
+		return fmt.Errorf("no event handlers registered")
+	}
+
+	retryConfig := defaultRetryConfig
+	if c.config.RetryConfig != nil {
+		retryConfig = *c.config.RetryConfig
+	}
+
+	consumer, err := queue.NewTypedConsumerWithOptions(c.connConfig, c.queueConfig(), retryConfig, c.dispatch, c.config.Options)
+	if err != nil {
+		return fmt.Errorf("failed to create event consumer: %w", err)
+	}
+	consumer.Use(c.middlewares...)
+
+	if err := consumer.StartConsuming(); err != nil {
+		consumer.Close()
+		return fmt.Errorf("failed to start event consumer: %w", err)
+	}
+	c.consumer = consumer
+	return nil
+}
+
+// queueConfig builds the queue configuration, mirroring the producer defaults
+func (c *Consumer) queueConfig() *queue.Config {
+	var config queue.Config
+	if c.config.QueueConfig != nil {
+		config = *c.config.QueueConfig
+	} else {
+		config = *defaultQueueConfig
+		config.QueueName = c.config.QueueName
+		config.DLQName = c.config.QueueName + ".failed"
+		config.DLQRoutingKey = c.config.QueueName + ".failed"
+	}
+
+	if c.config.RouteByType {
+		eventTypes := make([]string, 0, len(c.handlers))
+		for eventType := range c.handlers {
+			eventTypes = append(eventTypes, eventType)
+		}
+		sort.Strings(eventTypes)
+		config.RoutingKey, config.BindingKeys = eventTypes[0], eventTypes[1:]
+	}
+	return &config
+}
+
+// dispatch calls the handler registered for the event's type
+func (c *Consumer) dispatch(ctx context.Context, event Event, _ amqp.Delivery) error {
+	c.mu.RLock()
+	handler, ok := c.handlers[event.Type]
+	c.mu.RUnlock()
+
+	if !ok {
+		queue.Logf("No handler for event type %q from %s, skipping", event.Type, event.Service)
+		return nil
+	}
+	if c.config.Schemas != nil {
+		if err := c.config.Schemas.Validate(event.Type, event.Version, event.Payload); err != nil {
+			return queue.Permanent(err)
+		}
+	}
+	return handler(ctx, event)
+}
+
+// IsConnected returns true if the consumer is started and connected
+func (c *Consumer) IsConnected() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.consumer != nil && c.consumer.IsConnected()
+}
+
+// Shutdown stops consuming and waits for in-flight handlers or ctx
+func (c *Consumer) Shutdown(ctx context.Context) error {
+	c.mu.RLock()
+	consumer := c.consumer
+	c.mu.RUnlock()
+
+	if consumer == nil {
+		return nil
+	}
+	return consumer.Shutdown(ctx)
+}
+
+// Close closes the consumer connection
+func (c *Consumer) Close() error {
+	c.mu.RLock()
+	consumer := c.consumer
+	c.mu.RUnlock()
+
+	if consumer == nil {
+		return nil
+	}
+	return consumer.Close()
+}
@@ -0,0 +1,206 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kerimovok/go-pkg-utils/queue"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxMessage is an event stored in the outbox table until the relay publishes it
+type OutboxMessage struct {
+	ID            string     `gorm:"primaryKey;size:36"`
+	Type          string     `gorm:"size:255;not null"`
+	Data          []byte     `gorm:"not null"` // Marshaled Event
+	CorrelationID string     `gorm:"size:255"`
+	CausationID   string     `gorm:"size:255"`
+	Attempts      int        `gorm:"not null;default:0"`
+	LastError     string     `gorm:"type:text"`
+	CreatedAt     time.Time  `gorm:"index"`
+	SentAt        *time.Time `gorm:"index"`
+}
+
+// TableName returns the outbox table name
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
+
+// MigrateOutbox creates or updates the outbox table
+func MigrateOutbox(db *gorm.DB) error {
+	return db.AutoMigrate(&OutboxMessage{})
+}
+
+// Outbox writes events to the outbox table inside the caller's transaction, so an event
+// is stored if and only if the business change commits. A Relay publishes them afterwards.
+type Outbox struct {
+	service string
+	schemas *queue.SchemaRegistry
+}
+
+// NewOutbox creates an outbox for events of the given service
+func NewOutbox(serviceName string) (*Outbox, error) {
+	if serviceName == "" {
+		return nil, fmt.Errorf("service name is required")
+	}
+	return &Outbox{service: serviceName}, nil
+}
+
+// WithSchemas validates payloads against schemas before storing them and sets the event version
+func (o *Outbox) WithSchemas(schemas *queue.SchemaRegistry) *Outbox {
+	o.schemas = schemas
+	return o
+}
+
+// Publish stores an event in the outbox using tx (the caller's transaction). Correlation
+// and causation IDs are taken from the transaction's context (see tx.WithContext).
+func (o *Outbox) Publish(tx *gorm.DB, eventType string, payload map[string]any) error {
+	if payload == nil {
+		payload = make(map[string]any)
+	}
+
+	// Add timestamp if not present
+	if _, ok := payload["timestamp"]; !ok {
+		payload["timestamp"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	data, err := marshalEvent(o.schemas, o.service, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	msg := OutboxMessage{ID: uuid.NewString(), Type: eventType, Data: data}
+	if tx.Statement != nil && tx.Statement.Context != nil {
+		ctx := tx.Statement.Context
+		msg.CorrelationID = queue.CorrelationIDFromContext(ctx)
+		msg.CausationID = queue.CausationIDFromContext(ctx)
+	}
+	if err := tx.Create(&msg).Error; err != nil {
+		return fmt.Errorf("failed to write event to outbox: %w", err)
+	}
+	return nil
+}
+
+// RelayConfig holds configuration for the outbox relay
+type RelayConfig struct {
+	// BatchSize is the maximum number of events published per poll - defaults to 100
+	BatchSize int
+	// PollInterval is the wait between polls when the outbox is drained - defaults to 1 second
+	PollInterval time.Duration
+	// SkipLocked locks fetched rows with FOR UPDATE SKIP LOCKED so several relays can run
+	// concurrently (PostgreSQL, MySQL 8+)
+	SkipLocked bool
+}
+
+// Relay publishes outbox events in creation order and marks them sent. Delivery is
+// at-least-once: an event published just before a crash is published again, with the
+// same message ID, so consumers can deduplicate (see queue.DeduplicationMiddleware).
+type Relay struct {
+	db       *gorm.DB
+	producer *Producer
+	config   RelayConfig
+}
+
+// NewRelay creates a relay publishing outbox events through producer, which must have
+// publisher confirms enabled: events are only marked sent once the broker acknowledged them
+func NewRelay(db *gorm.DB, producer *Producer, config RelayConfig) (*Relay, error) {
+	if !producer.producer.ConfirmsEnabled() {
+		return nil, fmt.Errorf("outbox relay requires a producer with publisher confirms (ProducerOptions.Confirm)")
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+	return &Relay{db: db, producer: producer, config: config}, nil
+}
+
+// Run relays events until ctx is canceled
+func (r *Relay) Run(ctx context.Context) error {
+	for {
+		sent, err := r.RelayOnce(ctx)
+		if err != nil {
+			queue.Logf("Outbox relay failed: %v", err)
+		}
+
+		// Keep draining full batches; otherwise wait for new events
+		if err == nil && sent == r.config.BatchSize {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+
+		timer := time.NewTimer(r.config.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RelayOnce publishes one batch of pending events and returns how many were sent.
+// It stops at the first publish failure so events keep their order.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	sent := 0
+	var publishErr error
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("sent_at IS NULL").Order("created_at, id").Limit(r.config.BatchSize)
+		if r.config.SkipLocked {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+
+		var messages []OutboxMessage
+		if err := query.Find(&messages).Error; err != nil {
+			return fmt.Errorf("failed to read outbox: %w", err)
+		}
+
+		for _, msg := range messages {
+			headers := amqp.Table{queue.HeaderMessageID: msg.ID}
+			if msg.CorrelationID != "" {
+				headers[queue.HeaderCorrelationID] = msg.CorrelationID
+			}
+			if msg.CausationID != "" {
+				headers[queue.HeaderCausationID] = msg.CausationID
+			}
+			if publishErr = r.producer.publishData(ctx, msg.Type, msg.Data, headers); publishErr != nil {
+				publishErr = fmt.Errorf("failed to publish event %s: %w", msg.ID, publishErr)
+				err := tx.Model(&OutboxMessage{}).Where("id = ?", msg.ID).Updates(map[string]any{
+					"attempts":   gorm.Expr("attempts + 1"),
+					"last_error": publishErr.Error(),
+				}).Error
+				if err != nil {
+					return fmt.Errorf("failed to record failed attempt of event %s: %w (%v)", msg.ID, err, publishErr)
+				}
+				return nil // Commit the sent events and the failed attempt
+			}
+
+			now := time.Now()
+			if err := tx.Model(&OutboxMessage{}).Where("id = ?", msg.ID).Update("sent_at", &now).Error; err != nil {
+				return fmt.Errorf("failed to mark event %s as sent: %w", msg.ID, err)
+			}
+			sent++
+		}
+		return nil
+	})
+	if err != nil {
+		return sent, err
+	}
+	return sent, publishErr
+}
+
+// PurgeSent deletes events sent before the given time and returns how many were deleted
+func (r *Relay) PurgeSent(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("sent_at IS NOT NULL AND sent_at < ?", before).Delete(&OutboxMessage{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to purge outbox: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/kerimovok/go-pkg-utils/queue"
+	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 // Event represents a generic event structure for the event queue
 type Event struct {
 	Service string         `json:"service"`
 	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"` // Payload schema version (0 = unversioned)
 	Payload map[string]any `json:"payload"`
 }
 
@@ -29,8 +31,10 @@ var defaultQueueConfig = &queue.Config{
 
 // Producer wraps the base queue producer for publishing events
 type Producer struct {
-	producer *queue.Producer
-	service  string
+	producer    *queue.Producer
+	service     string
+	routeByType bool
+	schemas     *queue.SchemaRegistry
 }
 
 // ProducerConfig holds configuration for the event producer
@@ -40,6 +44,16 @@ type ProducerConfig struct {
 
 	// QueueConfig allows overriding the default queue configuration (optional)
 	QueueConfig *queue.Config
+
+	// Options enables publisher confirms and mandatory returns (optional)
+	Options queue.ProducerOptions
+
+	// RouteByType publishes each event with its type as routing key, so consumers with
+	// RouteByType only receive the types they handle
+	RouteByType bool
+
+	// Schemas validates payloads before publishing and sets the event version (optional)
+	Schemas *queue.SchemaRegistry
 }
 
 // NewProducer creates a new event producer
@@ -53,14 +67,16 @@ func NewProducer(connConfig queue.ConnectionConfig, config ProducerConfig) (*Pro
 		queueConfig = config.QueueConfig
 	}
 
-	producer, err := queue.NewProducer(connConfig, queueConfig)
+	producer, err := queue.NewProducerWithOptions(connConfig, queueConfig, config.Options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create event producer: %w", err)
 	}
 
 	return &Producer{
-		producer: producer,
-		service:  config.ServiceName,
+		producer:    producer,
+		service:     config.ServiceName,
+		routeByType: config.RouteByType,
+		schemas:     config.Schemas,
 	}, nil
 }
 
@@ -75,18 +91,42 @@ func (p *Producer) Publish(ctx context.Context, eventType string, payload map[st
 		payload["timestamp"] = time.Now().UTC().Format(time.RFC3339)
 	}
 
+	data, err := marshalEvent(p.schemas, p.service, eventType, payload)
+	if err != nil {
+		return err
+	}
+
+	return p.publishData(ctx, eventType, data, nil)
+}
+
+// marshalEvent builds and marshals an event, validating its payload against schemas (optional)
+func marshalEvent(schemas *queue.SchemaRegistry, service, eventType string, payload map[string]any) ([]byte, error) {
 	event := Event{
-		Service: p.service,
+		Service: service,
 		Type:    eventType,
 		Payload: payload,
 	}
 
+	if schemas != nil {
+		event.Version = schemas.LatestVersion(eventType)
+		if err := schemas.Validate(eventType, event.Version, payload); err != nil {
+			return nil, err
+		}
+	}
+
 	data, err := json.Marshal(event)
 	if err != nil {
-		return fmt.Errorf("failed to marshal event: %w", err)
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
 	}
+	return data, nil
+}
 
-	return p.producer.Publish(ctx, data, nil)
+// publishData publishes an already marshaled event
+func (p *Producer) publishData(ctx context.Context, eventType string, data []byte, headers amqp.Table) error {
+	if p.routeByType {
+		return p.producer.PublishWithRoutingKey(ctx, data, headers, eventType)
+	}
+	return p.producer.Publish(ctx, data, headers)
 }
 
 // PublishAsync publishes an event asynchronously (fire and forget)
@@ -99,6 +139,11 @@ func (p *Producer) PublishAsync(eventType string, payload map[string]any) {
 	}()
 }
 
+// Use wraps publishing with middlewares (e.g. header injection)
+func (p *Producer) Use(middlewares ...queue.PublishMiddleware) {
+	p.producer.Use(middlewares...)
+}
+
 // IsConnected returns true if the producer has a valid connection
 func (p *Producer) IsConnected() bool {
 	return p.producer.IsConnected()
@@ -0,0 +1,206 @@
+// Package kafka implements queue.Broker on Apache Kafka. It is a separate module so
+// users of the queue package don't pull in the Kafka client.
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/queue"
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+// Config holds the Kafka broker configuration
+type Config struct {
+	Brokers []string // e.g. []string{"localhost:9092"}
+	GroupID string   // Consumer group of the service; required for Subscribe
+	// DeadLetterTopic receives messages that still fail after all retries
+	// (empty = they are logged and skipped)
+	DeadLetterTopic string
+}
+
+// Broker is a queue.Broker on Kafka. Topics are Kafka topics (no wildcards) and message
+// keys are Kafka keys, so messages with the same key stay ordered in one partition.
+// Kafka has no per-message redelivery: a failed handler is retried in place with
+// RetryConfig backoff, which blocks its partition, and the offset is committed once
+// the message succeeded or was dead-lettered.
+type Broker struct {
+	config      Config
+	retryConfig queue.RetryConfig
+	writer      *kafkago.Writer
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	readers []*kafkago.Reader
+}
+
+// NewBroker creates a Kafka-backed Broker
+func NewBroker(config Config, retryConfig queue.RetryConfig) (*Broker, error) {
+	if len(config.Brokers) == 0 {
+		return nil, fmt.Errorf("at least one broker address is required")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Broker{
+		config:      config,
+		retryConfig: retryConfig,
+		writer: &kafkago.Writer{
+			Addr:         kafkago.TCP(config.Brokers...),
+			Balancer:     &kafkago.Hash{},
+			RequiredAcks: kafkago.RequireAll,
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+// Publish writes msg to its topic and waits for all in-sync replicas to acknowledge it
+func (b *Broker) Publish(ctx context.Context, msg queue.Message) error {
+	if err := b.writer.WriteMessages(ctx, toKafkaMessage(msg.Topic, msg)); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe joins the consumer group on the topic and starts consuming it
+func (b *Broker) Subscribe(_ context.Context, topic string, handler queue.BrokerHandler) error {
+	if b.config.GroupID == "" {
+		return fmt.Errorf("group ID is required to subscribe")
+	}
+	if strings.ContainsAny(topic, "*#") {
+		return fmt.Errorf("topic %s: Kafka does not support wildcards", topic)
+	}
+
+	reader := kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers: b.config.Brokers,
+		GroupID: b.config.GroupID,
+		Topic:   topic,
+	})
+
+	b.mu.Lock()
+	b.readers = append(b.readers, reader)
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.consume(reader, handler)
+	}()
+	return nil
+}
+
+// consume fetches, handles and commits messages until the broker is closed
+func (b *Broker) consume(reader *kafkago.Reader, handler queue.BrokerHandler) {
+	for {
+		m, err := reader.FetchMessage(b.ctx)
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			queue.Logf("Failed to fetch message: %v", err)
+			select {
+			case <-b.ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		if err := b.handle(m, handler); err != nil {
+			if b.ctx.Err() != nil {
+				return // Not committed; redelivered to the group after a restart
+			}
+			b.deadLetter(m, err)
+		}
+		if err := reader.CommitMessages(b.ctx, m); err != nil && b.ctx.Err() == nil {
+			queue.Logf("Failed to commit message on %s: %v", m.Topic, err)
+		}
+	}
+}
+
+// handle runs the handler, retrying retryable errors with backoff up to MaxRetries times
+func (b *Broker) handle(m kafkago.Message, handler queue.BrokerHandler) error {
+	msg := toMessage(m)
+	retryConfig := b.retryConfig.ForHeaders(msg.Headers)
+	for retryCount := 0; ; retryCount++ {
+		err := handler(b.ctx, msg)
+		if err == nil {
+			return nil
+		}
+		if !retryConfig.Retryable(err) || retryCount >= retryConfig.MaxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(queue.CalculateRetryDelay(retryCount, retryConfig))
+		select {
+		case <-b.ctx.Done():
+			timer.Stop()
+			return b.ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// deadLetter publishes a message that failed for good to the dead letter topic
+func (b *Broker) deadLetter(m kafkago.Message, cause error) {
+	if b.config.DeadLetterTopic == "" {
+		queue.Logf("Message on %s failed after all retries, skipping: %v", m.Topic, cause)
+		return
+	}
+
+	msg := toMessage(m)
+	msg.Headers["x-original-topic"] = m.Topic
+	msg.Headers["x-error"] = cause.Error()
+	if err := b.writer.WriteMessages(b.ctx, toKafkaMessage(b.config.DeadLetterTopic, msg)); err != nil {
+		queue.Logf("Failed to dead-letter message on %s: %v (handler error: %v)", m.Topic, err, cause)
+	}
+}
+
+// Close stops all subscriptions and closes the readers and the writer, returning the first error
+func (b *Broker) Close() error {
+	b.cancel()
+	b.wg.Wait()
+
+	b.mu.Lock()
+	readers := b.readers
+	b.readers = nil
+	b.mu.Unlock()
+
+	var firstErr error
+	for _, reader := range readers {
+		if err := reader.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if err := b.writer.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
+
+// toKafkaMessage converts a broker-neutral message to a Kafka message on topic
+func toKafkaMessage(topic string, msg queue.Message) kafkago.Message {
+	m := kafkago.Message{Topic: topic, Value: msg.Body}
+	if msg.Key != "" {
+		m.Key = []byte(msg.Key) // Keyless messages are spread round-robin
+	}
+	for k, v := range msg.Headers {
+		m.Headers = append(m.Headers, kafkago.Header{Key: k, Value: []byte(v)})
+	}
+	return m
+}
+
+// toMessage converts a Kafka message to a broker-neutral message
+func toMessage(m kafkago.Message) queue.Message {
+	msg := queue.Message{Topic: m.Topic, Key: string(m.Key), Body: m.Value, Headers: make(map[string]string, len(m.Headers))}
+	for _, h := range m.Headers {
+		msg.Headers[h.Key] = string(h.Value)
+	}
+	return msg
+}
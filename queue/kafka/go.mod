@@ -0,0 +1,18 @@
+module github.com/kerimovok/go-pkg-utils/queue/kafka
+
+go 1.24.5
+
+require (
+	github.com/kerimovok/go-pkg-utils v0.0.0
+	github.com/segmentio/kafka-go v0.4.47
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.18.2 // indirect
+	github.com/pierrec/lz4/v4 v4.1.15 // indirect
+	github.com/rabbitmq/amqp091-go v1.10.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/kerimovok/go-pkg-utils => ../..
@@ -0,0 +1,35 @@
+package queue
+
+import (
+	"log"
+	"sync"
+)
+
+// Logger receives the queue packages' log output (connection state, retries, handled
+// messages). *log.Logger implements it; logger.NewPrintfLogger adapts a zap logger.
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+var (
+	loggerMu sync.RWMutex
+	logger   Logger = log.Default()
+)
+
+// SetLogger routes the queue packages' log output to l (nil restores the standard logger)
+func SetLogger(l Logger) {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+	if l == nil {
+		l = log.Default()
+	}
+	logger = l
+}
+
+// Logf writes to the logger set with SetLogger; used by the queue subpackages
+func Logf(format string, args ...interface{}) {
+	loggerMu.RLock()
+	l := logger
+	loggerMu.RUnlock()
+	l.Printf(format, args...)
+}
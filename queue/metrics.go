@@ -0,0 +1,55 @@
+package queue
+
+import "time"
+
+// Dead-letter reasons reported to Metrics.DeadLettered
+const (
+	DeadLetterMaxRetries = "max_retries"
+	DeadLetterPermanent  = "permanent"
+	DeadLetterPanic      = "panic"
+)
+
+// Metrics receives producer and consumer events, e.g. to update Prometheus counters and
+// histograms. Implementations must be safe for concurrent use; embed NopMetrics to
+// implement only some of the methods.
+type Metrics interface {
+	// Published is called after every publish attempt (err is nil on success)
+	Published(exchange, routingKey string, err error)
+	// Confirmed is called when the broker acks or nacks a message in confirm mode
+	Confirmed(exchange string, acked bool)
+	// Consumed is called when a delivery is handed to a worker
+	Consumed(queue string)
+	// HandlerDuration is called after the handler returns
+	HandlerDuration(queue string, duration time.Duration, err error)
+	// Acked is called when a delivery is acknowledged
+	Acked(queue string)
+	// Nacked is called when a delivery is rejected or requeued
+	Nacked(queue string, requeued bool)
+	// Retried is called when a failed delivery is scheduled for retry attempt (1-based)
+	Retried(queue string, attempt int)
+	// DeadLettered is called when a delivery is rejected to the dead letter queue
+	DeadLettered(queue string, reason string)
+	// Reconnected is called after a producer or consumer ("producer"/"consumer") reconnects
+	Reconnected(component string)
+}
+
+// NopMetrics is a Metrics implementation that ignores all events
+type NopMetrics struct{}
+
+func (NopMetrics) Published(exchange, routingKey string, err error)                {}
+func (NopMetrics) Confirmed(exchange string, acked bool)                           {}
+func (NopMetrics) Consumed(queue string)                                           {}
+func (NopMetrics) HandlerDuration(queue string, duration time.Duration, err error) {}
+func (NopMetrics) Acked(queue string)                                              {}
+func (NopMetrics) Nacked(queue string, requeued bool)                              {}
+func (NopMetrics) Retried(queue string, attempt int)                               {}
+func (NopMetrics) DeadLettered(queue string, reason string)                        {}
+func (NopMetrics) Reconnected(component string)                                    {}
+
+// metricsOrNop returns m, or NopMetrics if m is nil
+func metricsOrNop(m Metrics) Metrics {
+	if m == nil {
+		return NopMetrics{}
+	}
+	return m
+}
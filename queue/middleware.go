@@ -0,0 +1,95 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/errors"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Middleware wraps message handling on consume (logging, metrics, recovery, tracing, ...)
+type Middleware func(next MessageHandler) MessageHandler
+
+// Publication is a message about to be published; publish middlewares may modify it
+type Publication struct {
+	Exchange   string
+	RoutingKey string
+	Publishing amqp.Publishing // Headers is never nil
+}
+
+// PublishHandler publishes a message
+type PublishHandler func(ctx context.Context, pub *Publication) error
+
+// PublishMiddleware wraps publishing (header injection, logging, metrics, ...)
+type PublishMiddleware func(next PublishHandler) PublishHandler
+
+// Use wraps the consumer's handler with middlewares; the first middleware is the outermost.
+// Call it before StartConsuming.
+func (c *Consumer) Use(middlewares ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		c.handler = middlewares[i](c.handler)
+	}
+}
+
+// Use wraps publishing with middlewares; the first middleware is the outermost
+func (p *Producer) Use(middlewares ...PublishMiddleware) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		p.publish = middlewares[i](p.publish)
+	}
+}
+
+// RecoverMiddleware turns handler panics into errors via errorHandler (nil = no logging or
+// component). A panicking message is treated as permanently failed and sent to the DLQ.
+func RecoverMiddleware(errorHandler *errors.ErrorHandler) Middleware {
+	if errorHandler == nil {
+		errorHandler = errors.NewErrorHandler("", nil)
+	}
+	return func(next MessageHandler) MessageHandler {
+		return func(msg amqp.Delivery) error {
+			err := errorHandler.SafeExecute(func() error {
+				return next(msg)
+			})
+			if errors.IsCode(err, "PANIC") {
+				return Permanent(err)
+			}
+			return err
+		}
+	}
+}
+
+// LoggingMiddleware logs each handled message with its routing key, duration and outcome
+func LoggingMiddleware() Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return func(msg amqp.Delivery) error {
+			start := time.Now()
+			err := next(msg)
+			correlationID, _ := msg.Headers[HeaderCorrelationID].(string)
+			if err != nil {
+				Logf("Handled message %s (%s, correlation %s) in %v: %v", MessageID(msg), msg.RoutingKey, correlationID, time.Since(start), err)
+			} else {
+				Logf("Handled message %s (%s, correlation %s) in %v", MessageID(msg), msg.RoutingKey, correlationID, time.Since(start))
+			}
+			return err
+		}
+	}
+}
+
+// HeaderMiddleware adds the headers returned by fn to every published message,
+// keeping headers already set by the caller
+func HeaderMiddleware(fn func(ctx context.Context) amqp.Table) PublishMiddleware {
+	return func(next PublishHandler) PublishHandler {
+		return func(ctx context.Context, pub *Publication) error {
+			for k, v := range fn(ctx) {
+				if _, exists := pub.Publishing.Headers[k]; !exists {
+					pub.Publishing.Headers[k] = v
+				}
+			}
+			return next(ctx, pub)
+		}
+	}
+}
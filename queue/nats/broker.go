@@ -0,0 +1,210 @@
+// Package nats implements queue.Broker on NATS JetStream. It is a separate module so
+// users of the queue package don't pull in the NATS client.
+package nats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kerimovok/go-pkg-utils/queue"
+	natsgo "github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Config holds the JetStream broker configuration
+type Config struct {
+	URL      string          // e.g. "nats://localhost:4222"
+	Options  []natsgo.Option // Connection options (credentials, TLS, ...)
+	Stream   string          // JetStream stream holding the topics
+	Subjects []string        // If set, the stream is created or updated with these subjects (e.g. "orders.>")
+	Durable  string          // Prefix of the durable consumer names - default: Stream
+	AckWait  time.Duration   // How long a delivery may take before it is redelivered - default: 30s
+}
+
+// Broker is a queue.Broker on NATS JetStream. Topics are subjects; AMQP-style "#"
+// wildcards are translated to ">". Each subscription is a durable consumer, so
+// instances subscribing to the same topic share its messages. Failed messages are
+// redelivered with RetryConfig backoff up to MaxRetries times, then terminated.
+type Broker struct {
+	conn        *natsgo.Conn
+	js          jetstream.JetStream
+	stream      jetstream.Stream
+	config      Config
+	retryConfig queue.RetryConfig
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	consumes []jetstream.ConsumeContext
+}
+
+// NewBroker connects to NATS and looks up (or creates) the stream
+func NewBroker(ctx context.Context, config Config, retryConfig queue.RetryConfig) (*Broker, error) {
+	if config.Stream == "" {
+		return nil, fmt.Errorf("stream is required")
+	}
+	if config.Durable == "" {
+		config.Durable = config.Stream
+	}
+	if config.AckWait <= 0 {
+		config.AckWait = 30 * time.Second
+	}
+
+	conn, err := natsgo.Connect(config.URL, config.Options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	var stream jetstream.Stream
+	if len(config.Subjects) > 0 {
+		stream, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{Name: config.Stream, Subjects: config.Subjects})
+	} else {
+		stream, err = js.Stream(ctx, config.Stream)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to set up stream %s: %w", config.Stream, err)
+	}
+
+	brokerCtx, cancel := context.WithCancel(context.Background())
+	return &Broker{
+		conn:        conn,
+		js:          js,
+		stream:      stream,
+		config:      config,
+		retryConfig: retryConfig,
+		ctx:         brokerCtx,
+		cancel:      cancel,
+	}, nil
+}
+
+// Publish publishes msg to its topic and waits for the stream to acknowledge it
+func (b *Broker) Publish(ctx context.Context, msg queue.Message) error {
+	natsMsg := &natsgo.Msg{Subject: msg.Topic, Data: msg.Body, Header: natsgo.Header{}}
+	for k, v := range msg.Headers {
+		natsMsg.Header.Set(k, v)
+	}
+	if msg.Key != "" {
+		natsMsg.Header.Set(queue.HeaderPartitionKey, msg.Key)
+	}
+	if _, err := b.js.PublishMsg(ctx, natsMsg); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// Subscribe creates (or resumes) the durable consumer of the topic and starts consuming it
+func (b *Broker) Subscribe(ctx context.Context, topic string, handler queue.BrokerHandler) error {
+	subject, err := Subject(topic)
+	if err != nil {
+		return err
+	}
+
+	consumer, err := b.stream.CreateOrUpdateConsumer(ctx, jetstream.ConsumerConfig{
+		Durable:       b.config.Durable + "_" + consumerName(subject),
+		FilterSubject: subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       b.config.AckWait,
+		// No MaxDeliver: handle terminates messages past their (per-message) retry budget
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	consume, err := consumer.Consume(func(m jetstream.Msg) {
+		b.handle(m, handler)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+
+	b.mu.Lock()
+	b.consumes = append(b.consumes, consume)
+	b.mu.Unlock()
+	return nil
+}
+
+// handle runs the handler and acknowledges, redelivers or terminates the message
+func (b *Broker) handle(m jetstream.Msg, handler queue.BrokerHandler) {
+	msg := toMessage(m)
+	err := handler(b.ctx, msg)
+	if err == nil {
+		if ackErr := m.Ack(); ackErr != nil {
+			queue.Logf("Failed to ack message on %s: %v", m.Subject(), ackErr)
+		}
+		return
+	}
+
+	retryCount := 0
+	if meta, metaErr := m.Metadata(); metaErr == nil {
+		retryCount = int(meta.NumDelivered) - 1
+	}
+	retryConfig := b.retryConfig.ForHeaders(msg.Headers)
+	if !retryConfig.Retryable(err) || retryCount >= retryConfig.MaxRetries {
+		queue.Logf("Message on %s failed after %d retries, terminating: %v", m.Subject(), retryCount, err)
+		if termErr := m.Term(); termErr != nil {
+			queue.Logf("Failed to terminate message on %s: %v", m.Subject(), termErr)
+		}
+		return
+	}
+	if nakErr := m.NakWithDelay(queue.CalculateRetryDelay(retryCount, retryConfig)); nakErr != nil {
+		queue.Logf("Failed to nak message on %s: %v", m.Subject(), nakErr)
+	}
+}
+
+// Close stops all subscriptions and drains the connection
+func (b *Broker) Close() error {
+	b.mu.Lock()
+	consumes := b.consumes
+	b.consumes = nil
+	b.mu.Unlock()
+
+	for _, consume := range consumes {
+		consume.Stop()
+	}
+	b.cancel()
+	return b.conn.Drain()
+}
+
+// Subject translates an AMQP-style topic pattern to a NATS subject: "#" becomes ">",
+// which NATS only supports as the last token
+func Subject(topic string) (string, error) {
+	tokens := strings.Split(topic, ".")
+	for i, token := range tokens {
+		if token != "#" {
+			continue
+		}
+		if i != len(tokens)-1 {
+			return "", fmt.Errorf("topic %s: NATS only supports '#' as the last word", topic)
+		}
+		tokens[i] = ">"
+	}
+	return strings.Join(tokens, "."), nil
+}
+
+// consumerName turns a subject into a valid durable name (no '.', '*' or '>')
+func consumerName(subject string) string {
+	return strings.NewReplacer(".", "_", "*", "any", ">", "all").Replace(subject)
+}
+
+// toMessage converts a JetStream message to a broker-neutral message
+func toMessage(m jetstream.Msg) queue.Message {
+	msg := queue.Message{Topic: m.Subject(), Body: m.Data(), Headers: make(map[string]string)}
+	for k := range m.Headers() {
+		if k == queue.HeaderPartitionKey {
+			msg.Key = m.Headers().Get(k)
+			continue
+		}
+		msg.Headers[k] = m.Headers().Get(k)
+	}
+	return msg
+}
@@ -0,0 +1,22 @@
+module github.com/kerimovok/go-pkg-utils/queue/nats
+
+go 1.24.5
+
+require (
+	github.com/kerimovok/go-pkg-utils v0.0.0
+	github.com/nats-io/nats.go v1.37.0
+)
+
+require (
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/klauspost/compress v1.18.2 // indirect
+	github.com/nats-io/nkeys v0.4.7 // indirect
+	github.com/nats-io/nuid v1.0.1 // indirect
+	github.com/rabbitmq/amqp091-go v1.10.0 // indirect
+	golang.org/x/crypto v0.47.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/text v0.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/kerimovok/go-pkg-utils => ../..
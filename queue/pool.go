@@ -0,0 +1,124 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ChannelPool is a fixed-size pool of AMQP channels on one connection. Channels are not
+// safe for concurrent publishing, so each publish acquires its own channel; closed
+// channels are replaced on acquisition.
+type ChannelPool struct {
+	conn     *amqp.Connection
+	setup    func(*amqp.Channel) error
+	channels chan *amqp.Channel // nil entries are slots whose channel must be (re)opened
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// NewChannelPool opens size channels on conn, running setup (optional) on each new channel
+func NewChannelPool(conn *amqp.Connection, size int, setup func(*amqp.Channel) error) (*ChannelPool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("channel pool size must be positive")
+	}
+
+	pool := &ChannelPool{
+		conn:     conn,
+		setup:    setup,
+		channels: make(chan *amqp.Channel, size),
+	}
+	for i := 0; i < size; i++ {
+		ch, err := pool.open()
+		if err != nil {
+			pool.Close()
+			return nil, err
+		}
+		pool.channels <- ch
+	}
+	return pool, nil
+}
+
+// Acquire takes a healthy channel from the pool, waiting until one is free or ctx is done.
+// The channel must be returned with Release.
+func (p *ChannelPool) Acquire(ctx context.Context) (*amqp.Channel, error) {
+	var ch *amqp.Channel
+	select {
+	case ch = <-p.channels:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("failed to acquire channel: %w", ctx.Err())
+	}
+
+	if p.isClosed() {
+		p.Release(ch)
+		return nil, fmt.Errorf("channel pool is closed")
+	}
+
+	if ch == nil || ch.IsClosed() {
+		var err error
+		if ch, err = p.open(); err != nil {
+			p.channels <- nil // Keep the slot so a later acquire can retry
+			return nil, err
+		}
+	}
+	return ch, nil
+}
+
+// Release returns a channel to the pool
+func (p *ChannelPool) Release(ch *amqp.Channel) {
+	if p.isClosed() && ch != nil {
+		ch.Close()
+		ch = nil
+	}
+	p.channels <- ch
+}
+
+// Close closes all idle channels; channels in use are closed when released
+func (p *ChannelPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	var firstErr error
+	for {
+		select {
+		case ch := <-p.channels:
+			if ch != nil && !ch.IsClosed() {
+				if err := ch.Close(); err != nil && firstErr == nil {
+					firstErr = err
+				}
+			}
+			defer func() { p.channels <- nil }() // Keep the slot count so Release never blocks
+		default:
+			return firstErr
+		}
+	}
+}
+
+// open opens and sets up a new channel
+func (p *ChannelPool) open() (*amqp.Channel, error) {
+	ch, err := p.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	if p.setup != nil {
+		if err := p.setup(ch); err != nil {
+			ch.Close()
+			return nil, err
+		}
+	}
+	return ch, nil
+}
+
+func (p *ChannelPool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
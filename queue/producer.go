@@ -3,35 +3,61 @@ package queue
 import (
 	"context"
 	"fmt"
-	"log"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// ProducerOptions controls delivery guarantees of published messages
+type ProducerOptions struct {
+	// Confirm enables publisher confirms: Publish waits until the broker acknowledges the message
+	Confirm bool
+	// ConfirmTimeout bounds the wait for a broker acknowledgement - defaults to 5 seconds
+	ConfirmTimeout time.Duration
+	// Mandatory asks the broker to return messages that cannot be routed to any queue
+	Mandatory bool
+	// OnReturn is called for every returned (unroutable) message when Mandatory is set
+	OnReturn func(ret amqp.Return)
+	// Metrics receives publish, confirm and reconnect events (optional)
+	Metrics Metrics
+	// ChannelPoolSize enables a pool of publishing channels so concurrent Publish calls
+	// don't share one channel - 0 publishes on a single channel
+	ChannelPoolSize int
+}
+
+// getConfirmTimeout returns the confirm timeout, defaulting to 5 seconds if not set
+func (o ProducerOptions) getConfirmTimeout() time.Duration {
+	if o.ConfirmTimeout <= 0 {
+		return 5 * time.Second
+	}
+	return o.ConfirmTimeout
+}
+
 // Producer is a RabbitMQ producer with automatic reconnection
 type Producer struct {
 	conn       *amqp.Connection
 	channel    *amqp.Channel
+	pool       *ChannelPool // Publishing channels when ChannelPoolSize is set
 	mu         sync.RWMutex
 	config     *Config
 	connConfig ConnectionConfig
+	options    ProducerOptions
+	publish    PublishHandler // publishMessage wrapped by middlewares
 }
 
 // NewProducer creates a new RabbitMQ producer with automatic reconnection
 func NewProducer(connConfig ConnectionConfig, queueConfig *Config) (*Producer, error) {
-	url := fmt.Sprintf("amqp://%s:%s@%s:%s/%s",
-		connConfig.Username,
-		connConfig.Password,
-		connConfig.Host,
-		connConfig.Port,
-		connConfig.VHost,
-	)
-
-	conn, err := amqp.Dial(url)
+	return NewProducerWithOptions(connConfig, queueConfig, ProducerOptions{})
+}
+
+// NewProducerWithOptions creates a new RabbitMQ producer with publisher confirms
+// and/or mandatory returns as configured by options
+func NewProducerWithOptions(connConfig ConnectionConfig, queueConfig *Config, options ProducerOptions) (*Producer, error) {
+	conn, err := connConfig.Dial()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
+		return nil, err
 	}
 
 	ch, err := conn.Channel()
@@ -52,6 +78,23 @@ func NewProducer(connConfig ConnectionConfig, queueConfig *Config) (*Producer, e
 		channel:    ch,
 		config:     queueConfig,
 		connConfig: connConfig,
+		options:    options,
+	}
+	producer.options.Metrics = metricsOrNop(options.Metrics)
+	producer.publish = producer.publishMessage
+
+	if err := producer.setupChannel(ch); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, err
+	}
+
+	if options.ChannelPoolSize > 0 {
+		if producer.pool, err = NewChannelPool(conn, options.ChannelPoolSize, producer.setupChannel); err != nil {
+			ch.Close()
+			conn.Close()
+			return nil, err
+		}
 	}
 
 	producer.setupConnectionRecovery()
@@ -66,15 +109,6 @@ func (p *Producer) Publish(ctx context.Context, body []byte, headers amqp.Table)
 
 // PublishWithRoutingKey publishes a message to the queue with a custom routing key
 func (p *Producer) PublishWithRoutingKey(ctx context.Context, body []byte, headers amqp.Table, routingKey string) error {
-	// Check connection health before publishing
-	p.mu.RLock()
-	if p.conn == nil || p.conn.IsClosed() || p.channel == nil || p.channel.IsClosed() {
-		p.mu.RUnlock()
-		return fmt.Errorf("RabbitMQ connection is not available")
-	}
-	channel := p.channel
-	p.mu.RUnlock()
-
 	// Use context with timeout if not provided
 	if ctx == nil {
 		var cancel context.CancelFunc
@@ -82,25 +116,125 @@ func (p *Producer) PublishWithRoutingKey(ctx context.Context, body []byte, heade
 		defer cancel()
 	}
 
-	err := channel.PublishWithContext(ctx,
-		p.config.ExchangeName, // exchange
-		routingKey,            // routing key (custom)
-		false,                 // mandatory
-		false,                 // immediate
-		amqp.Publishing{
+	// Copy headers so middlewares can inject their own without touching the caller's table
+	msgHeaders := amqp.Table{}
+	for k, v := range headers {
+		msgHeaders[k] = v
+	}
+
+	// Give every message an ID for deduplication, keeping one set by the caller
+	messageID, _ := msgHeaders[HeaderMessageID].(string)
+	if messageID == "" {
+		messageID = uuid.NewString()
+		msgHeaders[HeaderMessageID] = messageID
+	}
+	setCorrelationHeaders(ctx, msgHeaders, messageID)
+
+	p.mu.RLock()
+	publish := p.publish
+	p.mu.RUnlock()
+
+	return publish(ctx, &Publication{
+		Exchange:   p.config.ExchangeName,
+		RoutingKey: routingKey,
+		Publishing: amqp.Publishing{
 			ContentType:  "application/json",
+			MessageId:    messageID,
 			Body:         body,
-			Headers:      headers,
+			Headers:      msgHeaders,
 			DeliveryMode: amqp.Persistent, // Make messages persistent
-		})
+		},
+	})
+}
+
+// publishMessage publishes a message on the current channel, waiting for a broker confirm if enabled
+func (p *Producer) publishMessage(ctx context.Context, pub *Publication) error {
+	err := p.publishOnChannel(ctx, pub)
+	p.options.Metrics.Published(pub.Exchange, pub.RoutingKey, err)
+	return err
+}
+
+// publishOnChannel performs the AMQP publish and confirm wait
+func (p *Producer) publishOnChannel(ctx context.Context, pub *Publication) error {
+	// Check connection health before publishing
+	p.mu.RLock()
+	if p.conn == nil || p.conn.IsClosed() || p.channel == nil || p.channel.IsClosed() {
+		p.mu.RUnlock()
+		return fmt.Errorf("RabbitMQ connection is not available")
+	}
+	channel, pool := p.channel, p.pool
+	p.mu.RUnlock()
+
+	if pool != nil {
+		pooled, err := pool.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer pool.Release(pooled)
+		channel = pooled
+	}
 
+	if !p.options.Confirm {
+		err := channel.PublishWithContext(ctx,
+			pub.Exchange,        // exchange
+			pub.RoutingKey,      // routing key
+			p.options.Mandatory, // mandatory
+			false,               // immediate
+			pub.Publishing)
+		if err != nil {
+			return fmt.Errorf("failed to publish message: %w", err)
+		}
+		return nil
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx,
+		pub.Exchange,        // exchange
+		pub.RoutingKey,      // routing key
+		p.options.Mandatory, // mandatory
+		false,               // immediate
+		pub.Publishing)
 	if err != nil {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
+	confirmCtx, cancel := context.WithTimeout(ctx, p.options.getConfirmTimeout())
+	defer cancel()
+
+	acked, err := confirmation.WaitContext(confirmCtx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm message: %w", err)
+	}
+	p.options.Metrics.Confirmed(pub.Exchange, acked)
+	if !acked {
+		return fmt.Errorf("message was not acknowledged by the broker")
+	}
+	return nil
+}
+
+// setupChannel enables confirm mode and return notifications on a (new) channel
+func (p *Producer) setupChannel(ch *amqp.Channel) error {
+	if p.options.Confirm {
+		if err := ch.Confirm(false); err != nil {
+			return fmt.Errorf("failed to enable publisher confirms: %w", err)
+		}
+	}
+
+	if p.options.Mandatory && p.options.OnReturn != nil {
+		returns := ch.NotifyReturn(make(chan amqp.Return, 1))
+		go func() {
+			for ret := range returns {
+				p.options.OnReturn(ret)
+			}
+		}()
+	}
 	return nil
 }
 
+// ConfirmsEnabled reports whether Publish waits for broker acknowledgements
+func (p *Producer) ConfirmsEnabled() bool {
+	return p.options.Confirm
+}
+
 // IsConnected returns true if the producer has a valid connection
 func (p *Producer) IsConnected() bool {
 	p.mu.RLock()
@@ -113,6 +247,9 @@ func (p *Producer) Close() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.pool != nil {
+		p.pool.Close()
+	}
 	if p.channel != nil {
 		if err := p.channel.Close(); err != nil {
 			return err
@@ -129,7 +266,7 @@ func (p *Producer) setupConnectionRecovery() {
 	go func() {
 		for err := range p.conn.NotifyClose(make(chan *amqp.Error)) {
 			if err != nil {
-				log.Printf("RabbitMQ connection lost: %v, attempting to reconnect...", err)
+				Logf("RabbitMQ connection lost: %v, attempting to reconnect...", err)
 				p.reconnect()
 			}
 		}
@@ -138,7 +275,7 @@ func (p *Producer) setupConnectionRecovery() {
 	go func() {
 		for err := range p.channel.NotifyClose(make(chan *amqp.Error)) {
 			if err != nil {
-				log.Printf("RabbitMQ channel lost: %v, attempting to reconnect...", err)
+				Logf("RabbitMQ channel lost: %v, attempting to reconnect...", err)
 				p.reconnect()
 			}
 		}
@@ -148,9 +285,12 @@ func (p *Producer) setupConnectionRecovery() {
 // reconnect attempts to reconnect to RabbitMQ
 func (p *Producer) reconnect() {
 	for {
-		log.Println("Attempting to reconnect to RabbitMQ...")
+		Logf("Attempting to reconnect to RabbitMQ...")
 
 		p.mu.Lock()
+		if p.pool != nil {
+			p.pool.Close()
+		}
 		if p.channel != nil {
 			p.channel.Close()
 		}
@@ -161,40 +301,51 @@ func (p *Producer) reconnect() {
 
 		time.Sleep(5 * time.Second)
 
-		url := fmt.Sprintf("amqp://%s:%s@%s:%s/%s",
-			p.connConfig.Username,
-			p.connConfig.Password,
-			p.connConfig.Host,
-			p.connConfig.Port,
-			p.connConfig.VHost,
-		)
-
-		conn, err := amqp.Dial(url)
+		conn, err := p.connConfig.Dial()
 		if err != nil {
-			log.Printf("Failed to reconnect: %v, retrying in 5 seconds...", err)
+			Logf("Failed to reconnect: %v, retrying in 5 seconds...", err)
 			continue
 		}
 
 		ch, err := conn.Channel()
 		if err != nil {
-			log.Printf("Failed to create channel: %v, retrying in 5 seconds...", err)
+			Logf("Failed to create channel: %v, retrying in 5 seconds...", err)
 			conn.Close()
 			continue
 		}
 
 		if err := p.config.SetupAllQueues(ch); err != nil {
-			log.Printf("Failed to setup queues: %v, retrying in 5 seconds...", err)
+			Logf("Failed to setup queues: %v, retrying in 5 seconds...", err)
 			ch.Close()
 			conn.Close()
 			continue
 		}
 
+		if err := p.setupChannel(ch); err != nil {
+			Logf("Failed to setup channel: %v, retrying in 5 seconds...", err)
+			ch.Close()
+			conn.Close()
+			continue
+		}
+
+		var pool *ChannelPool
+		if p.options.ChannelPoolSize > 0 {
+			if pool, err = NewChannelPool(conn, p.options.ChannelPoolSize, p.setupChannel); err != nil {
+				Logf("Failed to create channel pool: %v, retrying in 5 seconds...", err)
+				ch.Close()
+				conn.Close()
+				continue
+			}
+		}
+
 		p.mu.Lock()
 		p.conn = conn
 		p.channel = ch
+		p.pool = pool
 		p.mu.Unlock()
 
-		log.Println("Successfully reconnected to RabbitMQ")
+		Logf("Successfully reconnected to RabbitMQ")
+		p.options.Metrics.Reconnected("producer")
 		break
 	}
 }
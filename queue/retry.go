@@ -1,38 +1,147 @@
 package queue
 
 import (
-	"log"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
 	"time"
 
+	pkgerrors "github.com/kerimovok/go-pkg-utils/errors"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// Per-message retry overrides, set by producers on messages that need a different policy
+const (
+	HeaderMaxRetries     = "x-max-retries"      // Overrides RetryConfig.MaxRetries
+	HeaderRetryDelayBase = "x-retry-delay-base" // Overrides RetryConfig.RetryDelayBase (seconds)
+)
+
+// HeaderOriginalRoutingKey keeps the routing key of the first delivery on retried messages,
+// which come back from the retry queues routed by queue name
+const HeaderOriginalRoutingKey = "x-original-routing-key"
+
+// OriginalRoutingKey returns the routing key a delivery was first published with
+func OriginalRoutingKey(msg amqp.Delivery) string {
+	if key, ok := msg.Headers[HeaderOriginalRoutingKey].(string); ok {
+		return key
+	}
+	return msg.RoutingKey
+}
+
+// JitterStrategy randomizes retry delays so failed messages don't retry in lockstep
+type JitterStrategy string
+
+const (
+	JitterNone  JitterStrategy = ""      // Exact exponential backoff
+	JitterFull  JitterStrategy = "full"  // Random delay in [0, backoff]
+	JitterEqual JitterStrategy = "equal" // backoff/2 plus a random delay in [0, backoff/2]
+)
+
 // RetryConfig holds retry configuration
 type RetryConfig struct {
 	MaxRetries     int
 	RetryDelayBase int
 	MaxRetryDelay  int
+	Jitter         JitterStrategy
+	// IsRetryable classifies handler errors; non-retryable errors go straight to the DLQ.
+	// Defaults to DefaultRetryClassifier.
+	IsRetryable func(err error) bool
+}
+
+// DefaultRetryClassifier retries every error except PermanentError and *errors.Error
+// values that are not marked retryable (see errors.IsRetryable)
+func DefaultRetryClassifier(err error) bool {
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+	var pkgErr *pkgerrors.Error
+	if errors.As(err, &pkgErr) {
+		return pkgerrors.IsRetryable(pkgErr)
+	}
+	return true
+}
+
+// Retryable classifies err with the configured or default classifier
+func (rc RetryConfig) Retryable(err error) bool {
+	if rc.IsRetryable != nil {
+		return rc.IsRetryable(err)
+	}
+	return DefaultRetryClassifier(err)
+}
+
+// ForMessage returns the retry config with the message's x-max-retries and
+// x-retry-delay-base header overrides applied
+func (rc RetryConfig) ForMessage(msg amqp.Delivery) RetryConfig {
+	if v, ok := headerInt(msg.Headers, HeaderMaxRetries); ok {
+		rc.MaxRetries = v
+	}
+	if v, ok := headerInt(msg.Headers, HeaderRetryDelayBase); ok {
+		rc.RetryDelayBase = v
+	}
+	return rc
+}
+
+// ForHeaders returns the retry config with the x-max-retries and x-retry-delay-base
+// overrides of a broker-neutral message's headers applied
+func (rc RetryConfig) ForHeaders(headers map[string]string) RetryConfig {
+	if v, err := strconv.Atoi(headers[HeaderMaxRetries]); err == nil {
+		rc.MaxRetries = v
+	}
+	if v, err := strconv.Atoi(headers[HeaderRetryDelayBase]); err == nil {
+		rc.RetryDelayBase = v
+	}
+	return rc
 }
 
 // GetRetryCount extracts the retry count from message headers
 func GetRetryCount(msg amqp.Delivery) int {
-	if msg.Headers != nil {
-		if retryCount, exists := msg.Headers["x-retry-count"]; exists {
-			switch v := retryCount.(type) {
-			case int32:
-				return int(v)
-			case int64:
-				return int(v)
-			case int:
-				return v
-			}
-		}
+	retryCount, _ := headerInt(msg.Headers, "x-retry-count")
+	return retryCount
+}
+
+// headerInt reads an integer header of any AMQP integer type, or a decimal string
+// (as sent by RabbitBroker, whose message headers are strings)
+func headerInt(headers amqp.Table, key string) (int, bool) {
+	switch v := headers[key].(type) {
+	case string:
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	case int8:
+		return int(v), true
+	case int16:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case int:
+		return v, true
 	}
-	return 0
+	return 0, false
 }
 
-// CalculateRetryDelay calculates delay with exponential backoff
+// CalculateRetryDelay calculates delay with exponential backoff and the configured jitter
 func CalculateRetryDelay(retryCount int, config RetryConfig) time.Duration {
+	delay := backoffDelay(retryCount, config)
+	if delay <= 0 {
+		return delay
+	}
+
+	switch config.Jitter {
+	case JitterFull:
+		return rand.N(delay + 1)
+	case JitterEqual:
+		half := delay / 2
+		return half + rand.N(delay-half+1)
+	default:
+		return delay
+	}
+}
+
+// backoffDelay calculates the exponential backoff delay without jitter
+func backoffDelay(retryCount int, config RetryConfig) time.Duration {
 	// Exponential backoff: baseDelay * 2^retryCount
 	delay := time.Duration(config.RetryDelayBase) * time.Duration(1<<retryCount) * time.Second
 
@@ -44,47 +153,97 @@ func CalculateRetryDelay(retryCount int, config RetryConfig) time.Duration {
 	return delay
 }
 
-// ChannelGetter returns the current consumer channel (used so retry uses channel after reconnect)
-type ChannelGetter func() *amqp.Channel
-
-// ScheduleRetry schedules a message for retry with delay.
-// getChannel is called at publish time so retries use the current channel after reconnect.
-func ScheduleRetry(
-	getChannel ChannelGetter,
-	config *Config,
-	body []byte,
-	headers amqp.Table,
-	delay time.Duration,
-) {
-	// In a production system, you might want to use a proper delay queue
-	// For now, we'll use a simple goroutine with sleep
-	go func() {
-		time.Sleep(delay)
-
-		channel := getChannel()
-		if channel == nil || channel.IsClosed() {
-			log.Printf("Cannot schedule retry: channel not available")
-			return
+// DelayedExchangeType is the exchange type provided by the rabbitmq_delayed_message_exchange plugin
+const DelayedExchangeType = "x-delayed-message"
+
+// RetryDelaysFor returns the backoff delay of every retry attempt allowed by config,
+// for use as Config.RetryDelays so each attempt waits in its own TTL queue.
+// TTL queues have fixed delays, so jitter only applies with a delayed exchange.
+func RetryDelaysFor(config RetryConfig) []time.Duration {
+	delays := make([]time.Duration, 0, config.MaxRetries)
+	for i := 0; i < config.MaxRetries; i++ {
+		delay := backoffDelay(i, config)
+		if len(delays) > 0 && delays[len(delays)-1] == delay {
+			continue // Capped delays share a queue
 		}
+		delays = append(delays, delay)
+	}
+	return delays
+}
+
+// RetryExchangeName returns the name of the exchange TTL retry queues are bound to
+func (qc *Config) RetryExchangeName() string {
+	return qc.QueueName + ".retry"
+}
 
-		// Publish back to the main queue with updated headers
-		err := channel.Publish(
-			config.ExchangeName, // exchange
-			config.RoutingKey,   // routing key
-			false,               // mandatory
-			false,               // immediate
-			amqp.Publishing{
-				ContentType:  "application/json",
-				Body:         body,
-				Headers:      headers,
-				DeliveryMode: amqp.Persistent,
-			},
-		)
+// RetryQueueName returns the name of the TTL retry queue for a delay
+func (qc *Config) RetryQueueName(delay time.Duration) string {
+	return fmt.Sprintf("%s.retry.%dms", qc.QueueName, delay.Milliseconds())
+}
+
+// SetupRetryQueues declares the retry exchange and a TTL queue per RetryDelays entry.
+// Expired messages are dead-lettered through the default exchange straight back to QueueName.
+func (qc *Config) SetupRetryQueues(ch *amqp.Channel) error {
+	if err := ch.ExchangeDeclare(qc.RetryExchangeName(), "direct", true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare retry exchange: %w", err)
+	}
 
+	for _, delay := range qc.RetryDelays {
+		name := qc.RetryQueueName(delay)
+		_, err := ch.QueueDeclare(name, true, false, false, false, amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": qc.QueueName,
+		})
 		if err != nil {
-			log.Printf("Failed to schedule retry: %v", err)
-		} else {
-			log.Printf("Scheduled retry with delay %v", delay)
+			return fmt.Errorf("failed to declare retry queue %s: %w", name, err)
 		}
-	}()
+		if err := ch.QueueBind(name, strconv.FormatInt(delay.Milliseconds(), 10), qc.RetryExchangeName(), false, nil); err != nil {
+			return fmt.Errorf("failed to bind retry queue %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// SetupDelayedExchange declares the delayed-message exchange and binds QueueName to it
+func (qc *Config) SetupDelayedExchange(ch *amqp.Channel) error {
+	err := ch.ExchangeDeclare(qc.DelayedExchangeName, DelayedExchangeType, true, false, false, false, amqp.Table{
+		"x-delayed-type": "direct",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to declare delayed exchange: %w", err)
+	}
+	return ch.QueueBind(qc.QueueName, qc.QueueName, qc.DelayedExchangeName, false, nil)
+}
+
+// hasRetryTopology checks if a retry delay topology is configured
+func (qc *Config) hasRetryTopology() bool {
+	return len(qc.RetryDelays) > 0 || qc.DelayedExchangeName != ""
+}
+
+// PublishRetry publishes a failed message for redelivery to QueueName after a delay, using
+// the TTL retry queue for the attempt or the delayed-message exchange (with delay as x-delay)
+func (qc *Config) PublishRetry(ch *amqp.Channel, body []byte, headers amqp.Table, retryCount int, delay time.Duration) error {
+	if ch == nil || ch.IsClosed() {
+		return fmt.Errorf("channel not available")
+	}
+
+	var exchange, routingKey string
+	switch {
+	case len(qc.RetryDelays) > 0:
+		tier := qc.RetryDelays[min(retryCount, len(qc.RetryDelays)-1)]
+		exchange, routingKey = qc.RetryExchangeName(), strconv.FormatInt(tier.Milliseconds(), 10)
+	case qc.DelayedExchangeName != "":
+		headers["x-delay"] = delay.Milliseconds()
+		exchange, routingKey = qc.DelayedExchangeName, qc.QueueName
+	default:
+		return fmt.Errorf("no retry queues or delayed exchange configured")
+	}
+
+	return ch.Publish(exchange, routingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		Body:         body,
+		Headers:      headers,
+		DeliveryMode: amqp.Persistent,
+	})
 }
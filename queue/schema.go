@@ -0,0 +1,112 @@
+package queue
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kerimovok/go-pkg-utils/validator"
+)
+
+// Schema validates the payload of a message type
+type Schema interface {
+	Validate(payload map[string]any) error
+}
+
+// SchemaFunc adapts a function to Schema, e.g. to plug in a JSON Schema library
+type SchemaFunc func(payload map[string]any) error
+
+// Validate calls f(payload)
+func (f SchemaFunc) Validate(payload map[string]any) error {
+	return f(payload)
+}
+
+// StructSchema validates payloads by decoding them into T and running validator.ValidateStruct
+func StructSchema[T any]() Schema {
+	return SchemaFunc(func(payload map[string]any) error {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode payload: %w", err)
+		}
+		var v T
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("payload does not match schema: %w", err)
+		}
+		if errs := validator.ValidateStruct(v); errs.HasErrors() {
+			return fmt.Errorf("invalid payload: %w", errs)
+		}
+		return nil
+	})
+}
+
+// SchemaRegistry holds the payload schemas of message types by version. Producers stamp
+// messages with the latest version; consumers accept any registered version.
+type SchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]map[int]Schema // type -> version -> schema
+}
+
+// NewSchemaRegistry creates an empty schema registry
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{schemas: make(map[string]map[int]Schema)}
+}
+
+// Register adds the schema of a message type version (versions start at 1)
+func (r *SchemaRegistry) Register(messageType string, version int, schema Schema) *SchemaRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.schemas[messageType] == nil {
+		r.schemas[messageType] = make(map[int]Schema)
+	}
+	r.schemas[messageType][version] = schema
+	return r
+}
+
+// LatestVersion returns the highest registered version of a message type (0 if none)
+func (r *SchemaRegistry) LatestVersion(messageType string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	latest := 0
+	for version := range r.schemas[messageType] {
+		latest = max(latest, version)
+	}
+	return latest
+}
+
+// Versions returns the registered versions of a message type in ascending order
+func (r *SchemaRegistry) Versions(messageType string) []int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	versions := make([]int, 0, len(r.schemas[messageType]))
+	for version := range r.schemas[messageType] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return versions
+}
+
+// Validate validates a payload against the schema of its type and version. Types without
+// schemas are accepted; version 0 (unversioned) is validated against the latest version
+// and unknown versions are rejected as incompatible.
+func (r *SchemaRegistry) Validate(messageType string, version int, payload map[string]any) error {
+	if version == 0 {
+		version = r.LatestVersion(messageType)
+	}
+
+	r.mu.RLock()
+	versions, ok := r.schemas[messageType]
+	schema := versions[version]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+	if schema == nil {
+		return fmt.Errorf("incompatible %s version %d (supported: %v)", messageType, version, r.Versions(messageType))
+	}
+	if err := schema.Validate(payload); err != nil {
+		return fmt.Errorf("invalid %s v%d payload: %w", messageType, version, err)
+	}
+	return nil
+}
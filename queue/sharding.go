@@ -0,0 +1,287 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	// ConsistentHashExchangeType is the exchange type provided by the rabbitmq_consistent_hash_exchange plugin
+	ConsistentHashExchangeType = "x-consistent-hash"
+	// HeaderPartitionKey is the header hashed by sharded exchanges to select a shard queue
+	HeaderPartitionKey = "x-partition-key"
+)
+
+// ShardingConfig describes a consistent-hash exchange with N shard queues.
+// Messages with the same partition key always land on the same shard, so ordering
+// is preserved per key while shards are consumed in parallel.
+type ShardingConfig struct {
+	ExchangeName    string
+	QueuePrefix     string // Shard queues are named "<QueuePrefix>.<index>"
+	ShardCount      int
+	Weight          int // Binding weight per shard (hash ring points) - defaults to 1
+	DLXExchangeName string
+	DLQName         string
+	DLQRoutingKey   string
+
+	// RetryDelays and DelayedExchangeName configure broker-side retry delays per shard (see Config)
+	RetryDelays         []time.Duration
+	DelayedExchangeName string
+}
+
+// getWeight returns the binding weight, defaulting to 1 if not set
+func (sc *ShardingConfig) getWeight() string {
+	if sc.Weight <= 0 {
+		return "1"
+	}
+	return strconv.Itoa(sc.Weight)
+}
+
+// exchangeArgs returns the consistent-hash exchange arguments (hash on partition header)
+func (sc *ShardingConfig) exchangeArgs() amqp.Table {
+	return amqp.Table{"hash-header": HeaderPartitionKey}
+}
+
+// ShardQueueName returns the queue name for the shard at index
+func (sc *ShardingConfig) ShardQueueName(index int) string {
+	return fmt.Sprintf("%s.%d", sc.QueuePrefix, index)
+}
+
+// ShardConfig returns the queue configuration for the shard at index
+func (sc *ShardingConfig) ShardConfig(index int) *Config {
+	return &Config{
+		ExchangeName:        sc.ExchangeName,
+		ExchangeType:        ConsistentHashExchangeType,
+		ExchangeArgs:        sc.exchangeArgs(),
+		QueueName:           sc.ShardQueueName(index),
+		RoutingKey:          sc.getWeight(), // For consistent-hash bindings the routing key is the weight
+		DLXExchangeName:     sc.DLXExchangeName,
+		DLQName:             sc.DLQName,
+		DLQRoutingKey:       sc.DLQRoutingKey,
+		RetryDelays:         sc.RetryDelays,
+		DelayedExchangeName: sc.DelayedExchangeName,
+	}
+}
+
+// ProducerConfig returns the exchange-only configuration used by sharded producers
+func (sc *ShardingConfig) ProducerConfig() *Config {
+	return &Config{
+		ExchangeName:    sc.ExchangeName,
+		ExchangeType:    ConsistentHashExchangeType,
+		ExchangeArgs:    sc.exchangeArgs(),
+		RoutingKey:      sc.getWeight(),
+		DLXExchangeName: sc.DLXExchangeName,
+		DLQName:         sc.DLQName,
+		DLQRoutingKey:   sc.DLQRoutingKey,
+	}
+}
+
+// validate checks the sharding configuration
+func (sc *ShardingConfig) validate() error {
+	if sc.ExchangeName == "" {
+		return fmt.Errorf("exchange name is required")
+	}
+	if sc.QueuePrefix == "" {
+		return fmt.Errorf("queue prefix is required")
+	}
+	if sc.ShardCount <= 0 {
+		return fmt.Errorf("shard count must be positive")
+	}
+	return nil
+}
+
+// SetupShardedQueues declares the consistent-hash exchange and all shard queues
+func (sc *ShardingConfig) SetupShardedQueues(ch *amqp.Channel) error {
+	if err := sc.validate(); err != nil {
+		return err
+	}
+	for i := 0; i < sc.ShardCount; i++ {
+		if err := sc.ShardConfig(i).SetupAllQueues(ch); err != nil {
+			return fmt.Errorf("failed to setup shard %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ShardsForInstance returns the shard indexes owned by an instance of a consumer group.
+// Shards are assigned round-robin: shard i belongs to instance i % instanceCount.
+func ShardsForInstance(shardCount, instanceIndex, instanceCount int) []int {
+	if instanceCount <= 0 {
+		instanceCount = 1
+	}
+	var shards []int
+	for i := 0; i < shardCount; i++ {
+		if i%instanceCount == instanceIndex {
+			shards = append(shards, i)
+		}
+	}
+	return shards
+}
+
+// ShardedProducer publishes messages to a consistent-hash exchange using a partition key
+type ShardedProducer struct {
+	producer *Producer
+}
+
+// NewShardedProducer creates a producer for a sharded exchange
+func NewShardedProducer(connConfig ConnectionConfig, shardConfig *ShardingConfig) (*ShardedProducer, error) {
+	if err := shardConfig.validate(); err != nil {
+		return nil, err
+	}
+
+	producer, err := NewProducer(connConfig, shardConfig.ProducerConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sharded producer: %w", err)
+	}
+
+	return &ShardedProducer{producer: producer}, nil
+}
+
+// Publish publishes a message; messages with the same partition key go to the same shard
+func (p *ShardedProducer) Publish(ctx context.Context, partitionKey string, body []byte, headers amqp.Table) error {
+	if partitionKey == "" {
+		return fmt.Errorf("partition key is required")
+	}
+
+	withKey := amqp.Table{}
+	for k, v := range headers {
+		withKey[k] = v
+	}
+	withKey[HeaderPartitionKey] = partitionKey
+
+	return p.producer.Publish(ctx, body, withKey)
+}
+
+// Use wraps publishing with middlewares
+func (p *ShardedProducer) Use(middlewares ...PublishMiddleware) {
+	p.producer.Use(middlewares...)
+}
+
+// IsConnected returns true if the producer has a valid connection
+func (p *ShardedProducer) IsConnected() bool {
+	return p.producer.IsConnected()
+}
+
+// Close closes the producer connection
+func (p *ShardedProducer) Close() error {
+	return p.producer.Close()
+}
+
+// ConsumerGroupConfig identifies this instance within a group of sharded consumers
+type ConsumerGroupConfig struct {
+	InstanceIndex int // Zero-based index of this instance
+	InstanceCount int // Total number of instances in the group - defaults to 1
+	Metrics       Metrics
+}
+
+// ShardedConsumerGroup consumes the shards assigned to this instance.
+// Each shard is processed sequentially to preserve per-partition ordering.
+type ShardedConsumerGroup struct {
+	consumers []*Consumer
+	shards    []int
+}
+
+// NewShardedConsumerGroup declares the sharded topology and creates a consumer per owned shard
+func NewShardedConsumerGroup(
+	connConfig ConnectionConfig,
+	shardConfig *ShardingConfig,
+	groupConfig ConsumerGroupConfig,
+	retryConfig RetryConfig,
+	handler MessageHandler,
+) (*ShardedConsumerGroup, error) {
+	if err := shardConfig.validate(); err != nil {
+		return nil, err
+	}
+	if groupConfig.InstanceCount <= 0 {
+		groupConfig.InstanceCount = 1
+	}
+	if groupConfig.InstanceIndex < 0 || groupConfig.InstanceIndex >= groupConfig.InstanceCount {
+		return nil, fmt.Errorf("instance index %d out of range for %d instances", groupConfig.InstanceIndex, groupConfig.InstanceCount)
+	}
+
+	group := &ShardedConsumerGroup{
+		shards: ShardsForInstance(shardConfig.ShardCount, groupConfig.InstanceIndex, groupConfig.InstanceCount),
+	}
+
+	for _, shard := range group.shards {
+		// A single worker per shard keeps messages in delivery order
+		consumer, err := NewConsumerWithOptions(connConfig, shardConfig.ShardConfig(shard), retryConfig, handler, ConsumerOptions{Concurrency: 1, Metrics: groupConfig.Metrics})
+		if err != nil {
+			group.Close()
+			return nil, fmt.Errorf("failed to create consumer for shard %d: %w", shard, err)
+		}
+		group.consumers = append(group.consumers, consumer)
+	}
+
+	return group, nil
+}
+
+// Shards returns the shard indexes consumed by this instance
+func (g *ShardedConsumerGroup) Shards() []int {
+	return g.shards
+}
+
+// Use wraps the handler of every shard consumer with middlewares
+func (g *ShardedConsumerGroup) Use(middlewares ...Middleware) {
+	for _, consumer := range g.consumers {
+		consumer.Use(middlewares...)
+	}
+}
+
+// StartConsuming starts consuming all owned shards
+func (g *ShardedConsumerGroup) StartConsuming() error {
+	for _, consumer := range g.consumers {
+		if err := consumer.StartConsuming(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsConnected returns true if all shard consumers have a valid connection
+func (g *ShardedConsumerGroup) IsConnected() bool {
+	for _, consumer := range g.consumers {
+		if !consumer.IsConnected() {
+			return false
+		}
+	}
+	return true
+}
+
+// Shutdown gracefully stops all shard consumers in parallel (see Consumer.Shutdown),
+// returning the first error encountered
+func (g *ShardedConsumerGroup) Shutdown(ctx context.Context) error {
+	errs := make([]error, len(g.consumers))
+	var wg sync.WaitGroup
+	for i, consumer := range g.consumers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = consumer.Shutdown(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all shard consumers, returning the first error encountered
+func (g *ShardedConsumerGroup) Close() error {
+	var firstErr error
+	for _, consumer := range g.consumers {
+		if err := consumer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -13,6 +13,7 @@ import (
 type Task struct {
 	Service string         `json:"service"`
 	Type    string         `json:"type"`
+	Version int            `json:"version,omitempty"` // Payload schema version (0 = unversioned)
 	Payload map[string]any `json:"payload"`
 }
 
@@ -31,6 +32,7 @@ var defaultQueueConfig = &queue.Config{
 type Producer struct {
 	producer *queue.Producer
 	service  string
+	schemas  *queue.SchemaRegistry
 }
 
 // ProducerConfig holds configuration for the task producer
@@ -40,6 +42,12 @@ type ProducerConfig struct {
 
 	// QueueConfig allows overriding the default queue configuration (optional)
 	QueueConfig *queue.Config
+
+	// Options enables publisher confirms and mandatory returns (optional)
+	Options queue.ProducerOptions
+
+	// Schemas validates payloads before publishing and sets the task version (optional)
+	Schemas *queue.SchemaRegistry
 }
 
 // NewProducer creates a new task producer
@@ -53,7 +61,7 @@ func NewProducer(connConfig queue.ConnectionConfig, config ProducerConfig) (*Pro
 		queueConfig = config.QueueConfig
 	}
 
-	producer, err := queue.NewProducer(connConfig, queueConfig)
+	producer, err := queue.NewProducerWithOptions(connConfig, queueConfig, config.Options)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task producer: %w", err)
 	}
@@ -61,6 +69,7 @@ func NewProducer(connConfig queue.ConnectionConfig, config ProducerConfig) (*Pro
 	return &Producer{
 		producer: producer,
 		service:  config.ServiceName,
+		schemas:  config.Schemas,
 	}, nil
 }
 
@@ -68,24 +77,9 @@ func NewProducer(connConfig queue.ConnectionConfig, config ProducerConfig) (*Pro
 // The routing key is constructed as "tasks.<taskType>"
 // Example: taskType "email.verify" becomes routing key "tasks.email.verify"
 func (p *Producer) Publish(ctx context.Context, taskType string, payload map[string]any) error {
-	if payload == nil {
-		payload = make(map[string]any)
-	}
-
-	// Add timestamp if not present
-	if _, ok := payload["timestamp"]; !ok {
-		payload["timestamp"] = time.Now().UTC().Format(time.RFC3339)
-	}
-
-	task := Task{
-		Service: p.service,
-		Type:    taskType,
-		Payload: payload,
-	}
-
-	data, err := json.Marshal(task)
+	data, err := p.marshalTask(taskType, payload)
 	if err != nil {
-		return fmt.Errorf("failed to marshal task: %w", err)
+		return err
 	}
 
 	// Build routing key: tasks.<taskType>
@@ -108,6 +102,17 @@ func (p *Producer) PublishAsync(taskType string, payload map[string]any) {
 // PublishWithCustomRoutingKey publishes a task with a custom routing key
 // Use this if you need to override the default "tasks.<taskType>" pattern
 func (p *Producer) PublishWithCustomRoutingKey(ctx context.Context, taskType string, payload map[string]any, routingKey string) error {
+	data, err := p.marshalTask(taskType, payload)
+	if err != nil {
+		return err
+	}
+
+	return p.producer.PublishWithRoutingKey(ctx, data, nil, routingKey)
+}
+
+// marshalTask builds the task (adding a timestamp to the payload if missing), validates and
+// versions it with the schema registry if configured, and marshals it
+func (p *Producer) marshalTask(taskType string, payload map[string]any) ([]byte, error) {
 	if payload == nil {
 		payload = make(map[string]any)
 	}
@@ -123,12 +128,23 @@ func (p *Producer) PublishWithCustomRoutingKey(ctx context.Context, taskType str
 		Payload: payload,
 	}
 
+	if p.schemas != nil {
+		task.Version = p.schemas.LatestVersion(taskType)
+		if err := p.schemas.Validate(taskType, task.Version, payload); err != nil {
+			return nil, err
+		}
+	}
+
 	data, err := json.Marshal(task)
 	if err != nil {
-		return fmt.Errorf("failed to marshal task: %w", err)
+		return nil, fmt.Errorf("failed to marshal task: %w", err)
 	}
+	return data, nil
+}
 
-	return p.producer.PublishWithRoutingKey(ctx, data, nil, routingKey)
+// Use wraps publishing with middlewares (e.g. header injection)
+func (p *Producer) Use(middlewares ...queue.PublishMiddleware) {
+	p.producer.Use(middlewares...)
 }
 
 // IsConnected returns true if the producer has a valid connection
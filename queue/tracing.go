@@ -0,0 +1,220 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// W3C trace context header names
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+// HeaderCarrier adapts AMQP headers to the Get/Set/Keys carrier interface used by
+// propagators (it satisfies OpenTelemetry's propagation.TextMapCarrier)
+type HeaderCarrier amqp.Table
+
+// Get returns the string value of a header ("" if missing or not a string)
+func (c HeaderCarrier) Get(key string) string {
+	switch v := c[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
+
+// Set sets a header
+func (c HeaderCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+// Keys returns the header names
+func (c HeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Propagator injects trace context from a context into message headers and extracts it back.
+// Wrap otel.GetTextMapPropagator() to use OpenTelemetry; W3CPropagator works without it.
+type Propagator interface {
+	Inject(ctx context.Context, carrier HeaderCarrier)
+	Extract(ctx context.Context, carrier HeaderCarrier) context.Context
+}
+
+// TraceContext is a W3C trace context (https://www.w3.org/TR/trace-context/)
+type TraceContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Flags   byte
+	State   string // Vendor-specific tracestate, passed through unchanged
+}
+
+// ParseTraceParent parses a traceparent header value ("00-<trace-id>-<span-id>-<flags>")
+func ParseTraceParent(value string) (TraceContext, error) {
+	var tc TraceContext
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return tc, fmt.Errorf("invalid traceparent %q", value)
+	}
+	if parts[0] == "00" && len(parts) != 4 {
+		return tc, fmt.Errorf("invalid traceparent %q", value)
+	}
+
+	if err := decodeHexInto(tc.TraceID[:], parts[1]); err != nil {
+		return tc, fmt.Errorf("invalid trace id in traceparent: %w", err)
+	}
+	if err := decodeHexInto(tc.SpanID[:], parts[2]); err != nil {
+		return tc, fmt.Errorf("invalid span id in traceparent: %w", err)
+	}
+	var flags [1]byte
+	if err := decodeHexInto(flags[:], parts[3]); err != nil {
+		return tc, fmt.Errorf("invalid flags in traceparent: %w", err)
+	}
+	tc.Flags = flags[0]
+
+	if !tc.IsValid() {
+		return tc, fmt.Errorf("invalid traceparent %q: zero trace or span id", value)
+	}
+	return tc, nil
+}
+
+// String formats the trace context as a traceparent header value
+func (tc TraceContext) String() string {
+	return fmt.Sprintf("00-%s-%s-%02x", hex.EncodeToString(tc.TraceID[:]), hex.EncodeToString(tc.SpanID[:]), tc.Flags)
+}
+
+// IsValid checks that the trace and span IDs are not all zeros
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// Child returns a trace context in the same trace with a new random span ID
+func (tc TraceContext) Child() TraceContext {
+	child := tc
+	_, _ = rand.Read(child.SpanID[:])
+	return child
+}
+
+// NewTraceContext starts a new sampled trace with random IDs
+func NewTraceContext() TraceContext {
+	tc := TraceContext{Flags: 0x01}
+	_, _ = rand.Read(tc.TraceID[:])
+	_, _ = rand.Read(tc.SpanID[:])
+	return tc
+}
+
+type traceContextKey struct{}
+
+// ContextWithTraceContext returns a context carrying tc
+func ContextWithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceContextFromContext returns the trace context carried by ctx, if any
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok
+}
+
+// W3CPropagator propagates TraceContext values via traceparent/tracestate headers.
+// Inject continues the trace found in the context with a new span ID, or starts a new trace.
+type W3CPropagator struct{}
+
+// Inject writes traceparent (and tracestate) for the trace context in ctx
+func (W3CPropagator) Inject(ctx context.Context, carrier HeaderCarrier) {
+	tc, ok := TraceContextFromContext(ctx)
+	if ok {
+		tc = tc.Child()
+	} else {
+		tc = NewTraceContext()
+	}
+	carrier.Set(HeaderTraceParent, tc.String())
+	if tc.State != "" {
+		carrier.Set(HeaderTraceState, tc.State)
+	}
+}
+
+// Extract returns ctx carrying the trace context found in the headers (ctx unchanged if none)
+func (W3CPropagator) Extract(ctx context.Context, carrier HeaderCarrier) context.Context {
+	tc, err := ParseTraceParent(carrier.Get(HeaderTraceParent))
+	if err != nil {
+		return ctx
+	}
+	tc.State = carrier.Get(HeaderTraceState)
+	return ContextWithTraceContext(ctx, tc)
+}
+
+// TracingPublishMiddleware injects the trace context of the publish context into message
+// headers (nil propagator = W3CPropagator), so consumers can continue the trace
+func TracingPublishMiddleware(propagator Propagator) PublishMiddleware {
+	if propagator == nil {
+		propagator = W3CPropagator{}
+	}
+	return func(next PublishHandler) PublishHandler {
+		return func(ctx context.Context, pub *Publication) error {
+			propagator.Inject(ctx, HeaderCarrier(pub.Publishing.Headers))
+			return next(ctx, pub)
+		}
+	}
+}
+
+// ExtractContext returns ctx carrying the trace context propagated in a delivery's headers
+// (nil propagator = W3CPropagator)
+func ExtractContext(ctx context.Context, delivery amqp.Delivery, propagator Propagator) context.Context {
+	if propagator == nil {
+		propagator = W3CPropagator{}
+	}
+	if delivery.Headers == nil {
+		return ctx
+	}
+	return propagator.Extract(ctx, HeaderCarrier(delivery.Headers))
+}
+
+// SpanStarter starts a consumer span as a child of ctx (which carries the extracted trace
+// context) and returns the span context and a function ending the span with the handler result
+type SpanStarter func(ctx context.Context, delivery amqp.Delivery) (context.Context, func(err error))
+
+// TracingMiddleware extracts the propagated trace context (nil propagator = W3CPropagator) and
+// wraps plain message handlers in a span started by startSpan. Typed consumers do this via
+// ConsumerOptions and pass the span context to the handler.
+func TracingMiddleware(propagator Propagator, startSpan SpanStarter) Middleware {
+	return func(next MessageHandler) MessageHandler {
+		return func(msg amqp.Delivery) error {
+			_, end := startSpan(ExtractContext(context.Background(), msg, propagator), msg)
+			err := next(msg)
+			end(err)
+			return err
+		}
+	}
+}
+
+// messageContext derives a handler context from the consumer context: the correlation IDs and
+// propagated trace context are extracted and, if configured, a consumer span is started
+func (c *Consumer) messageContext(delivery amqp.Delivery) (context.Context, func(err error)) {
+	ctx := ExtractContext(ContextFromDelivery(c.ctx, delivery), delivery, c.options.Propagator)
+	if c.options.StartSpan == nil {
+		return ctx, func(error) {}
+	}
+	return c.options.StartSpan(ctx, delivery)
+}
+
+// decodeHexInto decodes a lowercase hex string of exactly len(dst) bytes
+func decodeHexInto(dst []byte, value string) error {
+	if len(value) != hex.EncodedLen(len(dst)) || strings.ToLower(value) != value {
+		return fmt.Errorf("expected %d lowercase hex characters", hex.EncodedLen(len(dst)))
+	}
+	_, err := hex.Decode(dst, []byte(value))
+	return err
+}
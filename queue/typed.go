@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/kerimovok/go-pkg-utils/validator"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// PermanentError marks a handler error that retrying cannot fix (e.g. a malformed payload).
+// The consumer rejects such messages straight to the dead letter queue.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so the consumer does not retry the message
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// TypedHandler processes a message whose JSON body was decoded into T.
+// ctx carries the propagated trace context and is canceled when the consumer is closed.
+type TypedHandler[T any] func(ctx context.Context, msg T, delivery amqp.Delivery) error
+
+// NewTypedConsumer creates a consumer that decodes each JSON message body into T, validates
+// it with validator.ValidateStruct (for struct types) and calls handler. Messages that cannot
+// be decoded or fail validation are sent to the dead letter queue without retrying; handler
+// errors are retried as usual.
+func NewTypedConsumer[T any](connConfig ConnectionConfig, queueConfig *Config, retryConfig RetryConfig, handler TypedHandler[T]) (*Consumer, error) {
+	return NewTypedConsumerWithOptions(connConfig, queueConfig, retryConfig, handler, ConsumerOptions{})
+}
+
+// NewTypedConsumerWithOptions is NewTypedConsumer with concurrency and prefetch options
+func NewTypedConsumerWithOptions[T any](connConfig ConnectionConfig, queueConfig *Config, retryConfig RetryConfig, handler TypedHandler[T], options ConsumerOptions) (*Consumer, error) {
+	var consumer *Consumer
+	consumer, err := NewConsumerWithOptions(connConfig, queueConfig, retryConfig, func(delivery amqp.Delivery) error {
+		ctx, end := consumer.messageContext(delivery)
+		msg, err := DecodeMessage[T](delivery)
+		if err != nil {
+			err = Permanent(err)
+		} else {
+			err = handler(ctx, msg, delivery)
+		}
+		end(err)
+		return err
+	}, options)
+	if err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
+// DecodeMessage decodes a delivery's JSON body into T and validates it (for struct types)
+func DecodeMessage[T any](delivery amqp.Delivery) (T, error) {
+	var msg T
+	if err := json.Unmarshal(delivery.Body, &msg); err != nil {
+		return msg, fmt.Errorf("failed to decode message: %w", err)
+	}
+
+	t := reflect.TypeOf(msg)
+	if t != nil && (t.Kind() == reflect.Struct || (t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct)) {
+		if errs := validator.ValidateStruct(msg); errs.HasErrors() {
+			return msg, fmt.Errorf("invalid message: %w", errs)
+		}
+	}
+	return msg, nil
+}
@@ -0,0 +1,132 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CaseStyle is an identifier naming convention
+type CaseStyle string
+
+const (
+	CaseUnknown   CaseStyle = ""
+	CaseSnake     CaseStyle = "snake"     // hello_world
+	CaseScreaming CaseStyle = "screaming" // HELLO_WORLD
+	CaseCamel     CaseStyle = "camel"     // helloWorld
+	CasePascal    CaseStyle = "pascal"    // HelloWorld
+	CaseKebab     CaseStyle = "kebab"     // hello-world
+)
+
+// DetectCase reports the case style of an identifier. A single lowercase word reports
+// CaseSnake and a single uppercase word CaseScreaming; mixed separators or spaces report CaseUnknown.
+func DetectCase(str string) CaseStyle {
+	if str == "" {
+		return CaseUnknown
+	}
+
+	var hasUpper, hasLower, hasUnderscore, hasHyphen bool
+	for _, r := range str {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case r == '_':
+			hasUnderscore = true
+		case r == '-':
+			hasHyphen = true
+		case !unicode.IsDigit(r):
+			return CaseUnknown
+		}
+	}
+
+	first := []rune(str)[0]
+	switch {
+	case hasUnderscore && hasHyphen:
+		return CaseUnknown
+	case hasUnderscore || hasHyphen:
+		if hasUpper && hasLower {
+			return CaseUnknown
+		}
+		if hasUnderscore {
+			if hasUpper {
+				return CaseScreaming
+			}
+			return CaseSnake
+		}
+		if hasUpper {
+			return CaseUnknown
+		}
+		return CaseKebab
+	case !hasUpper:
+		return CaseSnake
+	case !hasLower:
+		return CaseScreaming
+	case unicode.IsUpper(first):
+		return CasePascal
+	case unicode.IsLower(first):
+		return CaseCamel
+	default:
+		return CaseUnknown
+	}
+}
+
+// ConvertCase converts a string of any case style (or plain words) to the target style.
+// An unknown target returns the string unchanged.
+func ConvertCase(str string, target CaseStyle) string {
+	words := SplitWords(str)
+	switch target {
+	case CaseSnake:
+		return strings.ToLower(strings.Join(words, "_"))
+	case CaseScreaming:
+		return strings.ToUpper(strings.Join(words, "_"))
+	case CaseKebab:
+		return strings.ToLower(strings.Join(words, "-"))
+	case CaseCamel, CasePascal:
+		var result strings.Builder
+		for i, word := range words {
+			if i == 0 && target == CaseCamel {
+				result.WriteString(strings.ToLower(word))
+				continue
+			}
+			runes := []rune(strings.ToLower(word))
+			runes[0] = unicode.ToUpper(runes[0])
+			result.WriteString(string(runes))
+		}
+		return result.String()
+	default:
+		return str
+	}
+}
+
+// SplitWords splits an identifier or phrase into words at separators, lower-to-upper
+// transitions and the end of acronyms ("HTTPServer_v2 id" -> ["HTTP", "Server", "v2", "id"])
+func SplitWords(str string) []string {
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+
+	runes := []rune(str)
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := current[len(current)-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			// "helloWorld" -> hello|World, "HTTPServer" -> HTTP|Server
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextIsLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+	return words
+}
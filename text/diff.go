@@ -0,0 +1,257 @@
+package text
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// DiffOp is the kind of a diff edit
+type DiffOp int
+
+const (
+	DiffEqual  DiffOp = iota // Text present in both versions
+	DiffInsert               // Text only in the new version
+	DiffDelete               // Text only in the old version
+)
+
+// DiffEdit is a run of text with the same diff operation
+type DiffEdit struct {
+	Op   DiffOp
+	Text string
+}
+
+// ANSI escape sequences used by RenderDiffANSI
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiReset = "\x1b[0m"
+)
+
+// Diff compares two texts line by line. Each edit holds whole lines including their
+// trailing newline, so concatenating the non-deleted edits reproduces b.
+func Diff(a, b string) []DiffEdit {
+	return diffTokens(splitLines(a), splitLines(b))
+}
+
+// DiffWords compares two texts word by word (whitespace runs are separate tokens),
+// for inline highlighting of small changes
+func DiffWords(a, b string) []DiffEdit {
+	return diffTokens(splitWords(a), splitWords(b))
+}
+
+// RenderDiffANSI renders edits for terminals: deletions in red, insertions in green
+func RenderDiffANSI(edits []DiffEdit) string {
+	var result strings.Builder
+	for _, edit := range edits {
+		switch edit.Op {
+		case DiffInsert:
+			result.WriteString(ansiGreen + edit.Text + ansiReset)
+		case DiffDelete:
+			result.WriteString(ansiRed + edit.Text + ansiReset)
+		default:
+			result.WriteString(edit.Text)
+		}
+	}
+	return result.String()
+}
+
+// RenderDiffHTML renders edits as escaped HTML with <del> and <ins> elements
+func RenderDiffHTML(edits []DiffEdit) string {
+	var result strings.Builder
+	for _, edit := range edits {
+		text := html.EscapeString(edit.Text)
+		switch edit.Op {
+		case DiffInsert:
+			result.WriteString("<ins>" + text + "</ins>")
+		case DiffDelete:
+			result.WriteString("<del>" + text + "</del>")
+		default:
+			result.WriteString(text)
+		}
+	}
+	return result.String()
+}
+
+// diffTokens computes a minimal edit script between token sequences with Myers' algorithm
+// in its linear-space form, so memory stays proportional to the input size. Adjacent edits
+// of the same kind are merged and deletions come before insertions within a change.
+func diffTokens(a, b []string) []DiffEdit {
+	// Compare small integer IDs instead of strings
+	ids := make(map[string]int)
+	id := func(tokens []string) []int {
+		result := make([]int, len(tokens))
+		for i, token := range tokens {
+			n, ok := ids[token]
+			if !ok {
+				n = len(ids)
+				ids[token] = n
+			}
+			result[i] = n
+		}
+		return result
+	}
+
+	d := &differ{a: a, b: b, ia: id(a), ib: id(b)}
+	d.compare(0, len(a), 0, len(b))
+	d.flush()
+	return d.edits
+}
+
+// differ accumulates the edit script of diffTokens
+type differ struct {
+	a, b     []string
+	ia, ib   []int
+	edits    []DiffEdit
+	del, ins strings.Builder // Pending change, emitted before the next equal run
+}
+
+// compare emits the edits turning a[aLo:aHi] into b[bLo:bHi]
+func (d *differ) compare(aLo, aHi, bLo, bHi int) {
+	// Common prefix and suffix are equal runs
+	for aLo < aHi && bLo < bHi && d.ia[aLo] == d.ib[bLo] {
+		d.equal(d.a[aLo])
+		aLo++
+		bLo++
+	}
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && d.ia[aHi-1-suffix] == d.ib[bHi-1-suffix] {
+		suffix++
+	}
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	switch {
+	case aLo == aHi:
+		for _, token := range d.b[bLo:bHi] {
+			d.ins.WriteString(token)
+		}
+	case bLo == bHi:
+		for _, token := range d.a[aLo:aHi] {
+			d.del.WriteString(token)
+		}
+	default:
+		x, y, u, v := middleSnake(d.ia[aLo:aHi], d.ib[bLo:bHi])
+		d.compare(aLo, aLo+x, bLo, bLo+y)
+		for _, token := range d.a[aLo+x : aLo+u] {
+			d.equal(token)
+		}
+		d.compare(aLo+u, aHi, bLo+v, bHi)
+	}
+
+	for _, token := range d.a[aHi : aHi+suffix] {
+		d.equal(token)
+	}
+}
+
+// equal emits the pending change and an equal token
+func (d *differ) equal(token string) {
+	d.flush()
+	d.add(DiffEqual, token)
+}
+
+// flush emits the pending deletions and insertions
+func (d *differ) flush() {
+	if d.del.Len() > 0 {
+		d.add(DiffDelete, d.del.String())
+		d.del.Reset()
+	}
+	if d.ins.Len() > 0 {
+		d.add(DiffInsert, d.ins.String())
+		d.ins.Reset()
+	}
+}
+
+// add appends text, merging it into the last edit of the same kind
+func (d *differ) add(op DiffOp, text string) {
+	if n := len(d.edits); n > 0 && d.edits[n-1].Op == op {
+		d.edits[n-1].Text += text
+		return
+	}
+	d.edits = append(d.edits, DiffEdit{Op: op, Text: text})
+}
+
+// middleSnake finds the middle snake of an optimal edit path between non-empty a and b
+// whose first and last tokens differ: a run of equal tokens a[x:u] == b[y:v] that splits
+// the problem into two halves of about half the edits each
+func middleSnake(a, b []int) (x, y, u, v int) {
+	n, m := len(a), len(b)
+	delta := n - m
+	limit := (n + m + 1) / 2
+	offset := limit + 1
+	// forward[k] and backward[k] hold the furthest x reached on diagonal k (x - y = k) from
+	// the start and, on the reversed sequences, from the end
+	forward := make([]int, 2*limit+3)
+	backward := make([]int, 2*limit+3)
+
+	for cost := 0; cost <= limit; cost++ {
+		for k := -cost; k <= cost; k += 2 {
+			var x int
+			if k == -cost || (k != cost && forward[offset+k-1] < forward[offset+k+1]) {
+				x = forward[offset+k+1]
+			} else {
+				x = forward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			forward[offset+k] = x
+
+			// On odd deltas the paths meet after a forward step
+			if delta%2 != 0 && delta-k >= -(cost-1) && delta-k <= cost-1 && x+backward[offset+delta-k] >= n {
+				return startX, startY, x, y
+			}
+		}
+
+		for k := -cost; k <= cost; k += 2 {
+			var x int
+			if k == -cost || (k != cost && backward[offset+k-1] < backward[offset+k+1]) {
+				x = backward[offset+k+1]
+			} else {
+				x = backward[offset+k-1] + 1
+			}
+			y := x - k
+			startX, startY := x, y
+			for x < n && y < m && a[n-1-x] == b[m-1-y] {
+				x++
+				y++
+			}
+			backward[offset+k] = x
+
+			// On even deltas the paths meet after a backward step
+			if delta%2 == 0 && delta-k >= -cost && delta-k <= cost && x+forward[offset+delta-k] >= n {
+				return n - x, m - y, n - startX, m - startY
+			}
+		}
+	}
+	return 0, 0, 0, 0 // Unreachable: the paths always meet within limit steps
+}
+
+// splitLines splits text into lines, keeping each line's trailing newline
+func splitLines(str string) []string {
+	lines := strings.SplitAfter(str, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitWords splits text into alternating runs of whitespace and non-whitespace
+func splitWords(str string) []string {
+	var tokens []string
+	start, inSpace := 0, false
+	for i, r := range str {
+		space := unicode.IsSpace(r)
+		if i > start && space != inSpace {
+			tokens = append(tokens, str[start:i])
+			start = i
+		}
+		inSpace = space
+	}
+	if start < len(str) {
+		tokens = append(tokens, str[start:])
+	}
+	return tokens
+}
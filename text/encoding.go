@@ -0,0 +1,160 @@
+package text
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+const (
+	// Base62Alphabet is the alphabet used by the Base62 encoders (digits, upper, lower)
+	Base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// Base58Alphabet is the Bitcoin alphabet used by the Base58 encoders (no 0, O, I or l)
+	Base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+// baseEncoding converts between bytes/integers and a positional alphabet
+type baseEncoding struct {
+	name     string
+	alphabet string
+	index    [256]int
+}
+
+func newBaseEncoding(name, alphabet string) *baseEncoding {
+	enc := &baseEncoding{name: name, alphabet: alphabet}
+	for i := range enc.index {
+		enc.index[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		enc.index[alphabet[i]] = i
+	}
+	return enc
+}
+
+var (
+	base62 = newBaseEncoding("base62", Base62Alphabet)
+	base58 = newBaseEncoding("base58", Base58Alphabet)
+)
+
+// EncodeBase62 encodes bytes as Base62; leading zero bytes are kept as leading '0's
+func EncodeBase62(data []byte) string {
+	return base62.encode(data)
+}
+
+// DecodeBase62 decodes a string produced by EncodeBase62
+func DecodeBase62(str string) ([]byte, error) {
+	return base62.decode(str)
+}
+
+// EncodeBase62Int encodes an integer as Base62 (e.g. for short link IDs)
+func EncodeBase62Int(n uint64) string {
+	return base62.encodeInt(n)
+}
+
+// DecodeBase62Int decodes a string produced by EncodeBase62Int
+func DecodeBase62Int(str string) (uint64, error) {
+	return base62.decodeInt(str)
+}
+
+// EncodeBase58 encodes bytes as Base58 (Bitcoin alphabet); leading zero bytes are kept as leading '1's
+func EncodeBase58(data []byte) string {
+	return base58.encode(data)
+}
+
+// DecodeBase58 decodes a string produced by EncodeBase58
+func DecodeBase58(str string) ([]byte, error) {
+	return base58.decode(str)
+}
+
+// EncodeBase58Int encodes an integer as Base58 (Bitcoin alphabet)
+func EncodeBase58Int(n uint64) string {
+	return base58.encodeInt(n)
+}
+
+// DecodeBase58Int decodes a string produced by EncodeBase58Int
+func DecodeBase58Int(str string) (uint64, error) {
+	return base58.decodeInt(str)
+}
+
+func (e *baseEncoding) encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	var digits []byte
+	n := new(big.Int).SetBytes(data[zeros:])
+	base := big.NewInt(int64(len(e.alphabet)))
+	mod := new(big.Int)
+	for n.Sign() > 0 {
+		n.DivMod(n, base, mod)
+		digits = append(digits, e.alphabet[mod.Int64()])
+	}
+
+	var result strings.Builder
+	result.Grow(zeros + len(digits))
+	for i := 0; i < zeros; i++ {
+		result.WriteByte(e.alphabet[0])
+	}
+	for i := len(digits) - 1; i >= 0; i-- {
+		result.WriteByte(digits[i])
+	}
+	return result.String()
+}
+
+func (e *baseEncoding) decode(str string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(str) && str[zeros] == e.alphabet[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	base := big.NewInt(int64(len(e.alphabet)))
+	for i := zeros; i < len(str); i++ {
+		digit := e.index[str[i]]
+		if digit < 0 {
+			return nil, fmt.Errorf("invalid %s character %q at position %d", e.name, str[i], i)
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
+
+func (e *baseEncoding) encodeInt(n uint64) string {
+	if n == 0 {
+		return e.alphabet[:1]
+	}
+
+	base := uint64(len(e.alphabet))
+	var digits [64]byte
+	i := len(digits)
+	for n > 0 {
+		i--
+		digits[i] = e.alphabet[n%base]
+		n /= base
+	}
+	return string(digits[i:])
+}
+
+func (e *baseEncoding) decodeInt(str string) (uint64, error) {
+	if str == "" {
+		return 0, fmt.Errorf("empty %s string", e.name)
+	}
+
+	base := uint64(len(e.alphabet))
+	var n uint64
+	for i := 0; i < len(str); i++ {
+		digit := e.index[str[i]]
+		if digit < 0 {
+			return 0, fmt.Errorf("invalid %s character %q at position %d", e.name, str[i], i)
+		}
+		if n > (math.MaxUint64-uint64(digit))/base {
+			return 0, fmt.Errorf("%s value %q overflows uint64", e.name, str)
+		}
+		n = n*base + uint64(digit)
+	}
+	return n, nil
+}
@@ -0,0 +1,92 @@
+package text
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Highlight wraps every case-insensitive occurrence of any of terms in openTag and closeTag
+// (e.g. "<mark>", "</mark>"). Longer terms win when terms overlap. The text is not escaped.
+func Highlight(str string, terms []string, openTag, closeTag string) string {
+	re := termsRegexp(terms)
+	if re == nil {
+		return str
+	}
+	return re.ReplaceAllStringFunc(str, func(match string) string {
+		return openTag + match + closeTag
+	})
+}
+
+// Excerpt returns the part of str around the first occurrence of any word of query,
+// with about radius characters of context on each side, cut at word boundaries and
+// marked with "..." where text was omitted. Without a match the start of str is used.
+func Excerpt(str, query string, radius int) string {
+	if radius < 0 {
+		radius = 0
+	}
+	runes := []rune(str)
+	center, matchEnd := 0, 0
+	if re := termsRegexp(strings.Fields(query)); re != nil {
+		if loc := re.FindStringIndex(str); loc != nil {
+			center = len([]rune(str[:loc[0]]))
+			matchEnd = len([]rune(str[:loc[1]]))
+		}
+	}
+
+	start := center - radius
+	if start <= 0 {
+		start = 0
+	} else {
+		// Move forward to the start of a word
+		for start < center && !unicode.IsSpace(runes[start-1]) {
+			start++
+		}
+	}
+
+	end := center + radius
+	if center == 0 {
+		end = 2 * radius
+	}
+	// Always keep the whole match, even if it is longer than radius
+	if end < matchEnd {
+		end = matchEnd
+	}
+	if end >= len(runes) {
+		end = len(runes)
+	} else {
+		// Move back to the end of a word
+		for end > matchEnd && end > center && !unicode.IsSpace(runes[end]) {
+			end--
+		}
+	}
+
+	excerpt := strings.TrimSpace(string(runes[start:end]))
+	if start > 0 {
+		excerpt = "..." + excerpt
+	}
+	if end < len(runes) {
+		excerpt = strings.TrimRight(excerpt, ".,;:") + "..."
+	}
+	return excerpt
+}
+
+// termsRegexp builds a case-insensitive regexp matching any of the non-empty terms,
+// longest first (nil if there are none)
+func termsRegexp(terms []string) *regexp.Regexp {
+	var quoted []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			quoted = append(quoted, regexp.QuoteMeta(term))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(quoted, func(i, j int) bool {
+		return len(quoted[i]) > len(quoted[j])
+	})
+	return regexp.MustCompile("(?i)" + strings.Join(quoted, "|"))
+}
@@ -0,0 +1,100 @@
+package text
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var nameAdjectives = []string{
+	"amber", "ancient", "autumn", "bold", "brave", "bright", "calm", "clever", "cosmic", "crimson",
+	"crisp", "daring", "dawn", "eager", "electric", "emerald", "fancy", "fast", "fierce", "gentle",
+	"golden", "grand", "happy", "hidden", "humble", "icy", "jolly", "lively", "lucky", "lunar",
+	"mellow", "misty", "noble", "polar", "proud", "quiet", "rapid", "royal", "rustic", "silent",
+	"silver", "snowy", "solar", "steady", "swift", "tidy", "vivid", "wild", "wise", "young",
+}
+
+var nameNouns = []string{
+	"anchor", "badger", "beacon", "breeze", "canyon", "cedar", "comet", "coral", "crane", "delta",
+	"dolphin", "eagle", "ember", "falcon", "fern", "forest", "fox", "galaxy", "glacier", "harbor",
+	"hawk", "heron", "island", "lake", "lantern", "leaf", "lion", "maple", "meadow", "meteor",
+	"moon", "mountain", "nebula", "oak", "ocean", "otter", "panda", "pebble", "pine", "planet",
+	"raven", "river", "rocket", "sparrow", "star", "summit", "thunder", "tiger", "valley", "wave",
+}
+
+// Character classes used by GenerateFromPattern
+const (
+	patternUpper = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	patternLower = "abcdefghijklmnopqrstuvwxyz"
+	patternDigit = "0123456789"
+)
+
+// GenerateNameSlug generates a readable random name like "brave-falcon-4821"
+// (adjective-noun-number) for resources such as projects or environments
+func GenerateNameSlug() (string, error) {
+	adjective, err := randomChoice(nameAdjectives)
+	if err != nil {
+		return "", err
+	}
+	noun, err := randomChoice(nameNouns)
+	if err != nil {
+		return "", err
+	}
+	number, err := GenerateFromPattern("9999")
+	if err != nil {
+		return "", err
+	}
+	return adjective + "-" + noun + "-" + number, nil
+}
+
+// GenerateFromPattern generates a random string shaped by pattern, e.g. coupon codes:
+// 'A' = uppercase letter, 'a' = lowercase letter, '9' = digit, 'X' = uppercase letter or digit.
+// Other characters are copied as-is; '\' copies the next character literally.
+//
+//	GenerateFromPattern("AAA-999") // "QZK-407"
+func GenerateFromPattern(pattern string) (string, error) {
+	var result strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		if escaped {
+			result.WriteRune(r)
+			escaped = false
+			continue
+		}
+
+		var charset string
+		switch r {
+		case '\\':
+			escaped = true
+			continue
+		case 'A':
+			charset = patternUpper
+		case 'a':
+			charset = patternLower
+		case '9':
+			charset = patternDigit
+		case 'X':
+			charset = patternUpper + patternDigit
+		default:
+			result.WriteRune(r)
+			continue
+		}
+
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate random character: %w", err)
+		}
+		result.WriteByte(charset[n.Int64()])
+	}
+	return result.String(), nil
+}
+
+// randomChoice picks a cryptographically random element of words
+func randomChoice(words []string) (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(words))))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate random choice: %w", err)
+	}
+	return words[n.Int64()], nil
+}
@@ -0,0 +1,334 @@
+package text
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// HTMLPolicy is a whitelist describing which HTML survives SanitizeHTML
+type HTMLPolicy struct {
+	// AllowedTags maps lowercase tag names to their allowed lowercase attribute names
+	AllowedTags map[string][]string
+	// AllowedSchemes lists URL schemes permitted in href and src attributes
+	// (relative URLs are always allowed; empty = http, https and mailto)
+	AllowedSchemes []string
+	// AddNoFollow adds rel="nofollow noopener" to links
+	AddNoFollow bool
+}
+
+// StrictHTMLPolicy returns a policy that allows no tags, leaving only escaped text
+func StrictHTMLPolicy() *HTMLPolicy {
+	return &HTMLPolicy{AllowedTags: map[string][]string{}}
+}
+
+// UGCHTMLPolicy returns a policy for user-generated content: basic formatting,
+// lists, quotes, code and links with rel="nofollow noopener"
+func UGCHTMLPolicy() *HTMLPolicy {
+	return &HTMLPolicy{
+		AllowedTags: map[string][]string{
+			"a": {"href", "title"}, "b": nil, "strong": nil, "i": nil, "em": nil, "u": nil,
+			"s": nil, "p": nil, "br": nil, "ul": nil, "ol": nil, "li": nil,
+			"blockquote": nil, "code": nil, "pre": nil,
+		},
+		AddNoFollow: true,
+	}
+}
+
+// rawTextTags are elements whose content is never text worth keeping
+var rawTextTags = map[string]bool{
+	"script": true, "style": true, "textarea": true, "title": true, "iframe": true,
+	"noscript": true, "noembed": true, "noframes": true, "xmp": true, "template": true,
+}
+
+// voidTags are elements that have no closing tag
+var voidTags = map[string]bool{
+	"br": true, "hr": true, "img": true, "wbr": true, "input": true, "meta": true, "link": true,
+}
+
+// blockTags are elements that separate words when stripped
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true, "td": true, "th": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"blockquote": true, "pre": true, "hr": true, "section": true, "article": true,
+}
+
+// htmlAttr is a parsed tag attribute
+type htmlAttr struct {
+	name, value string
+}
+
+// htmlToken is a piece of an HTML document: text, a start tag or an end tag
+type htmlToken struct {
+	text   string // Unescaped text for text tokens
+	tag    string // Lowercase tag name for tag tokens
+	end    bool
+	attrs  []htmlAttr
+	isText bool
+}
+
+// StripHTML removes tags, comments and script/style content, unescapes entities and
+// collapses whitespace, producing plain text suitable for storage or search indexing
+func StripHTML(str string) string {
+	var result strings.Builder
+	for _, token := range tokenizeHTML(str) {
+		if token.isText {
+			result.WriteString(token.text)
+		} else if blockTags[token.tag] {
+			result.WriteByte(' ')
+		}
+	}
+	return strings.Join(strings.Fields(result.String()), " ")
+}
+
+// SanitizeHTML keeps only the tags and attributes allowed by policy (nil = UGCHTMLPolicy).
+// Disallowed tags are removed but their text is kept, except for script, style and similar
+// elements which are dropped entirely. Text is re-escaped, URLs with disallowed schemes are
+// removed and unclosed tags are closed.
+func SanitizeHTML(str string, policy *HTMLPolicy) string {
+	if policy == nil {
+		policy = UGCHTMLPolicy()
+	}
+
+	var result strings.Builder
+	var open []string
+	for _, token := range tokenizeHTML(str) {
+		switch {
+		case token.isText:
+			result.WriteString(html.EscapeString(token.text))
+
+		case token.end:
+			if _, ok := policy.AllowedTags[token.tag]; !ok {
+				continue
+			}
+			// Close the most recent matching tag, closing anything opened inside it
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == token.tag {
+					for j := len(open) - 1; j >= i; j-- {
+						result.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+
+		default:
+			allowedAttrs, ok := policy.AllowedTags[token.tag]
+			if !ok {
+				continue
+			}
+			result.WriteString("<" + token.tag)
+			for _, attr := range token.attrs {
+				if !containsString(allowedAttrs, attr.name) {
+					continue
+				}
+				if (attr.name == "href" || attr.name == "src") && !policy.allowsURL(attr.value) {
+					continue
+				}
+				if attr.name == "rel" && policy.AddNoFollow && token.tag == "a" {
+					continue
+				}
+				result.WriteString(" " + attr.name + `="` + html.EscapeString(attr.value) + `"`)
+			}
+			if token.tag == "a" && policy.AddNoFollow {
+				result.WriteString(` rel="nofollow noopener"`)
+			}
+			result.WriteString(">")
+			if !voidTags[token.tag] {
+				open = append(open, token.tag)
+			}
+		}
+	}
+
+	for i := len(open) - 1; i >= 0; i-- {
+		result.WriteString("</" + open[i] + ">")
+	}
+	return result.String()
+}
+
+// allowsURL checks if a URL is relative or uses an allowed scheme
+func (p *HTMLPolicy) allowsURL(value string) bool {
+	// Browsers ignore whitespace and control characters inside schemes ("java\tscript:")
+	cleaned := strings.Map(func(r rune) rune {
+		if r <= ' ' || unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, value)
+
+	colon := strings.IndexByte(cleaned, ':')
+	if colon < 0 || strings.ContainsAny(cleaned[:colon], "/?#") {
+		return true
+	}
+
+	schemes := p.AllowedSchemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https", "mailto"}
+	}
+	return containsString(schemes, strings.ToLower(cleaned[:colon]))
+}
+
+// tokenizeHTML splits a document into text and tag tokens. It is lenient like browsers:
+// a '<' that does not start a tag is text, and unterminated tags are dropped.
+func tokenizeHTML(str string) []htmlToken {
+	var tokens []htmlToken
+	var text strings.Builder
+	flushText := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, htmlToken{text: html.UnescapeString(text.String()), isText: true})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(str); {
+		if str[i] != '<' {
+			next := strings.IndexByte(str[i:], '<')
+			if next < 0 {
+				next = len(str) - i
+			}
+			text.WriteString(str[i : i+next])
+			i += next
+			continue
+		}
+
+		rest := str[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			flushText()
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return tokens
+			}
+			i += 4 + end + 3
+
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			flushText()
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return tokens
+			}
+			i += end + 1
+
+		case len(rest) > 2 && rest[1] == '/' && isASCIILetter(rest[2]):
+			flushText()
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return tokens
+			}
+			name, _ := readTagName(rest[2:end])
+			tokens = append(tokens, htmlToken{tag: name, end: true})
+			i += end + 1
+
+		case len(rest) > 1 && isASCIILetter(rest[1]):
+			flushText()
+			token, n := readStartTag(rest)
+			if n < 0 {
+				return tokens
+			}
+			i += n
+			if rawTextTags[token.tag] {
+				closing := indexFold(str[i:], "</"+token.tag)
+				if closing < 0 {
+					return tokens
+				}
+				// Drop the element's content; its end tag is tokenized normally
+				i += closing
+			}
+			tokens = append(tokens, token)
+
+		default:
+			text.WriteByte('<')
+			i++
+		}
+	}
+
+	flushText()
+	return tokens
+}
+
+// readStartTag parses a start tag at the beginning of str, returning the number of
+// bytes consumed (-1 if the tag is not terminated)
+func readStartTag(str string) (htmlToken, int) {
+	name, n := readTagName(str[1:])
+	token := htmlToken{tag: name}
+	i := 1 + n
+
+	for i < len(str) {
+		c := str[i]
+		switch {
+		case c == '>':
+			return token, i + 1
+		case c == '/' || isHTMLSpace(c):
+			i++
+		default:
+			start := i
+			for i < len(str) && !isHTMLSpace(str[i]) && str[i] != '=' && str[i] != '>' && str[i] != '/' {
+				i++
+			}
+			attr := htmlAttr{name: strings.ToLower(str[start:i])}
+			for i < len(str) && isHTMLSpace(str[i]) {
+				i++
+			}
+			if i < len(str) && str[i] == '=' {
+				i++
+				for i < len(str) && isHTMLSpace(str[i]) {
+					i++
+				}
+				if i < len(str) && (str[i] == '"' || str[i] == '\'') {
+					quote := str[i]
+					end := strings.IndexByte(str[i+1:], quote)
+					if end < 0 {
+						return token, -1
+					}
+					attr.value = str[i+1 : i+1+end]
+					i += end + 2
+				} else {
+					start = i
+					for i < len(str) && !isHTMLSpace(str[i]) && str[i] != '>' {
+						i++
+					}
+					attr.value = str[start:i]
+				}
+			}
+			attr.value = html.UnescapeString(attr.value)
+			token.attrs = append(token.attrs, attr)
+		}
+	}
+	return token, -1
+}
+
+// readTagName reads a lowercase tag name, returning it and its length
+func readTagName(str string) (string, int) {
+	n := 0
+	for n < len(str) && !isHTMLSpace(str[n]) && str[n] != '>' && str[n] != '/' {
+		n++
+	}
+	return strings.ToLower(str[:n]), n
+}
+
+// indexFold finds substr in str ignoring ASCII case
+func indexFold(str, substr string) int {
+	for i := 0; i+len(substr) <= len(str); i++ {
+		if strings.EqualFold(str[i:i+len(substr)], substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
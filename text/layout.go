@@ -0,0 +1,144 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Alignment controls how text is positioned within a column
+type Alignment int
+
+const (
+	AlignLeft   Alignment = iota // Pad on the right
+	AlignRight                   // Pad on the left
+	AlignCenter                  // Pad on both sides
+)
+
+// Wrap breaks text into lines at most width display cells wide, breaking at whitespace.
+// Existing line breaks are kept; words longer than width are placed on their own line unbroken.
+func Wrap(str string, width int) string {
+	return strings.Join(WrapLines(str, width), "\n")
+}
+
+// WrapLines is like Wrap but returns the individual lines
+func WrapLines(str string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(str, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 || width <= 0 {
+			lines = append(lines, strings.TrimRightFunc(paragraph, unicode.IsSpace))
+			continue
+		}
+
+		line := words[0]
+		lineWidth := Width(line)
+		for _, word := range words[1:] {
+			wordWidth := Width(word)
+			if lineWidth+1+wordWidth > width {
+				lines = append(lines, line)
+				line, lineWidth = word, wordWidth
+				continue
+			}
+			line += " " + word
+			lineWidth += 1 + wordWidth
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Indent prefixes every non-empty line with prefix
+func Indent(str, prefix string) string {
+	lines := strings.Split(str, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Dedent removes the longest common leading whitespace from every non-empty line
+// (useful for multi-line raw string literals). Whitespace-only lines become empty.
+func Dedent(str string) string {
+	lines := strings.Split(str, "\n")
+
+	var margin string
+	found := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeftFunc(line, unicode.IsSpace))]
+		if !found {
+			margin, found = indent, true
+			continue
+		}
+		for !strings.HasPrefix(indent, margin) {
+			margin = margin[:len(margin)-1]
+		}
+	}
+
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ""
+		} else {
+			lines[i] = strings.TrimPrefix(line, margin)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CenterPad centers string within width display cells using padChar;
+// the extra cell of an odd padding goes to the right
+func CenterPad(str string, width int, padChar rune) string {
+	padding := width - Width(str)
+	if padding <= 0 {
+		return str
+	}
+	left := padding / 2
+	return strings.Repeat(string(padChar), left) + str + strings.Repeat(string(padChar), padding-left)
+}
+
+// Align pads string with spaces to width display cells using the given alignment
+func Align(str string, width int, alignment Alignment) string {
+	switch alignment {
+	case AlignRight:
+		return PadWidth(str, width, true)
+	case AlignCenter:
+		return CenterPad(str, width, ' ')
+	default:
+		return PadWidth(str, width, false)
+	}
+}
+
+// AlignColumns renders rows as text columns separated by separator, each column as wide
+// as its widest cell. alignments are applied per column (missing entries = AlignLeft);
+// trailing spaces are trimmed from each line.
+func AlignColumns(rows [][]string, separator string, alignments ...Alignment) string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := Width(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	lines := make([]string, len(rows))
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			alignment := AlignLeft
+			if i < len(alignments) {
+				alignment = alignments[i]
+			}
+			cells[i] = Align(cell, widths[i], alignment)
+		}
+		lines[r] = strings.TrimRight(strings.Join(cells, separator), " ")
+	}
+	return strings.Join(lines, "\n")
+}
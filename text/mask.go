@@ -0,0 +1,94 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultMaskRune is the rune the PII masking presets replace hidden characters with
+const DefaultMaskRune = '*'
+
+// MaskPattern masks str according to pattern, matched rune by rune: '#' keeps the
+// character, '*' replaces it with maskRune, and any other pattern rune is written as-is
+// without consuming input. Input beyond the end of the pattern is masked.
+//
+//	MaskPattern("4111111111111111", "****-****-****-####", 'X') // "XXXX-XXXX-XXXX-1111"
+func MaskPattern(str, pattern string, maskRune rune) string {
+	input := []rune(str)
+	var result strings.Builder
+	i := 0
+	for _, p := range pattern {
+		if i >= len(input) {
+			break
+		}
+		switch p {
+		case '#':
+			result.WriteRune(input[i])
+			i++
+		case '*':
+			result.WriteRune(maskRune)
+			i++
+		default:
+			result.WriteRune(p)
+		}
+	}
+	for ; i < len(input); i++ {
+		result.WriteRune(maskRune)
+	}
+	return result.String()
+}
+
+// MaskPhone masks the digits of a phone number except the last 4, keeping a leading
+// '+' and separators (e.g. "+994 50 123 45 67" -> "+*** ** *** 45 67")
+func MaskPhone(phone string) string {
+	return maskDigitsExceptLast(phone, 4)
+}
+
+// MaskCard masks the digits of a payment card number except the last 4, keeping
+// separators (e.g. "4111 1111 1111 1234" -> "**** **** **** 1234")
+func MaskCard(card string) string {
+	return maskDigitsExceptLast(card, 4)
+}
+
+// MaskName keeps the first letter of each word of a name and masks the rest
+// (e.g. "John Smith" -> "J*** S****")
+func MaskName(name string) string {
+	var result strings.Builder
+	first := true
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r) || r == '-':
+			result.WriteRune(r)
+			first = true
+		case first:
+			result.WriteRune(r)
+			first = false
+		default:
+			result.WriteRune(DefaultMaskRune)
+		}
+	}
+	return result.String()
+}
+
+// maskDigitsExceptLast replaces all digits but the last keep with DefaultMaskRune
+func maskDigitsExceptLast(str string, keep int) string {
+	digits := 0
+	for _, r := range str {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+
+	var result strings.Builder
+	seen := 0
+	for _, r := range str {
+		if unicode.IsDigit(r) {
+			seen++
+			if seen <= digits-keep {
+				r = DefaultMaskRune
+			}
+		}
+		result.WriteRune(r)
+	}
+	return result.String()
+}
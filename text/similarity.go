@@ -0,0 +1,227 @@
+package text
+
+import (
+	"sort"
+	"strings"
+)
+
+// SimilarityFunc scores how similar two strings are, from 0 (different) to 1 (identical)
+type SimilarityFunc func(a, b string) float64
+
+// FuzzyMatch is a candidate matched by FuzzyFind
+type FuzzyMatch struct {
+	Candidate string
+	Index     int     // Position of the candidate in the input slice
+	Score     float64 // Similarity score in [0, 1]
+}
+
+// LevenshteinSimilarity normalizes the Levenshtein distance to a score in [0, 1]. Like the
+// other similarity metrics it works on runes, so non-ASCII text scores comparably.
+func LevenshteinSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(runeLevenshteinDistance(ra, rb))/float64(longest)
+}
+
+// runeLevenshteinDistance is LevenshteinDistance over runes, keeping only two matrix rows
+func runeLevenshteinDistance(ra, rb []rune) int {
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 0
+			if ra[i-1] != rb[j-1] {
+				cost = 1
+			}
+			current[j] = min(
+				previous[j]+1,      // deletion
+				current[j-1]+1,     // insertion
+				previous[j-1]+cost, // substitution
+			)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// DamerauLevenshteinDistance calculates the edit distance between two strings counting
+// adjacent transpositions as a single edit (optimal string alignment). Works on runes.
+func DamerauLevenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	matrix := make([][]int, len(ra)+1)
+	for i := range matrix {
+		matrix[i] = make([]int, len(rb)+1)
+		matrix[i][0] = i
+	}
+	for j := 0; j <= len(rb); j++ {
+		matrix[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 0
+			if ra[i-1] != rb[j-1] {
+				cost = 1
+			}
+
+			matrix[i][j] = min(
+				matrix[i-1][j]+1,      // deletion
+				matrix[i][j-1]+1,      // insertion
+				matrix[i-1][j-1]+cost, // substitution
+			)
+
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				if transposition := matrix[i-2][j-2] + 1; transposition < matrix[i][j] {
+					matrix[i][j] = transposition
+				}
+			}
+		}
+	}
+
+	return matrix[len(ra)][len(rb)]
+}
+
+// JaroSimilarity calculates the Jaro similarity between two strings in [0, 1]. Works on runes.
+func JaroSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	window := longest/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		start := i - window
+		if start < 0 {
+			start = 0
+		}
+		end := i + window + 1
+		if end > len(rb) {
+			end = len(rb)
+		}
+		for j := start; j < end; j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	// Count matched characters that appear in a different order
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	return (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+}
+
+// JaroWinklerSimilarity calculates the Jaro-Winkler similarity between two strings in [0, 1],
+// boosting the Jaro score of strings sharing a common prefix (up to 4 characters)
+func JaroWinklerSimilarity(a, b string) float64 {
+	jaro := JaroSimilarity(a, b)
+
+	ra, rb := []rune(a), []rune(b)
+	prefix := 0
+	for prefix < len(ra) && prefix < len(rb) && prefix < 4 && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// Trigrams returns the set of case-insensitive character trigrams of a string.
+// Each word is padded with spaces so short words and word boundaries produce trigrams.
+func Trigrams(str string) map[string]struct{} {
+	trigrams := make(map[string]struct{})
+	for _, word := range strings.Fields(strings.ToLower(str)) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			trigrams[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+	return trigrams
+}
+
+// TrigramSimilarity calculates the ratio of shared trigrams to all trigrams of two strings in [0, 1]
+func TrigramSimilarity(a, b string) float64 {
+	ta, tb := Trigrams(a), Trigrams(b)
+	if len(ta) == 0 && len(tb) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for trigram := range ta {
+		if _, ok := tb[trigram]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// FuzzyFind returns the candidates whose Jaro-Winkler similarity to query (compared
+// case-insensitively) is at least threshold, best match first
+func FuzzyFind(query string, candidates []string, threshold float64) []FuzzyMatch {
+	return FuzzyFindWith(query, candidates, threshold, func(a, b string) float64 {
+		return JaroWinklerSimilarity(Normalize(a), Normalize(b))
+	})
+}
+
+// FuzzyFindWith returns the candidates scoring at least threshold with the given
+// similarity function, best match first. Ties keep the order of candidates.
+func FuzzyFindWith(query string, candidates []string, threshold float64, similarity SimilarityFunc) []FuzzyMatch {
+	var matches []FuzzyMatch
+	for i, candidate := range candidates {
+		if score := similarity(query, candidate); score >= threshold {
+			matches = append(matches, FuzzyMatch{Candidate: candidate, Index: i, Score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches
+}
@@ -11,59 +11,28 @@ func Normalize(input string) string {
 	return strings.ToLower(strings.TrimSpace(input))
 }
 
-// ToSnakeCase converts string to snake_case
+// ToSnakeCase converts string to snake_case ("HTTPServer" -> "http_server")
 func ToSnakeCase(str string) string {
-	var result strings.Builder
-	for i, r := range str {
-		if i > 0 && unicode.IsUpper(r) {
-			result.WriteRune('_')
-		}
-		result.WriteRune(unicode.ToLower(r))
-	}
-	return result.String()
+	return ConvertCase(str, CaseSnake)
 }
 
 // ToCamelCase converts string to camelCase
 func ToCamelCase(str string) string {
-	if str == "" {
-		return ""
-	}
-
-	words := strings.FieldsFunc(str, func(r rune) bool {
-		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
-	})
-
-	if len(words) == 0 {
-		return ""
-	}
-
-	var result strings.Builder
-	result.WriteString(strings.ToLower(words[0]))
-
-	for _, word := range words[1:] {
-		if len(word) > 0 {
-			result.WriteString(strings.ToUpper(string(word[0])) + strings.ToLower(word[1:]))
-		}
-	}
-
-	return result.String()
+	return ConvertCase(str, CaseCamel)
 }
 
 // ToPascalCase converts string to PascalCase
 func ToPascalCase(str string) string {
-	camel := ToCamelCase(str)
-	if len(camel) == 0 {
-		return ""
-	}
-	return strings.ToUpper(string(camel[0])) + camel[1:]
+	return ConvertCase(str, CasePascal)
 }
 
 // ToKebabCase converts string to kebab-case
 func ToKebabCase(str string) string {
-	return strings.ReplaceAll(ToSnakeCase(str), "_", "-")
+	return ConvertCase(str, CaseKebab)
 }
 
-// Truncate truncates a string to the specified length
+// Truncate truncates a string to the specified length in bytes.
+// Use TruncateRunes for strings that may contain multi-byte characters.
 func Truncate(str string, length int) string {
 	if len(str) <= length {
 		return str
@@ -71,7 +40,8 @@ func Truncate(str string, length int) string {
 	return str[:length]
 }
 
-// TruncateWithEllipsis truncates a string and adds ellipsis
+// TruncateWithEllipsis truncates a string to length bytes and adds ellipsis.
+// Use TruncateRunesWithEllipsis for strings that may contain multi-byte characters.
 func TruncateWithEllipsis(str string, length int) string {
 	if len(str) <= length {
 		return str
@@ -126,51 +96,31 @@ func EndsWithAny(str string, suffixes ...string) bool {
 	return false
 }
 
-// RemoveAccents removes accents from characters
+// RemoveAccents removes accents from characters (see StripDiacritics);
+// Latin letters with strokes such as ł or đ are replaced by their base letter
 func RemoveAccents(str string) string {
-	// Simple ASCII transliteration
-	replacements := map[rune]string{
-		'á': "a", 'à': "a", 'ä': "a", 'â': "a", 'ā': "a", 'ă': "a", 'ą': "a",
-		'é': "e", 'è': "e", 'ë': "e", 'ê': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e",
-		'í': "i", 'ì': "i", 'ï': "i", 'î': "i", 'ī': "i", 'ĭ': "i", 'į': "i",
-		'ó': "o", 'ò': "o", 'ö': "o", 'ô': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
-		'ú': "u", 'ù': "u", 'ü': "u", 'û': "u", 'ū': "u", 'ŭ': "u", 'ű': "u", 'ų': "u",
-		'ñ': "n", 'ń': "n", 'ň': "n", 'ņ': "n",
-		'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c",
-		'ý': "y", 'ÿ': "y", 'ŷ': "y",
-		'ž': "z", 'ź': "z", 'ż': "z",
-		'š': "s", 'ś': "s", 'ş': "s", 'ŝ': "s",
-		'đ': "d", 'ď': "d",
-		'ř': "r", 'ŕ': "r", 'ŗ': "r",
-		'ł': "l", 'ľ': "l", 'ŀ': "l", 'ļ': "l",
-		'ť': "t", 'ţ': "t",
-		'ğ': "g", 'ĝ': "g", 'ġ': "g", 'ģ': "g",
-		'ĥ': "h", 'ħ': "h",
-		'ĵ': "j",
-		'ķ': "k",
-		'ĺ': "l",
-		'ŵ': "w",
+	// Letters that do not decompose into a base letter plus a combining mark
+	replacements := map[rune]rune{
+		'đ': 'd', 'ł': 'l', 'ŀ': 'l', 'ħ': 'h', 'ŧ': 't', 'ø': 'o',
 	}
 
 	var result strings.Builder
-	for _, r := range str {
+	for _, r := range StripDiacritics(str) {
 		if replacement, exists := replacements[unicode.ToLower(r)]; exists {
 			if unicode.IsUpper(r) {
-				result.WriteString(strings.ToUpper(replacement))
-			} else {
-				result.WriteString(replacement)
+				replacement = unicode.ToUpper(replacement)
 			}
-		} else {
-			result.WriteRune(r)
+			r = replacement
 		}
+		result.WriteRune(r)
 	}
 	return result.String()
 }
 
-// ToSlug converts string to URL-friendly slug
+// ToSlug converts string to URL-friendly slug, transliterating non-Latin scripts
 func ToSlug(str string) string {
-	// Remove accents
-	slug := RemoveAccents(str)
+	// Transliterate to ASCII
+	slug := Transliterate(str)
 
 	// Convert to lowercase
 	slug = strings.ToLower(slug)
@@ -202,7 +152,8 @@ func CharCount(str string) int {
 	return count
 }
 
-// Pad pads string to specified length with given character
+// Pad pads string to specified length in bytes with given character.
+// Use PadRunes or PadWidth for strings that may contain multi-byte characters.
 func Pad(str string, length int, padChar rune, leftPad bool) string {
 	if len(str) >= length {
 		return str
@@ -260,14 +211,14 @@ func MaskEmail(email string) string {
 		return email
 	}
 
-	username := parts[0]
+	username := []rune(parts[0])
 	domain := parts[1]
 
 	if len(username) <= 2 {
-		return "*@" + domain
+		return string(DefaultMaskRune) + "@" + domain
 	}
 
-	maskedUsername := string(username[0]) + strings.Repeat("*", len(username)-2) + string(username[len(username)-1])
+	maskedUsername := string(username[0]) + strings.Repeat(string(DefaultMaskRune), len(username)-2) + string(username[len(username)-1])
 	return maskedUsername + "@" + domain
 }
 
@@ -0,0 +1,130 @@
+package text
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"reflect"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+	"unicode"
+
+	"github.com/kerimovok/go-pkg-utils/datetime"
+)
+
+var (
+	templateFuncsMu sync.RWMutex
+	templateFuncs   = map[string]any{
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"snake":      ToSnakeCase,
+		"camel":      ToCamelCase,
+		"pascal":     ToPascalCase,
+		"kebab":      ToKebabCase,
+		"slug":       ToSlug,
+		"truncate":   func(length int, str string) string { return TruncateRunesWithEllipsis(str, length) },
+		"maskEmail":  MaskEmail,
+		"default":    templateDefault,
+		"formatDate": func(layout string, t time.Time) string { return t.Format(layout) },
+		"timeAgo":    datetime.TimeAgo,
+		"timeUntil":  datetime.TimeUntil,
+		"duration":   datetime.FormatDuration,
+	}
+)
+
+// RegisterTemplateFunc adds a function available to all templates rendered by this package.
+// Registering an existing name replaces it. It panics if name is not an identifier or fn is
+// not a function returning one value, or a value and an error, like template.FuncMap.
+func RegisterTemplateFunc(name string, fn any) {
+	if !isTemplateFuncName(name) {
+		panic(fmt.Sprintf("text: template function name '%s' is not a valid identifier", name))
+	}
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		panic(fmt.Sprintf("text: template function '%s' is not a function", name))
+	}
+	t := v.Type()
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	if t.NumOut() != 1 && (t.NumOut() != 2 || t.Out(1) != errorType) {
+		panic(fmt.Sprintf("text: template function '%s' must return one value, or a value and an error", name))
+	}
+
+	templateFuncsMu.Lock()
+	defer templateFuncsMu.Unlock()
+	templateFuncs[name] = fn
+}
+
+// isTemplateFuncName checks that name is an identifier usable in templates
+func isTemplateFuncName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// TemplateFuncs returns a copy of the registered template functions
+func TemplateFuncs() map[string]any {
+	templateFuncsMu.RLock()
+	defer templateFuncsMu.RUnlock()
+	funcs := make(map[string]any, len(templateFuncs))
+	for name, fn := range templateFuncs {
+		funcs[name] = fn
+	}
+	return funcs
+}
+
+// RenderTemplate renders a text/template with the registered functions.
+// Referencing a key missing from data is an error rather than "<no value>".
+func RenderTemplate(tmpl string, data map[string]any) (string, error) {
+	t, err := texttemplate.New("template").
+		Option("missingkey=error").
+		Funcs(TemplateFuncs()).
+		Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// RenderHTMLTemplate is like RenderTemplate but uses html/template, contextually
+// escaping data (e.g. for HTML email and notification bodies)
+func RenderHTMLTemplate(tmpl string, data map[string]any) (string, error) {
+	t, err := htmltemplate.New("template").
+		Option("missingkey=error").
+		Funcs(TemplateFuncs()).
+		Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// templateDefault returns fallback when value is nil or an empty string,
+// e.g. {{ .name | default "there" }}
+func templateDefault(fallback, value any) any {
+	if value == nil {
+		return fallback
+	}
+	if s, ok := value.(string); ok && s == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,70 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations maps lowercase letters that do not decompose into a base Latin
+// letter plus combining marks (so StripDiacritics leaves them untouched) to ASCII
+var transliterations = map[rune]string{
+	// Latin letters with strokes, ligatures and other non-decomposable forms
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'đ': "d", 'ð': "d", 'þ': "th",
+	'ł': "l", 'ŀ': "l", 'ħ': "h", 'ı': "i", 'ə': "e", 'ŧ': "t", 'ĸ': "k", 'ŋ': "n",
+
+	// Cyrillic (Russian, Ukrainian, Belarusian, Serbian, Kazakh, Azerbaijani)
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'ђ': "dj", 'е': "e",
+	'ё': "yo", 'є': "ye", 'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi", 'й': "y",
+	'ј': "j", 'к': "k", 'л': "l", 'љ': "lj", 'м': "m", 'н': "n", 'њ': "nj", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'ћ': "c", 'у': "u", 'ў': "u", 'ф': "f",
+	'х': "kh", 'ц': "ts", 'ч': "ch", 'џ': "dz", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y",
+	'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'ә': "e", 'ғ': "g", 'қ': "q", 'ң': "n",
+	'ө': "o", 'ұ': "u", 'ү': "u", 'һ': "h", 'ҹ': "j", 'ҝ': "g",
+
+	// Greek (accented forms are reduced to these by StripDiacritics)
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th",
+	'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p",
+	'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps",
+	'ω': "o",
+}
+
+// StripDiacritics removes combining marks after canonical decomposition (NFD),
+// e.g. "Crème Brûlée" -> "Creme Brulee", "İstanbul" -> "Istanbul".
+// Letters that do not decompose (ß, ł, Cyrillic, ...) are kept; see Transliterate.
+func StripDiacritics(str string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, str)
+	if err != nil {
+		return str
+	}
+	return result
+}
+
+// Transliterate converts text to its closest ASCII representation: diacritics are stripped
+// and Latin special letters, Cyrillic and Greek are romanized. Uppercase letters produce
+// capitalized replacements ("Ж" -> "Zh"). Other characters that are not ASCII are kept.
+func Transliterate(str string) string {
+	var result strings.Builder
+	for _, r := range StripDiacritics(str) {
+		if r < unicode.MaxASCII {
+			result.WriteRune(r)
+			continue
+		}
+
+		lower := unicode.ToLower(r)
+		replacement, exists := transliterations[lower]
+		if !exists {
+			result.WriteRune(r)
+			continue
+		}
+		if r != lower && replacement != "" {
+			replacement = strings.ToUpper(replacement[:1]) + replacement[1:]
+		}
+		result.WriteString(replacement)
+	}
+	return result.String()
+}
@@ -0,0 +1,148 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/clipperhouse/uax29/v2/graphemes"
+	"github.com/mattn/go-runewidth"
+)
+
+// Graphemes splits a string into grapheme clusters (user-perceived characters),
+// e.g. "é" or a flag emoji count as one
+func Graphemes(str string) []string {
+	result := make([]string, 0, len(str))
+	iter := graphemes.FromString(str)
+	for iter.Next() {
+		result = append(result, iter.Value())
+	}
+	return result
+}
+
+// RuneCount returns the number of runes (code points) in a string
+func RuneCount(str string) int {
+	return utf8.RuneCountInString(str)
+}
+
+// GraphemeCount returns the number of grapheme clusters in a string
+func GraphemeCount(str string) int {
+	count := 0
+	iter := graphemes.FromString(str)
+	for iter.Next() {
+		count++
+	}
+	return count
+}
+
+// Width returns the display width of a string in monospace terminals
+// (East Asian wide characters and most emoji count as 2, combining marks as 0)
+func Width(str string) int {
+	return runewidth.StringWidth(str)
+}
+
+// TruncateRunes truncates a string to at most length runes without splitting
+// multi-byte characters or grapheme clusters (unlike the byte-based Truncate)
+func TruncateRunes(str string, length int) string {
+	if length <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(str) <= length {
+		return str
+	}
+
+	runes := 0
+	end := 0
+	iter := graphemes.FromString(str)
+	for iter.Next() {
+		runes += utf8.RuneCountInString(iter.Value())
+		if runes > length {
+			break
+		}
+		end = iter.End()
+	}
+	return str[:end]
+}
+
+// TruncateRunesWithEllipsis truncates a string to at most length runes including "..."
+func TruncateRunesWithEllipsis(str string, length int) string {
+	if utf8.RuneCountInString(str) <= length {
+		return str
+	}
+	if length <= 3 {
+		return TruncateRunes(str, length)
+	}
+	return TruncateRunes(str, length-3) + "..."
+}
+
+// TruncateGraphemes truncates a string to at most length grapheme clusters
+func TruncateGraphemes(str string, length int) string {
+	if length <= 0 {
+		return ""
+	}
+
+	count := 0
+	iter := graphemes.FromString(str)
+	for iter.Next() {
+		count++
+		if count > length {
+			return str[:iter.Start()]
+		}
+	}
+	return str
+}
+
+// TruncateWidth truncates a string to at most width display columns, appending tail
+// (e.g. "...") when truncated; the tail counts towards the width
+func TruncateWidth(str string, width int, tail string) string {
+	return runewidth.Truncate(str, width, tail)
+}
+
+// TruncateWords truncates a string to at most maxWords words, appending "..." when truncated.
+// Whitespace between the kept words is preserved.
+func TruncateWords(str string, maxWords int) string {
+	if maxWords <= 0 {
+		return ""
+	}
+
+	words := 0
+	inWord := false
+	for i, r := range str {
+		if unicode.IsSpace(r) {
+			inWord = false
+			continue
+		}
+		if !inWord {
+			inWord = true
+			words++
+			if words > maxWords {
+				return strings.TrimRightFunc(str[:i], unicode.IsSpace) + "..."
+			}
+		}
+	}
+	return str
+}
+
+// PadRunes pads a string to length grapheme clusters with padChar
+// (unlike the byte-based Pad, multi-byte characters count once)
+func PadRunes(str string, length int, padChar rune, leftPad bool) string {
+	count := GraphemeCount(str)
+	if count >= length {
+		return str
+	}
+
+	padding := strings.Repeat(string(padChar), length-count)
+	if leftPad {
+		return padding + str
+	}
+	return str + padding
+}
+
+// PadWidth pads a string with spaces to the given display width, for aligning
+// columns that contain wide (e.g. CJK) characters
+func PadWidth(str string, width int, leftPad bool) string {
+	if leftPad {
+		return runewidth.FillLeft(str, width)
+	}
+	return runewidth.FillRight(str, width)
+}
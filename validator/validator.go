@@ -135,6 +135,12 @@ func getFieldName(field reflect.StructField) string {
 	return field.Name
 }
 
+// ValidateValue validates a single value against validation tags, for rules whose parameters
+// are only known at runtime (e.g. a per-resource sortfield whitelist)
+func ValidateValue(fieldName string, value interface{}, tag string) ValidationErrors {
+	return validateField(fieldName, value, tag)
+}
+
 // validateField validates a single field value against validation tags
 func validateField(fieldName string, value interface{}, tag string) ValidationErrors {
 	var errors ValidationErrors
@@ -142,6 +148,13 @@ func validateField(fieldName string, value interface{}, tag string) ValidationEr
 	// Parse validation tags
 	rules := strings.Split(tag, ",")
 
+	// omitempty skips all other rules for empty values
+	for _, rule := range rules {
+		if strings.TrimSpace(rule) == "omitempty" && isEmpty(value) {
+			return nil
+		}
+	}
+
 	for _, rule := range rules {
 		rule = strings.TrimSpace(rule)
 		if rule == "" {
@@ -226,6 +239,12 @@ func applyValidationRule(fieldName string, value interface{}, ruleName, param st
 		return validateDate(fieldName, value)
 	case "datetime":
 		return validateDateTime(fieldName, value)
+	case "oneof":
+		return validateOneOf(fieldName, value, param)
+	case "sortfield":
+		return validateSortField(fieldName, value, param)
+	case "filterexpr":
+		return validateFilterExpr(fieldName, value, param)
 	}
 
 	return nil
@@ -0,0 +1,249 @@
+package validator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	whitelistsMu sync.RWMutex
+	whitelists   = make(map[string]map[string]bool)
+
+	// filterOperators are the operators accepted by the filterexpr rule; the filter package
+	// registers its built-in and custom operators here
+	filterOperators = make(map[string]bool)
+)
+
+// RegisterWhitelist registers (or replaces) a named set of allowed values
+// referenced by the sortfield=<name> and filterexpr=<name> rules
+func RegisterWhitelist(name string, values ...string) {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+
+	whitelistsMu.Lock()
+	defer whitelistsMu.Unlock()
+	whitelists[name] = set
+}
+
+// RegisterWhitelistFromMap registers the keys of a map (e.g. filter.Config.AllowedFields
+// or a sort field-to-column map) as a named whitelist
+func RegisterWhitelistFromMap[V any](name string, m map[string]V) {
+	values := make([]string, 0, len(m))
+	for k := range m {
+		values = append(values, k)
+	}
+	RegisterWhitelist(name, values...)
+}
+
+// UnregisterWhitelist removes a named whitelist
+func UnregisterWhitelist(name string) {
+	whitelistsMu.Lock()
+	defer whitelistsMu.Unlock()
+	delete(whitelists, name)
+}
+
+// RegisterFilterOperators adds operators accepted by filterexpr. The filter package registers
+// its operators (including those added with filter.RegisterOperator) when it is imported.
+func RegisterFilterOperators(operators ...string) {
+	whitelistsMu.Lock()
+	defer whitelistsMu.Unlock()
+	for _, op := range operators {
+		filterOperators[op] = true
+	}
+}
+
+// UnregisterFilterOperators removes operators accepted by filterexpr
+func UnregisterFilterOperators(operators ...string) {
+	whitelistsMu.Lock()
+	defer whitelistsMu.Unlock()
+	for _, op := range operators {
+		delete(filterOperators, op)
+	}
+}
+
+// IsWhitelisted checks if value is part of the named whitelist
+func IsWhitelisted(name, value string) bool {
+	whitelistsMu.RLock()
+	defer whitelistsMu.RUnlock()
+	set, ok := whitelists[name]
+	return ok && set[value]
+}
+
+// whitelistExists checks if a named whitelist was registered
+func whitelistExists(name string) bool {
+	whitelistsMu.RLock()
+	defer whitelistsMu.RUnlock()
+	_, ok := whitelists[name]
+	return ok
+}
+
+// isFilterOperator checks if op is an accepted filter operator
+func isFilterOperator(op string) bool {
+	whitelistsMu.RLock()
+	defer whitelistsMu.RUnlock()
+	return filterOperators[op]
+}
+
+// filterOperatorsBySuffixLength returns accepted operators, longest first
+func filterOperatorsBySuffixLength() []string {
+	whitelistsMu.RLock()
+	ops := make([]string, 0, len(filterOperators))
+	for op := range filterOperators {
+		ops = append(ops, op)
+	}
+	whitelistsMu.RUnlock()
+
+	sort.Slice(ops, func(i, j int) bool { return len(ops[i]) > len(ops[j]) })
+	return ops
+}
+
+// validateOneOf validates that a string value is one of the space-separated options
+func validateOneOf(fieldName string, value interface{}, param string) *FieldError {
+	str, ok := value.(string)
+	if !ok {
+		return &FieldError{
+			Field:   fieldName,
+			Message: "oneof validation requires string value",
+			Tag:     "oneof",
+		}
+	}
+
+	for _, option := range strings.Fields(param) {
+		if str == option {
+			return nil
+		}
+	}
+
+	return &FieldError{
+		Field:   fieldName,
+		Message: fmt.Sprintf("value must be one of: %s", strings.Join(strings.Fields(param), ", ")),
+		Value:   str,
+		Tag:     "oneof",
+	}
+}
+
+// validateSortField validates a sort field (or comma-separated list with optional -/+ prefixes)
+// against a registered whitelist
+func validateSortField(fieldName string, value interface{}, whitelist string) *FieldError {
+	str, ok := value.(string)
+	if !ok {
+		return &FieldError{
+			Field:   fieldName,
+			Message: "sortfield validation requires string value",
+			Tag:     "sortfield",
+		}
+	}
+
+	if whitelist == "" || !whitelistExists(whitelist) {
+		return &FieldError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("sortfield whitelist '%s' is not registered", whitelist),
+			Tag:     "sortfield",
+		}
+	}
+
+	for _, part := range strings.Split(str, ",") {
+		part = strings.TrimLeft(strings.TrimSpace(part), "+-")
+		if part == "" {
+			continue
+		}
+		if !IsWhitelisted(whitelist, part) {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("field '%s' is not allowed for sorting", part),
+				Value:   str,
+				Tag:     "sortfield",
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateFilterExpr validates filter expressions in the "field_operator" or
+// "field_operator=value" form (string, "&"-separated string or []string).
+// The operator must be a known filter operator; with a whitelist parameter
+// the field must also be part of that whitelist.
+func validateFilterExpr(fieldName string, value interface{}, whitelist string) *FieldError {
+	var exprs []string
+	switch v := value.(type) {
+	case string:
+		exprs = strings.Split(v, "&")
+	case []string:
+		exprs = v
+	default:
+		return &FieldError{
+			Field:   fieldName,
+			Message: "filterexpr validation requires string or []string value",
+			Tag:     "filterexpr",
+		}
+	}
+
+	if whitelist != "" && !whitelistExists(whitelist) {
+		return &FieldError{
+			Field:   fieldName,
+			Message: fmt.Sprintf("filterexpr whitelist '%s' is not registered", whitelist),
+			Tag:     "filterexpr",
+		}
+	}
+
+	for _, expr := range exprs {
+		expr = strings.TrimSpace(expr)
+		if expr == "" {
+			continue
+		}
+
+		key, _, _ := strings.Cut(expr, "=")
+		field, operator, ok := splitFilterKey(key)
+		if !ok {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("invalid filter expression '%s' (expected field_operator)", expr),
+				Value:   expr,
+				Tag:     "filterexpr",
+			}
+		}
+
+		if whitelist != "" && !IsWhitelisted(whitelist, field) {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("field '%s' is not allowed for filtering", field),
+				Value:   expr,
+				Tag:     "filterexpr",
+			}
+		}
+
+		if !isFilterOperator(operator) {
+			return &FieldError{
+				Field:   fieldName,
+				Message: fmt.Sprintf("invalid operator '%s' for field '%s'", operator, field),
+				Value:   expr,
+				Tag:     "filterexpr",
+			}
+		}
+	}
+
+	return nil
+}
+
+// splitFilterKey splits "field_operator" into its parts, matching known operators
+// (including ones with underscores such as not_in) before falling back to the last segment
+func splitFilterKey(key string) (field, operator string, ok bool) {
+	lower := strings.ToLower(key)
+	for _, op := range filterOperatorsBySuffixLength() {
+		suffix := "_" + op
+		if strings.HasSuffix(lower, suffix) && len(key) > len(suffix) {
+			return key[:len(key)-len(suffix)], op, true
+		}
+	}
+
+	idx := strings.LastIndex(key, "_")
+	if idx <= 0 || idx == len(key)-1 {
+		return "", "", false
+	}
+	return key[:idx], lower[idx+1:], true
+}